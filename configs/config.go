@@ -23,6 +23,14 @@ const (
 	gBucketPublic  = "GBUCKET_PUBLIC"
 	gBucketUrl     = "GBUCKET_URL"
 
+	mailFilePath    = "MAIL_FILE_PATH"
+	mailMaxAttempts = "MAIL_MAX_ATTEMPTS"
+	mailTransport   = "MAIL_TRANSPORT"
+	smtpHost        = "SMTP_HOST"
+	smtpPassword    = "SMTP_PASSWORD"
+	smtpPort        = "SMTP_PORT"
+	smtpUsername    = "SMTP_USERNAME"
+
 	mjSenderEmail   = "MJ_SENDER_EMAIL"
 	mjSenderName    = "MJ_SENDER_NAME"
 	mjApikeyPrivate = "MJ_APIKEY_PRIVATE"
@@ -35,6 +43,9 @@ const (
 
 	jwtTimeOut = "JWT_TIME_OUT"
 
+	pdfTimeOut      = "PDF_TIME_OUT"
+	wkhtmltopdfPath = "WKHTMLTOPDF_PATH"
+
 	sendWhatsappAPI   = "SEND_WHATSAPP_API"
 	sendWhatsappToken = "SEND_WHATSAPP_TOKEN"
 
@@ -67,6 +78,16 @@ type Config struct {
 	// DB
 	DBConnectionString string
 
+	// Mail transport: mailjet (default), smtp, file or mbox
+	MailTransport string
+	MailFilePath  string
+	// MailMaxAttempts is the number of sends of a queued mail before it is failed
+	MailMaxAttempts int
+	SMTPHost        string
+	SMTPPort        int
+	SMTPUsername    string
+	SMTPPassword    string
+
 	// Mailjet
 	MjSenderEmail   string
 	MjSenderName    string
@@ -86,6 +107,10 @@ type Config struct {
 
 	JwtTimeOut int
 
+	// PDF
+	PdfTimeOut      int
+	WkhtmltopdfPath string
+
 	// WA
 	SendWhatsappAPI   string
 	SendWhatsappToken string
@@ -113,6 +138,16 @@ func getEnvOrDefault(env string, defaultVal string) string {
 	return e
 }
 
+// getOptional gets an optional value of the env file, defaultVal when it is missing or empty
+func getOptional(result map[string]interface{}, key string, defaultVal string) string {
+	v, ok := result[key].(string)
+	if !ok || v == "" {
+		return defaultVal
+	}
+
+	return v
+}
+
 // GetConfiguration , get application configuration based on set environment
 func GetConfiguration() (*Config, error) {
 	if config != nil {
@@ -143,6 +178,21 @@ func GetConfiguration() (*Config, error) {
 		return nil, fmt.Errorf("failed to parse jwt timeout: %v", err)
 	}
 
+	pdfTimeOut, err := strconv.Atoi(getOptional(result, pdfTimeOut, "30"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pdf timeout: %v", err)
+	}
+
+	smtpPort, err := strconv.Atoi(getOptional(result, smtpPort, "587"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse smtp port: %v", err)
+	}
+
+	mailMaxAttempts, err := strconv.Atoi(getOptional(result, mailMaxAttempts, "8"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse mail max attempts: %v", err)
+	}
+
 	activeWorker, err := strconv.Atoi(result[activeWorker].(string))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse active worker: %v", err)
@@ -156,6 +206,14 @@ func GetConfiguration() (*Config, error) {
 
 		DBConnectionString: result[dbConnectionString].(string),
 
+		MailTransport:   getOptional(result, mailTransport, "mailjet"),
+		MailFilePath:    getOptional(result, mailFilePath, ""),
+		MailMaxAttempts: mailMaxAttempts,
+		SMTPHost:        getOptional(result, smtpHost, ""),
+		SMTPPort:        smtpPort,
+		SMTPUsername:    getOptional(result, smtpUsername, ""),
+		SMTPPassword:    getOptional(result, smtpPassword, ""),
+
 		MjSenderEmail:   result[mjSenderEmail].(string),
 		MjSenderName:    result[mjSenderName].(string),
 		MjApikeyPrivate: result[mjApikeyPrivate].(string),
@@ -172,6 +230,9 @@ func GetConfiguration() (*Config, error) {
 
 		JwtTimeOut: jwtTimeOut,
 
+		PdfTimeOut:      pdfTimeOut,
+		WkhtmltopdfPath: getOptional(result, wkhtmltopdfPath, "wkhtmltopdf"),
+
 		SendWhatsappAPI:   result[sendWhatsappAPI].(string),
 		SendWhatsappToken: result[sendWhatsappToken].(string),
 
@@ -0,0 +1,6 @@
+CREATE TABLE user_businesses (
+  user_id VARCHAR(255) NOT NULL,
+  business_id VARCHAR(255) NOT NULL,
+  PRIMARY KEY (user_id, business_id),
+  INDEX index_business_id (business_id)
+);
@@ -0,0 +1,6 @@
+CREATE TABLE user_outlets (
+  user_id VARCHAR(255) NOT NULL,
+  outlet_id VARCHAR(255) NOT NULL,
+  PRIMARY KEY (user_id, outlet_id),
+  INDEX index_outlet_id (outlet_id)
+);
@@ -0,0 +1,12 @@
+CREATE TABLE card_provider_banks (
+  id VARCHAR(255) NOT NULL,
+  card_provider_id VARCHAR(255) NOT NULL,
+  bank_id VARCHAR(255) NOT NULL,
+  created_at DATETIME NULL,
+  created_by INT NULL,
+  updated_at DATETIME NULL,
+  updated_by INT NULL,
+  PRIMARY KEY (id),
+  UNIQUE INDEX index_card_provider_bank (card_provider_id, bank_id),
+  INDEX index_bank_id (bank_id)
+);
@@ -0,0 +1,16 @@
+CREATE TABLE code_sequences (
+  id VARCHAR(255) NOT NULL DEFAULT (UUID()),
+  business_id VARCHAR(255) NOT NULL DEFAULT "",
+  prefix VARCHAR(50) NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  year INT NOT NULL,
+  sequence INT NOT NULL DEFAULT 0,
+  format VARCHAR(255) NOT NULL DEFAULT "{PREFIX}/{YYYY}/{SEQ:6}",
+  reset_yearly TINYINT(1) NOT NULL DEFAULT 1,
+  created_at DATETIME NULL,
+  created_by INT NULL,
+  updated_at DATETIME NULL,
+  updated_by INT NULL,
+  PRIMARY KEY (id),
+  UNIQUE INDEX index_business_prefix_year (business_id, prefix, year)
+);
@@ -0,0 +1 @@
+ALTER TABLE banks ADD COLUMN account_number_pattern VARCHAR(255) NOT NULL DEFAULT "" AFTER name;
@@ -0,0 +1,18 @@
+CREATE TABLE bank_accounts (
+  id VARCHAR(255) NOT NULL,
+  business_id VARCHAR(255) NOT NULL,
+  bank_id VARCHAR(255) NOT NULL,
+  account_number VARCHAR(50) NOT NULL,
+  holder_name VARCHAR(255) NOT NULL,
+  branch VARCHAR(255) NOT NULL DEFAULT "",
+  currency CHAR(3) NOT NULL DEFAULT "IDR",
+  is_default TINYINT(1) NOT NULL DEFAULT 0,
+  status_id VARCHAR(255) DEFAULT "1",
+  created_at DATETIME NULL,
+  created_by INT NULL,
+  updated_at DATETIME NULL,
+  updated_by INT NULL,
+  PRIMARY KEY (id),
+  UNIQUE INDEX index_bank_account_number (business_id, bank_id, account_number),
+  INDEX index_bank_id (bank_id)
+);
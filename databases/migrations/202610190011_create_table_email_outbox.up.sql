@@ -0,0 +1,28 @@
+CREATE TABLE email_outbox (
+  id VARCHAR(255) NOT NULL,
+  business_id VARCHAR(255) NOT NULL DEFAULT "",
+  from_email VARCHAR(255) NOT NULL,
+  from_name VARCHAR(255) NOT NULL DEFAULT "",
+  reply_to VARCHAR(255) NOT NULL DEFAULT "",
+  subject VARCHAR(998) NOT NULL DEFAULT "",
+  html_body MEDIUMTEXT NULL,
+  text_body MEDIUMTEXT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT "pending",
+  attempts INT NOT NULL DEFAULT 0,
+  max_attempts INT NOT NULL DEFAULT 8,
+  next_attempt_at DATETIME NOT NULL,
+  locked_at DATETIME NULL,
+  lock_token VARCHAR(64) NULL,
+  provider VARCHAR(50) NOT NULL DEFAULT "",
+  provider_message_id VARCHAR(255) NOT NULL DEFAULT "",
+  last_error TEXT NULL,
+  sent_at DATETIME NULL,
+  created_at DATETIME NULL,
+  created_by INT NULL,
+  updated_at DATETIME NULL,
+  updated_by INT NULL,
+  PRIMARY KEY (id),
+  INDEX index_email_outbox_due (status, next_attempt_at),
+  INDEX index_email_outbox_lock_token (lock_token),
+  INDEX index_email_outbox_business_id (business_id)
+);
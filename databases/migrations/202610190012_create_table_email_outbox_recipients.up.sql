@@ -0,0 +1,14 @@
+CREATE TABLE email_outbox_recipients (
+  id VARCHAR(255) NOT NULL,
+  outbox_id VARCHAR(255) NOT NULL,
+  kind VARCHAR(3) NOT NULL DEFAULT "to",
+  email VARCHAR(255) NOT NULL,
+  name VARCHAR(255) NOT NULL DEFAULT "",
+  status VARCHAR(20) NOT NULL DEFAULT "pending",
+  provider_message_id VARCHAR(255) NOT NULL DEFAULT "",
+  last_error TEXT NULL,
+  sent_at DATETIME NULL,
+  PRIMARY KEY (id),
+  INDEX index_email_outbox_recipients_outbox_id (outbox_id),
+  INDEX index_email_outbox_recipients_email (email)
+);
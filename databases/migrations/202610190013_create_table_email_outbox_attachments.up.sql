@@ -0,0 +1,10 @@
+CREATE TABLE email_outbox_attachments (
+  id VARCHAR(255) NOT NULL,
+  outbox_id VARCHAR(255) NOT NULL,
+  filename VARCHAR(255) NOT NULL,
+  content_type VARCHAR(255) NOT NULL DEFAULT "",
+  content_id VARCHAR(255) NOT NULL DEFAULT "",
+  content LONGBLOB NOT NULL,
+  PRIMARY KEY (id),
+  INDEX index_email_outbox_attachments_outbox_id (outbox_id)
+);
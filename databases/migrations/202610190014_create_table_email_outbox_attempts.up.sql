@@ -0,0 +1,13 @@
+CREATE TABLE email_outbox_attempts (
+  id VARCHAR(255) NOT NULL,
+  outbox_id VARCHAR(255) NOT NULL,
+  attempt INT NOT NULL,
+  status VARCHAR(20) NOT NULL,
+  provider VARCHAR(50) NOT NULL DEFAULT "",
+  response TEXT NULL,
+  error TEXT NULL,
+  started_at DATETIME NOT NULL,
+  finished_at DATETIME NOT NULL,
+  PRIMARY KEY (id),
+  INDEX index_email_outbox_attempts_outbox_id (outbox_id)
+);
@@ -0,0 +1,19 @@
+CREATE TABLE notification_rules (
+  id VARCHAR(255) NOT NULL,
+  business_id VARCHAR(255) NOT NULL DEFAULT "",
+  name VARCHAR(255) NOT NULL,
+  event VARCHAR(100) NOT NULL,
+  channel VARCHAR(20) NOT NULL,
+  target VARCHAR(255) NOT NULL DEFAULT "user",
+  subject_template TEXT NULL,
+  body_template TEXT NULL,
+  dedup_seconds INT NOT NULL DEFAULT 0,
+  ignore_quiet_hours TINYINT(1) NOT NULL DEFAULT 0,
+  status_id VARCHAR(255) DEFAULT "1",
+  created_at DATETIME NULL,
+  created_by INT NULL,
+  updated_at DATETIME NULL,
+  updated_by INT NULL,
+  PRIMARY KEY (id),
+  INDEX index_notification_rules_event (event, business_id)
+);
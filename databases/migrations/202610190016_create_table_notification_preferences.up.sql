@@ -0,0 +1,14 @@
+CREATE TABLE notification_preferences (
+  id VARCHAR(255) NOT NULL,
+  user_id VARCHAR(255) NOT NULL,
+  event VARCHAR(100) NOT NULL DEFAULT "*",
+  channel VARCHAR(20) NOT NULL DEFAULT "*",
+  address VARCHAR(255) NOT NULL DEFAULT "",
+  enabled TINYINT(1) NOT NULL DEFAULT 1,
+  quiet_start TIME NULL,
+  quiet_end TIME NULL,
+  created_at DATETIME NULL,
+  updated_at DATETIME NULL,
+  PRIMARY KEY (id),
+  UNIQUE INDEX index_notification_preferences_user (user_id, event, channel)
+);
@@ -0,0 +1,29 @@
+CREATE TABLE notifications (
+  id VARCHAR(255) NOT NULL,
+  business_id VARCHAR(255) NOT NULL DEFAULT "",
+  rule_id VARCHAR(255) NOT NULL,
+  event VARCHAR(100) NOT NULL,
+  channel VARCHAR(20) NOT NULL,
+  user_id VARCHAR(255) NOT NULL DEFAULT "",
+  recipient VARCHAR(255) NOT NULL DEFAULT "",
+  subject VARCHAR(998) NOT NULL DEFAULT "",
+  body TEXT NULL,
+  dedup_key CHAR(40) NOT NULL,
+  repeat_count INT NOT NULL DEFAULT 1,
+  status VARCHAR(20) NOT NULL DEFAULT "pending",
+  attempts INT NOT NULL DEFAULT 0,
+  deliver_after DATETIME NOT NULL,
+  lock_token VARCHAR(64) NULL,
+  locked_at DATETIME NULL,
+  last_error TEXT NULL,
+  sent_at DATETIME NULL,
+  read_at DATETIME NULL,
+  created_at DATETIME NULL,
+  updated_at DATETIME NULL,
+  PRIMARY KEY (id),
+  INDEX index_notifications_dedup (dedup_key, created_at),
+  INDEX index_notifications_due (status, deliver_after),
+  INDEX index_notifications_user (user_id, channel),
+  INDEX index_notifications_lock_token (lock_token),
+  INDEX index_notifications_business_id (business_id)
+);
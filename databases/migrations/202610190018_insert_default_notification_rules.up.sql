@@ -0,0 +1,6 @@
+INSERT INTO notification_rules (id, name, event, channel, target, subject_template, body_template, dedup_seconds, ignore_quiet_hours, status_id) VALUES
+  (UUID(), "Error alert to slack", "error.alert", "slack", "", "", "", 300, 1, "1"),
+  (UUID(), "Welcome email", "user.created", "email", "user", "Selamat datang di Luxe", "Halo {{.Name}},\n\nAkun Anda dengan username {{.Username}} sudah dibuat.", 0, 0, "1"),
+  (UUID(), "Welcome in-app", "user.created", "in_app", "user", "Selamat datang", "Akun Anda sudah dibuat.", 0, 0, "1"),
+  (UUID(), "Password reset email", "user.password_reset", "email", "user", "Reset password", "{{.Body}}", 60, 1, "1"),
+  (UUID(), "Payment received whatsapp", "payment.received", "whatsapp", "user", "Pembayaran diterima", "Pembayaran {{.Number}} sebesar {{rupiah .Amount}} sudah kami terima.", 0, 0, "1");
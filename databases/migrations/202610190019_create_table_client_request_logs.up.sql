@@ -0,0 +1,27 @@
+CREATE TABLE client_request_logs (
+  id INT NOT NULL AUTO_INCREMENT,
+  businessId VARCHAR(255) NOT NULL DEFAULT "",
+  clientId INT NOT NULL DEFAULT 0,
+  clientName VARCHAR(100) NOT NULL DEFAULT "",
+  clientType VARCHAR(50) NOT NULL DEFAULT "",
+  transactionId INT NOT NULL DEFAULT 0,
+  correlationId VARCHAR(64) NOT NULL DEFAULT "",
+  method VARCHAR(10) NOT NULL,
+  url TEXT NOT NULL,
+  header TEXT NULL,
+  request MEDIUMTEXT NULL,
+  response MEDIUMTEXT NULL,
+  status VARCHAR(20) NOT NULL,
+  httpStatusCode INT NOT NULL DEFAULT 0,
+  latencyMs INT NOT NULL DEFAULT 0,
+  error TEXT NULL,
+  referenceId INT NOT NULL DEFAULT 0,
+  created_by INT NULL,
+  created_at DATETIME NOT NULL,
+  updated_by INT NULL,
+  updated_at DATETIME NULL,
+  PRIMARY KEY (id),
+  INDEX index_client_request_logs_correlation_id (correlationId),
+  INDEX index_client_request_logs_client_name (clientName, created_at),
+  INDEX index_client_request_logs_business_id (businessId)
+);
@@ -0,0 +1,9 @@
+ALTER TABLE client_request_logs
+  ADD COLUMN isAcknowledgeNeeded TINYINT(1) NOT NULL DEFAULT 0,
+  ADD COLUMN isAcknowledged TINYINT(1) NOT NULL DEFAULT 0,
+  ADD COLUMN idempotencyKey VARCHAR(64) NOT NULL DEFAULT "",
+  ADD COLUMN attempts INT NOT NULL DEFAULT 0,
+  ADD COLUMN nextAttemptAt DATETIME NULL,
+  ADD COLUMN lockToken VARCHAR(64) NULL,
+  ADD COLUMN lockedAt DATETIME NULL,
+  ADD INDEX index_client_request_logs_acknowledge (isAcknowledgeNeeded, isAcknowledged, nextAttemptAt);
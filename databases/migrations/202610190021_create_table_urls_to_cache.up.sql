@@ -0,0 +1,15 @@
+CREATE TABLE urls_to_cache (
+  id INT NOT NULL AUTO_INCREMENT,
+  baseUrl VARCHAR(2048) NOT NULL,
+  method VARCHAR(10) NOT NULL,
+  clientId INT NOT NULL DEFAULT 0,
+  clientName VARCHAR(100) NOT NULL DEFAULT "",
+  bufferedTime INT NOT NULL DEFAULT 0,
+  isBlocked TINYINT(1) NOT NULL DEFAULT 0,
+  created_by INT NULL,
+  created_at DATETIME NOT NULL,
+  updated_by INT NULL,
+  updated_at DATETIME NULL,
+  PRIMARY KEY (id),
+  INDEX index_urls_to_cache_method_base_url (method, baseUrl(255))
+);
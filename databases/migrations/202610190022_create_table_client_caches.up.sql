@@ -0,0 +1,16 @@
+CREATE TABLE client_caches (
+  id INT NOT NULL AUTO_INCREMENT,
+  url VARCHAR(2048) NOT NULL,
+  method VARCHAR(10) NOT NULL,
+  clientId INT NOT NULL DEFAULT 0,
+  clientName VARCHAR(100) NOT NULL DEFAULT "",
+  response MEDIUMTEXT NOT NULL,
+  lastAccessed DATETIME NOT NULL,
+  created_by INT NULL,
+  created_at DATETIME NOT NULL,
+  updated_by INT NULL,
+  updated_at DATETIME NULL,
+  PRIMARY KEY (id),
+  INDEX index_client_caches_method_url (method, url(255)),
+  INDEX index_client_caches_client_name (clientName)
+);
@@ -0,0 +1,9 @@
+// Package html embeds the html templates so they are available without the html directory on disk
+package html
+
+import "embed"
+
+// Files holds the pages, layouts/ and partials/ of the templates
+//
+//go:embed *.html layouts/*.html partials/*.html
+var Files embed.FS
@@ -3,6 +3,7 @@ package appcontext
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -34,6 +35,15 @@ const (
 	// KeyCustomerID represents the current logged-in UserID's CustomerID from customer-payfazz
 	KeyBusinessID contextKey = "BusinessID"
 
+	// KeyBusinessIDs represents the list of business the current logged-in UserID may access
+	KeyBusinessIDs contextKey = "BusinessIDs"
+
+	// KeyOutletIDs represents the list of outlet the current logged-in UserID may access
+	KeyOutletIDs contextKey = "OutletIDs"
+
+	// KeyIsSuperAdmin represents whether the current logged-in UserID may access every business
+	KeyIsSuperAdmin contextKey = "IsSuperAdmin"
+
 	// KeyBusinessShiftID represents the current logged-in UserID's CustomerID from customer-payfazz
 	KeyBusinessShiftID contextKey = "BusinessShiftID"
 
@@ -163,23 +173,64 @@ func Type(ctx *gin.Context) *string {
 }
 
 // OutletID gets current logged-in UserID's OutletID from context
-func OutletID(ctx *gin.Context) int {
-	outletID := ctx.Value(fmt.Sprintf("%v", KeyOutletID))
-	if outletID != nil {
-		v := int(outletID.(float64))
+func OutletID(ctx *gin.Context) string {
+	return contextID(ctx, KeyOutletID)
+}
+
+// BusinessID gets current prefered BusinessID of UserID the way it is stored in the business columns,
+// empty when there is no context or no business
+func BusinessID(ctx *gin.Context) string {
+	return contextID(ctx, KeyBusinessID)
+}
+
+// contextID reads an id set from the token claims, the ids are VARCHAR uuids but older tokens carry them as numbers
+func contextID(ctx *gin.Context, key contextKey) string {
+	if ctx == nil {
+		return ""
+	}
+	switch v := ctx.Value(fmt.Sprintf("%s", key)).(type) {
+	case string:
 		return v
+	case float64:
+		if v == 0 {
+			return ""
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprintf("%v", v)
 	}
-	return 0
 }
 
-// BusinessID gets current prefered BusinessID of UserID
-func BusinessID(ctx *gin.Context) int {
-	businessID := ctx.Value(fmt.Sprintf("%s", KeyBusinessID))
-	if businessID != nil {
-		v := int(businessID.(float64))
+// BusinessIDs gets the list of business the current UserID may access
+func BusinessIDs(ctx *gin.Context) []string {
+	businessIDs := ctx.Value(fmt.Sprintf("%s", KeyBusinessIDs))
+	if businessIDs != nil {
+		v := businessIDs.([]string)
 		return v
 	}
-	return 0
+	return nil
+}
+
+// OutletIDs gets the list of outlet the current UserID may access
+func OutletIDs(ctx *gin.Context) []string {
+	outletIDs := ctx.Value(fmt.Sprintf("%s", KeyOutletIDs))
+	if outletIDs != nil {
+		v := outletIDs.([]string)
+		return v
+	}
+	return nil
+}
+
+// IsSuperAdmin gets whether the current UserID holds the super admin permission
+func IsSuperAdmin(ctx *gin.Context) bool {
+	isSuperAdmin := ctx.Value(fmt.Sprintf("%s", KeyIsSuperAdmin))
+	if isSuperAdmin != nil {
+		v := isSuperAdmin.(bool)
+		return v
+	}
+	return false
 }
 
 // BusinessShiftID gets current prefered BusinessShiftID of UserID
@@ -0,0 +1,292 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"luxe-beb-go/library"
+	"luxe-beb-go/library/appcontext"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// IdempotencyKeyHeader is sent with an acknowledged request, every resend of the request carries the same key
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// DefaultAcknowledgeMaxAttempts is the number of times an acknowledged request is sent before giving up
+const DefaultAcknowledgeMaxAttempts = 8
+
+const acknowledgeBaseDelay = 30 * time.Second
+const acknowledgeMaxDelay = time.Hour
+
+// sendAcknowledged persists the request before sending it with an idempotency key, it is acknowledged on a 2xx response.
+// Until then the AcknowledgeSweeper the client is registered on sends it again
+func (c *HTTPClient) sendAcknowledged(ctx *gin.Context, req *http.Request, body []byte) (string, *ResponseError) {
+	req.Header.Set(IdempotencyKeyHeader, uuid.New().String())
+
+	header, _ := json.Marshal(RedactHeader(req.Header))
+	requestLog := &ClientRequestLog{
+		BusinessID:          appcontext.BusinessID(ctx),
+		ClientID:            c.ClientID,
+		ClientName:          c.ClientName,
+		CorrelationID:       req.Header.Get(CorrelationIDHeader),
+		Method:              req.Method,
+		URL:                 req.URL.String(),
+		Header:              string(header),
+		Request:             string(body),
+		Status:              RequestLogStatusPending,
+		IsAcknowledgeNeeded: true,
+		IdempotencyKey:      req.Header.Get(IdempotencyKeyHeader),
+		NextAttemptAt:       nextAttemptAt(0),
+	}
+
+	requestLog, err := c.clientRequestLogStorage.Insert(detachedContext(ctx), requestLog)
+	if err != nil {
+		return "", &ResponseError{
+			Message: "the request could not be persisted for acknowledgement",
+			Error:   err.Error,
+		}
+	}
+
+	return c.deliver(ctx, req, requestLog)
+}
+
+// deliver sends the persisted request and records the attempt on it
+func (c *HTTPClient) deliver(ctx *gin.Context, req *http.Request, requestLog *ClientRequestLog) (string, *ResponseError) {
+	start := time.Now()
+	response, errDo := c.Do(req)
+	latency := time.Since(start)
+
+	requestLog.Attempts++
+	requestLog.LatencyMs = latency.Milliseconds()
+	requestLog.Response = c.truncateBody(response)
+	requestLog.Error = ""
+	requestLog.HTTPStatusCode = 0
+	if errDo != nil {
+		requestLog.HTTPStatusCode = errDo.StatusCode
+	}
+
+	if errDo != nil && errDo.Error != nil {
+		requestLog.Status = RequestLogStatusFailed
+		requestLog.Error = errDo.Error.Error()
+		requestLog.Response = c.truncateBody(errDo.Body)
+		requestLog.NextAttemptAt = nextAttemptAt(acknowledgeBackoff(requestLog.Attempts))
+		// the request was refused, sending it again gets the same answer so it is never due again
+		if isFinalStatusCode(requestLog.HTTPStatusCode) {
+			requestLog.NextAttemptAt = nil
+		}
+	} else {
+		requestLog.Status = RequestLogStatusSuccess
+		requestLog.IsAcknowledged = true
+	}
+
+	_, err := c.clientRequestLogStorage.Update(detachedContext(ctx), requestLog)
+	if err != nil {
+		log.Printf("client acknowledge: request %d: %v\n", requestLog.ID, err.Error)
+	}
+
+	return response, errDo
+}
+
+// resend builds the persisted request again with the authentication of the client & the same idempotency key
+func (c *HTTPClient) resend(requestLog *ClientRequestLog) *ResponseError {
+	req, err := http.NewRequest(requestLog.Method, requestLog.URL, bytes.NewBufferString(requestLog.Request))
+	if err != nil {
+		return &ResponseError{
+			Error: err,
+		}
+	}
+
+	// the logged header keeps everything but the credentials, which are taken from the client
+	header := http.Header{}
+	if json.Unmarshal([]byte(requestLog.Header), &header) == nil {
+		for name, values := range header {
+			if !isRedactedHeader(name) {
+				req.Header[name] = values
+			}
+		}
+	}
+	for _, authorizationType := range c.AuthorizationTypes {
+		if authorizationType.HeaderType != "APIKey" {
+			req.Header.Set(authorizationType.HeaderName, fmt.Sprintf("%s%s", authorizationType.HeaderTypeValue, authorizationType.Token))
+		}
+	}
+	req.Header.Set(IdempotencyKeyHeader, requestLog.IdempotencyKey)
+
+	_, errDo := c.deliver(nil, req, requestLog)
+	if errDo != nil && errDo.Error != nil {
+		return errDo
+	}
+	return nil
+}
+
+// isFinalStatusCode tells whether the response refuses the request for good, a 4xx but a timeout or a rate limit
+func isFinalStatusCode(statusCode int) bool {
+	switch statusCode {
+	case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests:
+		return false
+	}
+	return statusCode >= 400 && statusCode < 500
+}
+
+func nextAttemptAt(delay time.Duration) *string {
+	at := library.UTCPlus7().Add(delay).Format("2006-01-02 15:04:05")
+	return &at
+}
+
+// acknowledgeBackoff is the wait before the next attempt, doubled on every failure
+func acknowledgeBackoff(attempt int) time.Duration {
+	delay := acknowledgeBaseDelay
+	for i := 1; i < attempt && delay < acknowledgeMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > acknowledgeMaxDelay {
+		delay = acknowledgeMaxDelay
+	}
+	return delay
+}
+
+// AcknowledgeSweeperConfig tunes the sweeper, zero values use the defaults
+type AcknowledgeSweeperConfig struct {
+	// Interval between two sweeps, 30 seconds by default
+	Interval time.Duration
+	// BatchSize is the number of requests claimed per sweep, 20 by default
+	BatchSize int
+	// MaxAttempts is the number of attempts after which a request is left unacknowledged, DefaultAcknowledgeMaxAttempts by default
+	MaxAttempts int
+	// LockTimeout frees a request claimed by a sweeper that died, 10 minutes by default
+	LockTimeout time.Duration
+}
+
+// AcknowledgeSweeper sends the unacknowledged requests again through the client they were first sent with,
+// the clients are registered on it by their ClientName
+type AcknowledgeSweeper struct {
+	db     *sqlx.DB
+	config AcknowledgeSweeperConfig
+
+	mu      sync.RWMutex
+	clients map[string]*HTTPClient
+}
+
+// NewAcknowledgeSweeper creates a sweeper over the client request log
+func NewAcknowledgeSweeper(db *sqlx.DB, config AcknowledgeSweeperConfig) *AcknowledgeSweeper {
+	if config.Interval <= 0 {
+		config.Interval = 30 * time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 20
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultAcknowledgeMaxAttempts
+	}
+	if config.LockTimeout <= 0 {
+		config.LockTimeout = 10 * time.Minute
+	}
+	return &AcknowledgeSweeper{db: db, config: config, clients: map[string]*HTTPClient{}}
+}
+
+// Register adds the client sending the requests logged under its ClientName again.
+// A client without name or named as another registered client is refused, its requests could go to the wrong client
+func (s *AcknowledgeSweeper) Register(c *HTTPClient) error {
+	if c.ClientName == "" {
+		return errors.New("client acknowledge sweeper: the client has no name")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if registered, ok := s.clients[c.ClientName]; ok && registered != c {
+		return fmt.Errorf("client acknowledge sweeper: another client is registered as %q", c.ClientName)
+	}
+	s.clients[c.ClientName] = c
+	return nil
+}
+
+func (s *AcknowledgeSweeper) client(name string) *HTTPClient {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.clients[name]
+}
+
+// Run sweeps until the context is done
+func (s *AcknowledgeSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.SweepDue(ctx); err != nil {
+			log.Printf("client acknowledge sweeper: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SweepDue claims the due unacknowledged requests and sends them, it returns the number of requests tried
+func (s *AcknowledgeSweeper) SweepDue(ctx context.Context) (int, error) {
+	token := uuid.New().String()
+	now := library.UTCPlus7()
+
+	_, err := s.db.ExecContext(ctx, `
+  UPDATE client_request_logs
+  SET lockToken = ?, lockedAt = ?
+  WHERE isAcknowledgeNeeded = 1 AND isAcknowledged = 0 AND attempts < ? AND nextAttemptAt <= ?
+    AND (lockToken IS NULL OR lockedAt < ?)
+  ORDER BY nextAttemptAt
+  LIMIT ?`,
+		token, now,
+		s.config.MaxAttempts, now,
+		now.Add(-s.config.LockTimeout),
+		s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	requestLogs := []*ClientRequestLog{}
+	err = s.db.SelectContext(ctx, &requestLogs, `
+  SELECT
+    id, businessId, clientId, clientName, clientType, transactionId, correlationId, method, url,
+    COALESCE(header, '') AS header,
+    COALESCE(request, '') AS request,
+    COALESCE(response, '') AS response,
+    status, httpStatusCode, latencyMs,
+    COALESCE(error, '') AS error,
+    referenceId, isAcknowledgeNeeded, isAcknowledged, idempotencyKey, attempts,
+    nextAttemptAt, created_at
+  FROM client_request_logs
+  WHERE lockToken = ?`, token)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, requestLog := range requestLogs {
+		c := s.client(requestLog.ClientName)
+		if c == nil || c.clientRequestLogStorage == nil {
+			log.Printf("client acknowledge sweeper: request %d: no client %q to resend it\n", requestLog.ID, requestLog.ClientName)
+		} else if errDo := c.resend(requestLog); errDo != nil {
+			log.Printf("client acknowledge sweeper: request %d: %v\n", requestLog.ID, errDo.Error)
+		}
+
+		_, err = s.db.ExecContext(ctx, `
+  UPDATE client_request_logs SET lockToken = NULL, lockedAt = NULL
+  WHERE id = ? AND lockToken = ?`, requestLog.ID, token)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(requestLogs), nil
+}
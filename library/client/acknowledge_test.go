@@ -0,0 +1,124 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"luxe-beb-go/library/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// memoryRequestLogStorage keeps the client request logs in memory
+type memoryRequestLogStorage struct {
+	mu   sync.Mutex
+	logs map[int]ClientRequestLog
+}
+
+func newMemoryRequestLogStorage() *memoryRequestLogStorage {
+	return &memoryRequestLogStorage{logs: map[int]ClientRequestLog{}}
+}
+
+func (s *memoryRequestLogStorage) FindAll(ctx *gin.Context, params *FindAllClientRequestLogs) ([]*ClientRequestLog, *types.Error) {
+	return nil, nil
+}
+
+func (s *memoryRequestLogStorage) FindByID(ctx *gin.Context, clientRequestLogID int) (*ClientRequestLog, *types.Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	requestLog := s.logs[clientRequestLogID]
+	return &requestLog, nil
+}
+
+func (s *memoryRequestLogStorage) Insert(ctx *gin.Context, clientRequestLog *ClientRequestLog) (*ClientRequestLog, *types.Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clientRequestLog.ID = len(s.logs) + 1
+	s.logs[clientRequestLog.ID] = *clientRequestLog
+	return clientRequestLog, nil
+}
+
+func (s *memoryRequestLogStorage) Update(ctx *gin.Context, clientRequestLog *ClientRequestLog) (*ClientRequestLog, *types.Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logs[clientRequestLog.ID] = *clientRequestLog
+	return clientRequestLog, nil
+}
+
+func (s *memoryRequestLogStorage) Delete(ctx *gin.Context, clientRequestLogID int) *types.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.logs, clientRequestLogID)
+	return nil
+}
+
+func TestAcknowledgedRequest(t *testing.T) {
+	cases := []struct {
+		name           string
+		statusCode     int
+		wantStatus     string
+		isAcknowledged bool
+		isDueAgain     bool
+	}{
+		{"success", http.StatusOK, RequestLogStatusSuccess, true, false},
+		{"unavailable", http.StatusServiceUnavailable, RequestLogStatusFailed, false, true},
+		{"rate limited", http.StatusTooManyRequests, RequestLogStatusFailed, false, true},
+		{"server error", http.StatusInternalServerError, RequestLogStatusFailed, false, true},
+		{"refused", http.StatusUnprocessableEntity, RequestLogStatusFailed, false, false},
+		{"not found", http.StatusNotFound, RequestLogStatusFailed, false, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var idempotencyKey string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				idempotencyKey = r.Header.Get(IdempotencyKeyHeader)
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			storage := newMemoryRequestLogStorage()
+			c := NewHTTPClient(HTTPClient{APIURL: server.URL, HTTPClient: server.Client(), ClientName: "partner"})
+			c.SetClientRequestLogStorage(storage)
+
+			c.CallClient(newTestContext(), "payments", POST, map[string]int{"amount": 1000}, nil, true)
+
+			requestLog, _ := storage.FindByID(nil, 1)
+			if requestLog.Status != tc.wantStatus || requestLog.IsAcknowledged != tc.isAcknowledged || requestLog.HTTPStatusCode != tc.statusCode {
+				t.Errorf("request log = %s acknowledged %v status code %d, want %s acknowledged %v status code %d",
+					requestLog.Status, requestLog.IsAcknowledged, requestLog.HTTPStatusCode, tc.wantStatus, tc.isAcknowledged, tc.statusCode)
+			}
+			if requestLog.Attempts != 1 || requestLog.IdempotencyKey == "" || requestLog.IdempotencyKey != idempotencyKey {
+				t.Errorf("request log attempts %d key %q, want one attempt with the key %q sent", requestLog.Attempts, requestLog.IdempotencyKey, idempotencyKey)
+			}
+			if isDueAgain := requestLog.NextAttemptAt != nil; !tc.isAcknowledged && isDueAgain != tc.isDueAgain {
+				t.Errorf("request log due again = %v, want %v", isDueAgain, tc.isDueAgain)
+			}
+		})
+	}
+}
+
+func TestAcknowledgeSweeperRegister(t *testing.T) {
+	sweeper := NewAcknowledgeSweeper(nil, AcknowledgeSweeperConfig{})
+
+	partner := NewHTTPClient(HTTPClient{ClientName: "partner"})
+	if err := sweeper.Register(partner); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := sweeper.Register(partner); err != nil {
+		t.Errorf("registering the same client again: %v", err)
+	}
+	if err := sweeper.Register(NewHTTPClient(HTTPClient{ClientName: "partner"})); err == nil {
+		t.Error("another client registered under the name of a registered client")
+	}
+	if err := sweeper.Register(NewHTTPClient(HTTPClient{})); err == nil {
+		t.Error("a client without name registered")
+	}
+
+	if sweeper.client("partner") != partner {
+		t.Error("the sweeper does not resend through the registered client")
+	}
+}
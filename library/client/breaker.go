@@ -0,0 +1,139 @@
+package client
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling the client while the circuit of its host is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// Enum value for circuit breaker state
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half-open"
+)
+
+const defaultFailureThreshold = 5
+const defaultOpenTimeout = 30 * time.Second
+
+// CircuitBreakerConfig represents the config of the circuit breaker of a host
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of failures in a row opening the circuit
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before a single call is let through to probe the host
+	OpenTimeout time.Duration
+}
+
+// CircuitBreaker keeps the state of the calls to one host
+type CircuitBreaker struct {
+	mu       sync.Mutex
+	config   CircuitBreakerConfig
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker creates a closed circuit breaker
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{config: config.withDefaults(), state: CircuitClosed}
+}
+
+// withDefaults returns the config with the defaults in place of its zero values
+func (config CircuitBreakerConfig) withDefaults() CircuitBreakerConfig {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = defaultFailureThreshold
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = defaultOpenTimeout
+	}
+	return config
+}
+
+// Allow tells whether a call may be made, once the open timeout passed only one probing call is allowed at a time
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.config.OpenTimeout {
+			return false
+		}
+		b.state = CircuitHalfOpen
+		b.probing = true
+		return true
+	case CircuitHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	}
+
+	return true
+}
+
+// Success closes the circuit
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = CircuitClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// Failure counts a failed call, the circuit opens again when the probing call fails
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == CircuitHalfOpen || b.failures >= b.config.FailureThreshold {
+		b.state = CircuitOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// State returns the current state of the circuit
+func (b *CircuitBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.config.OpenTimeout {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// breakerKey identifies a circuit breaker by its host and its config
+type breakerKey struct {
+	host   string
+	config CircuitBreakerConfig
+}
+
+// breakers are shared by every client calling the same host with the same config
+var breakers = struct {
+	sync.Mutex
+	hosts map[breakerKey]*CircuitBreaker
+}{hosts: map[breakerKey]*CircuitBreaker{}}
+
+// HostCircuitBreaker returns the circuit breaker of the host for the config, created on first use.
+// The clients of a host sharing a config share its state, a client with another config has a breaker of its own
+func HostCircuitBreaker(host string, config CircuitBreakerConfig) *CircuitBreaker {
+	breakers.Lock()
+	defer breakers.Unlock()
+
+	key := breakerKey{host: host, config: config.withDefaults()}
+	breaker, ok := breakers.hosts[key]
+	if !ok {
+		breaker = NewCircuitBreaker(config)
+		breakers.hosts[key] = breaker
+	}
+	return breaker
+}
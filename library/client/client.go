@@ -2,12 +2,16 @@ package client
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"math/rand"
+	"log"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -39,6 +43,8 @@ type ResponseError struct {
 	Fields     types.Metadata `json:"-"`
 	StatusCode int            `json:"statusCode"`
 	Error      error          `json:"error"`
+	// Body is the raw body of a response outside 2xx
+	Body string `json:"-"`
 }
 
 // AuthorizationTypeStruct represents struct of Authorization Type
@@ -104,60 +110,19 @@ type HTTPClient struct {
 	MaxNetworkRetries       int
 	UseNormalSleep          bool
 	AuthorizationTypes      []AuthorizationType
+	ClientID                int
 	ClientName              string
+	CircuitBreaker          CircuitBreakerConfig
+	// MaxLogBodySize is the size the bodies are cut to in the client request log, -1 logs them whole
+	MaxLogBodySize int
+	// RetryPolicy replaces MaxNetworkRetries & UseNormalSleep when given
+	RetryPolicy *RetryPolicy
 }
 
-func (c *HTTPClient) shouldRetry(err error, res *http.Response, retry int) bool {
-	if retry >= c.MaxNetworkRetries {
-		return false
-	}
-
-	if err != nil {
-		return true
-	}
-
-	return false
-}
-
-func (c *HTTPClient) sleepTime(numRetries int) time.Duration {
-	if c.UseNormalSleep {
-		return 0
-	}
-
-	// exponentially backoff by 2^numOfRetries
-	delay := minNetworkRetriesDelay + minNetworkRetriesDelay*time.Duration(1<<uint(numRetries))
-	if delay > maxNetworkRetriesDelay {
-		delay = maxNetworkRetriesDelay
-	}
-
-	// generate random jitter to prevent thundering herd problem
-	jitter := rand.Int63n(int64(delay / 4))
-	delay -= time.Duration(jitter)
-
-	if delay < minNetworkRetriesDelay {
-		delay = minNetworkRetriesDelay
-	}
-
-	return delay
-}
-
-// Do calls the api http request and parse the response into v
+// Do calls the api http request and parse the response into v.
+// The request is sent again as the retry policy of the client allows, see RetryPolicy
 func (c *HTTPClient) Do(req *http.Request) (string, *ResponseError) {
-	var res *http.Response
-	var err error
-
-	for retry := 0; ; {
-		res, err = c.HTTPClient.Do(req)
-
-		if !c.shouldRetry(err, res, retry) {
-			break
-		}
-
-		sleepDuration := c.sleepTime(retry)
-		retry++
-
-		time.Sleep(sleepDuration)
-	}
+	res, done, err := c.roundTrip(req)
 	if err != nil {
 		return "", &ResponseError{
 			Code:    "",
@@ -166,13 +131,12 @@ func (c *HTTPClient) Do(req *http.Request) (string, *ResponseError) {
 			Error:   err,
 		}
 	}
-	defer res.Body.Close()
+	defer done()
 
 	resBody, err := ioutil.ReadAll(res.Body)
-	res.Body.Close()
 	if err != nil {
 		return "", &ResponseError{
-			Code:       string(res.StatusCode),
+			Code:       strconv.Itoa(res.StatusCode),
 			Message:    "",
 			Fields:     nil,
 			StatusCode: res.StatusCode,
@@ -181,7 +145,7 @@ func (c *HTTPClient) Do(req *http.Request) (string, *ResponseError) {
 	}
 
 	errResponse := &ResponseError{
-		Code:       string(res.StatusCode),
+		Code:       strconv.Itoa(res.StatusCode),
 		Message:    "",
 		Fields:     nil,
 		StatusCode: res.StatusCode,
@@ -193,6 +157,7 @@ func (c *HTTPClient) Do(req *http.Request) (string, *ResponseError) {
 			errResponse.Error = err
 		}
 		errResponse.Error = fmt.Errorf("Error while calling %s: %v", req.URL.String(), errResponse.Message)
+		errResponse.Body = string(resBody)
 
 		return "", errResponse
 	}
@@ -200,8 +165,204 @@ func (c *HTTPClient) Do(req *http.Request) (string, *ResponseError) {
 	return string(resBody), errResponse
 }
 
+// roundTrip sends the request as the retry policy allows and returns the last response unread,
+// done closes it and frees the timeouts of the request
+func (c *HTTPClient) roundTrip(req *http.Request) (*http.Response, func(), error) {
+	var res *http.Response
+	var err error
+
+	policy := c.retryPolicy()
+
+	ctx := req.Context()
+	cancelTotal := context.CancelFunc(func() {})
+	if policy.TotalTimeout > 0 {
+		ctx, cancelTotal = context.WithTimeout(ctx, policy.TotalTimeout)
+	}
+
+	var cancelAttempt context.CancelFunc
+	done := func() {
+		if res != nil {
+			res.Body.Close()
+		}
+		cancelAttempt()
+		cancelTotal()
+	}
+
+	for retry := 0; ; {
+		res, cancelAttempt, err = c.attempt(ctx, req, policy.AttemptTimeout)
+
+		if ctx.Err() != nil || !policy.shouldRetry(req, res, err, retry) {
+			break
+		}
+
+		sleepDuration := policy.delay(retry, res)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(sleepDuration).After(deadline) {
+			break
+		}
+
+		// the response is dropped for the next attempt
+		if res != nil {
+			ioutil.ReadAll(res.Body)
+			res.Body.Close()
+			res = nil
+		}
+		cancelAttempt()
+		retry++
+
+		timer := time.NewTimer(sleepDuration)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			done()
+			return nil, nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		// the body was read by the failed call, it is sent again from the start
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				done()
+				return nil, nil, err
+			}
+		}
+	}
+	if err != nil {
+		done()
+		return nil, nil, err
+	}
+
+	return res, done, nil
+}
+
+// attempt sends the request once, cancel frees the timeout of the attempt once its response is read
+func (c *HTTPClient) attempt(ctx context.Context, req *http.Request, timeout time.Duration) (*http.Response, context.CancelFunc, error) {
+	cancel := context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+
+	res, err := c.HTTPClient.Do(req.WithContext(ctx))
+	return res, cancel, err
+}
+
 // CallClient do call client
 func (c *HTTPClient) CallClient(ctx *gin.Context, path string, method Method, request interface{}, result interface{}, isAcknowledgeNeeded bool) *ResponseError {
+	return c.call(ctx, fmt.Sprintf("%s/%s", c.APIURL, path), method, request, result, callOptions{isAcknowledgeNeeded: isAcknowledgeNeeded})
+}
+
+// CallClientWithoutLog do call client without writing it to the client request log
+func (c *HTTPClient) CallClientWithoutLog(ctx *gin.Context, path string, method Method, request interface{}, result interface{}, isAcknowledgeNeeded bool) *ResponseError {
+	return c.call(ctx, fmt.Sprintf("%s/%s", c.APIURL, path), method, request, result, callOptions{isWithoutLog: true, isAcknowledgeNeeded: isAcknowledgeNeeded})
+}
+
+// CallClientWithBaseURLGiven do call client on the given url instead of the api url of the client
+func (c *HTTPClient) CallClientWithBaseURLGiven(ctx *gin.Context, url string, method Method, request interface{}, result interface{}, isAcknowledgeNeeded bool) *ResponseError {
+	return c.call(ctx, url, method, request, result, callOptions{isAcknowledgeNeeded: isAcknowledgeNeeded})
+}
+
+// CallClientWithCustomizedError do call client with the query params added to the path,
+// the body of an error response is decoded into result so the caller can read the error of the client
+func (c *HTTPClient) CallClientWithCustomizedError(ctx *gin.Context, path string, method Method, queryParams interface{}, request interface{}, result interface{}, isAcknowledgeNeeded bool) *ResponseError {
+	return c.call(ctx, c.pathWithQuery(path, queryParams), method, request, result, callOptions{isCustomizedError: true, isAcknowledgeNeeded: isAcknowledgeNeeded})
+}
+
+// CallClientWithCustomizedErrorAndCaching is CallClientWithCustomizedError answered from the client cache when the url is cached
+func (c *HTTPClient) CallClientWithCustomizedErrorAndCaching(ctx *gin.Context, path string, method Method, queryParams interface{}, request interface{}, result interface{}, isAcknowledgeNeeded bool) *ResponseError {
+	rawURL := c.pathWithQuery(path, queryParams)
+	return c.withCaching(ctx, rawURL, method, result, func() *ResponseError {
+		return c.call(ctx, rawURL, method, request, result, callOptions{isCustomizedError: true, isAcknowledgeNeeded: isAcknowledgeNeeded})
+	})
+}
+
+// CallClientWithCaching do call client, the response is kept in the client cache when the url is set to be cached
+// and is given back from there until its buffered time passed
+func (c *HTTPClient) CallClientWithCaching(ctx *gin.Context, path string, method Method, request interface{}, result interface{}, isAcknowledgeNeeded bool) *ResponseError {
+	rawURL := fmt.Sprintf("%s/%s", c.APIURL, path)
+	return c.withCaching(ctx, rawURL, method, result, func() *ResponseError {
+		return c.call(ctx, rawURL, method, request, result, callOptions{isAcknowledgeNeeded: isAcknowledgeNeeded})
+	})
+}
+
+// CallClientWithCachingInRedis do call client, a successful response is kept in redis for durationInSecond
+func (c *HTTPClient) CallClientWithCachingInRedis(ctx *gin.Context, durationInSecond int, path string, method Method, request interface{}, result interface{}, isAcknowledgeNeeded bool) *ResponseError {
+	rawURL := fmt.Sprintf("%s/%s", c.APIURL, path)
+	if c.redisClient == nil || durationInSecond <= 0 {
+		return c.call(ctx, rawURL, method, request, result, callOptions{isAcknowledgeNeeded: isAcknowledgeNeeded})
+	}
+
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return &ResponseError{
+			Error: err,
+		}
+	}
+	hash := sha1.Sum(jsonData)
+	key := fmt.Sprintf("client-cache:%s:%s:%s", method, rawURL, hex.EncodeToString(hash[:]))
+
+	cached, err := c.redisClient.Get(key).Result()
+	if err == nil {
+		if result == nil || json.Unmarshal([]byte(cached), result) == nil {
+			return &ResponseError{StatusCode: http.StatusOK}
+		}
+	} else if err != redis.Nil {
+		log.Printf("client cache: %s: %v\n", key, err)
+	}
+
+	errDo := c.call(ctx, rawURL, method, request, result, callOptions{isAcknowledgeNeeded: isAcknowledgeNeeded})
+	if errDo != nil && (errDo.Error != nil || errDo.Message != "") {
+		return errDo
+	}
+
+	response, err := json.Marshal(result)
+	if err == nil {
+		err = c.redisClient.Set(key, response, time.Duration(durationInSecond)*time.Second).Err()
+	}
+	if err != nil {
+		log.Printf("client cache: %s: %v\n", key, err)
+	}
+
+	return errDo
+}
+
+// CallClientWithCircuitBreaker do call client through the circuit breaker of its host,
+// while the circuit is open the call fails right away with ErrCircuitOpen
+func (c *HTTPClient) CallClientWithCircuitBreaker(ctx *gin.Context, path string, method Method, request interface{}, result interface{}, isAcknowledgeNeeded bool) *ResponseError {
+	rawURL := fmt.Sprintf("%s/%s", c.APIURL, path)
+	urlPath, err := url.Parse(rawURL)
+	if err != nil {
+		return &ResponseError{
+			Error: err,
+		}
+	}
+
+	breaker := HostCircuitBreaker(urlPath.Host, c.CircuitBreaker)
+	if !breaker.Allow() {
+		return &ResponseError{
+			Message:    ErrCircuitOpen.Error(),
+			StatusCode: http.StatusServiceUnavailable,
+			Error:      ErrCircuitOpen,
+		}
+	}
+
+	errDo := c.call(ctx, rawURL, method, request, result, callOptions{isAcknowledgeNeeded: isAcknowledgeNeeded})
+	if errDo != nil && errDo.Error != nil && (errDo.StatusCode == 0 || errDo.StatusCode >= 500) {
+		breaker.Failure()
+	} else {
+		breaker.Success()
+	}
+
+	return errDo
+}
+
+// callOptions changes how call handles the request
+type callOptions struct {
+	isCustomizedError   bool
+	isWithoutLog        bool
+	isAcknowledgeNeeded bool
+}
+
+// call sends the request as json to the url and decodes the response into result
+func (c *HTTPClient) call(ctx *gin.Context, rawURL string, method Method, request interface{}, result interface{}, options callOptions) *ResponseError {
 	var jsonData []byte
 	var err error
 	var response string
@@ -217,7 +378,7 @@ func (c *HTTPClient) CallClient(ctx *gin.Context, path string, method Method, re
 		}
 	}
 
-	urlPath, err := url.Parse(fmt.Sprintf("%s/%s", c.APIURL, path))
+	urlPath, err := url.Parse(rawURL)
 	if err != nil {
 		errDo = &ResponseError{
 			Error: err,
@@ -225,7 +386,7 @@ func (c *HTTPClient) CallClient(ctx *gin.Context, path string, method Method, re
 		return errDo
 	}
 
-	req, err := http.NewRequest(string(method), urlPath.String(), bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(requestContext(ctx), string(method), urlPath.String(), bytes.NewBuffer(jsonData))
 	if err != nil {
 		errDo = &ResponseError{
 			Error: err,
@@ -241,8 +402,11 @@ func (c *HTTPClient) CallClient(ctx *gin.Context, path string, method Method, re
 
 	req.Header.Add("Content-Type", "application/json")
 
-	response, errDo = c.Do(req)
+	response, errDo = c.send(ctx, req, jsonData, !options.isWithoutLog, options.isAcknowledgeNeeded)
 	if errDo != nil && (errDo.Error != nil || errDo.Message != "") {
+		if options.isCustomizedError && errDo.Body != "" && result != nil {
+			json.Unmarshal([]byte(errDo.Body), result)
+		}
 		return errDo
 	}
 
@@ -259,6 +423,96 @@ func (c *HTTPClient) CallClient(ctx *gin.Context, path string, method Method, re
 	return errDo
 }
 
+// send does the request with the correlation id of ctx and writes it to the client request log.
+// A request needing acknowledgement is persisted first, see sendAcknowledged
+func (c *HTTPClient) send(ctx *gin.Context, req *http.Request, body []byte, isLogged bool, isAcknowledgeNeeded bool) (string, *ResponseError) {
+	req.Header.Set(CorrelationIDHeader, CorrelationID(ctx))
+
+	if isAcknowledgeNeeded && c.clientRequestLogStorage != nil {
+		return c.sendAcknowledged(ctx, req, body)
+	}
+
+	start := time.Now()
+	response, errDo := c.Do(req)
+	if isLogged {
+		c.logRequest(ctx, req, body, response, errDo, time.Since(start))
+	}
+
+	return response, errDo
+}
+
+// withCaching answers from the client cache when the url is set to be cached, otherwise calls the client
+// and keeps its response. A failing cache never fails the call, it is only logged
+func (c *HTTPClient) withCaching(ctx *gin.Context, rawURL string, method Method, result interface{}, call func() *ResponseError) *ResponseError {
+	if c.clientCacheService == nil {
+		return call()
+	}
+
+	// the cache is kept whatever happens to the transaction of the caller
+	cacheCtx := detachedContext(ctx)
+
+	isNeeded, err := c.clientCacheService.IsClientNeedToBeCache(cacheCtx, rawURL, string(method))
+	if err != nil {
+		log.Printf("client cache: %s %s: %v\n", method, rawURL, err.Error)
+	}
+	if !isNeeded {
+		return call()
+	}
+
+	clientCache, err := c.clientCacheService.GetClientCacheByURL(cacheCtx, &GetClientCacheByURLParams{URL: rawURL, Method: string(method), IsActive: true})
+	if err == nil && clientCache != nil {
+		response, errMarshal := json.Marshal(clientCache.Response)
+		if errMarshal == nil && (result == nil || json.Unmarshal(response, result) == nil) {
+			return &ResponseError{StatusCode: http.StatusOK}
+		}
+	}
+
+	errDo := call()
+	if errDo != nil && (errDo.Error != nil || errDo.Message != "") {
+		return errDo
+	}
+
+	// only an object response can be kept in the cache
+	var response types.Metadata
+	jsonData, errMarshal := json.Marshal(result)
+	if errMarshal != nil || json.Unmarshal(jsonData, &response) != nil {
+		return errDo
+	}
+
+	expired, err := c.clientCacheService.GetClientCacheByURL(cacheCtx, &GetClientCacheByURLParams{URL: rawURL, Method: string(method)})
+	if err == nil && expired != nil {
+		_, err = c.clientCacheService.UpdateClientCache(cacheCtx, expired.ID, &UpdateClientCacheParams{
+			URL:        rawURL,
+			Method:     string(method),
+			ClientID:   c.ClientID,
+			ClientName: c.ClientName,
+			Response:   response,
+		})
+	} else {
+		_, err = c.clientCacheService.CreateClientCache(cacheCtx, &CreateClientCacheParams{
+			URL:        rawURL,
+			Method:     string(method),
+			ClientID:   c.ClientID,
+			ClientName: c.ClientName,
+			Response:   response,
+		})
+	}
+	if err != nil {
+		log.Printf("client cache: %s %s: %v\n", method, rawURL, err.Error)
+	}
+
+	return errDo
+}
+
+// pathWithQuery returns the url of the path with the fields of queryParams added as query string
+func (c *HTTPClient) pathWithQuery(path string, queryParams interface{}) string {
+	rawURL := fmt.Sprintf("%s/%s", c.APIURL, path)
+	if queryParams == nil {
+		return rawURL
+	}
+	return ParseQueryParams(rawURL, queryParams)
+}
+
 func (c *HTTPClient) CallClientFormEncode(ctx *gin.Context, path string, method Method, request url.Values, result interface{}, isAcknowledgeNeeded bool) *ResponseError {
 	var response string
 	var errDo *ResponseError
@@ -271,7 +525,7 @@ func (c *HTTPClient) CallClientFormEncode(ctx *gin.Context, path string, method
 		return errDo
 	}
 
-	req, err := http.NewRequest(string(method), urlPath.String(), strings.NewReader(request.Encode()))
+	req, err := http.NewRequestWithContext(requestContext(ctx), string(method), urlPath.String(), strings.NewReader(request.Encode()))
 	if err != nil {
 		errDo = &ResponseError{
 			Error: err,
@@ -287,7 +541,7 @@ func (c *HTTPClient) CallClientFormEncode(ctx *gin.Context, path string, method
 
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 
-	response, errDo = c.Do(req)
+	response, errDo = c.send(ctx, req, []byte(request.Encode()), true, isAcknowledgeNeeded)
 	if errDo != nil && (errDo.Error != nil || errDo.Message != "") {
 		return errDo
 	}
@@ -320,6 +574,24 @@ func (c *HTTPClient) AddAuthentication(ctx *gin.Context, authorizationType Autho
 	}
 }
 
+// SetClientCacheService sets the client cache used by CallClientWithCaching
+func (c *HTTPClient) SetClientCacheService(clientCacheService ClientCacheServiceInterface) *HTTPClient {
+	c.clientCacheService = clientCacheService
+	return c
+}
+
+// SetClientRequestLogStorage sets the storage the requests are logged to, nil stops the logging
+func (c *HTTPClient) SetClientRequestLogStorage(clientRequestLogStorage ClientRequestLogStorage) *HTTPClient {
+	c.clientRequestLogStorage = clientRequestLogStorage
+	return c
+}
+
+// SetRedisClient sets the redis used by CallClientWithCachingInRedis
+func (c *HTTPClient) SetRedisClient(redisClient *redis.Client) *HTTPClient {
+	c.redisClient = redisClient
+	return c
+}
+
 // NewHTTPClient creates the new http client
 func NewHTTPClient(
 	config HTTPClient,
@@ -332,12 +604,30 @@ func NewHTTPClient(
 		config.APIURL = apiURL
 	}
 
-	return &HTTPClient{
-		APIURL:             config.APIURL,
-		HTTPClient:         config.HTTPClient,
-		MaxNetworkRetries:  config.MaxNetworkRetries,
-		UseNormalSleep:     config.UseNormalSleep,
-		AuthorizationTypes: config.AuthorizationTypes,
-		ClientName:         config.ClientName,
+	if config.clientRequestLogStorage == nil {
+		config.clientRequestLogStorage = requestLogStorage()
 	}
+
+	if config.clientCacheService == nil {
+		config.clientCacheService = clientCacheService()
+	}
+
+	c := &HTTPClient{
+		clientRequestLogStorage: config.clientRequestLogStorage,
+		clientCacheService:      config.clientCacheService,
+		redisClient:             config.redisClient,
+		APIURL:                  config.APIURL,
+		HTTPClient:              config.HTTPClient,
+		MaxNetworkRetries:       config.MaxNetworkRetries,
+		UseNormalSleep:          config.UseNormalSleep,
+		AuthorizationTypes:      config.AuthorizationTypes,
+		ClientID:                config.ClientID,
+		ClientName:              config.ClientName,
+		CircuitBreaker:          config.CircuitBreaker,
+		MaxLogBodySize:          config.MaxLogBodySize,
+		RetryPolicy:             config.RetryPolicy,
+	}
+	return c
 }
+
+var _ GenericHTTPClient = (*HTTPClient)(nil)
@@ -1,26 +1,27 @@
 package client
 
 import (
-	"reflect"
-	"strings"
+	"net/http"
+	"sync"
 	"time"
 
+	"luxe-beb-go/library"
 	"luxe-beb-go/library/types"
+	"luxe-beb-go/library/validator"
 
 	"github.com/gin-gonic/gin"
-	validator "gopkg.in/go-playground/validator.v9"
 )
 
 // ClientCache object of ClientCache to reflect the url-to-cache data for response purpose
 // swagger:model
 type ClientCache struct {
-	ID           int            `json:"-" db:"id"`
+	ID           int            `json:"id" db:"id"`
 	URL          string         `json:"url" db:"url"`
 	Method       string         `json:"method" db:"method"`
 	ClientID     int            `json:"clientId" db:"clientId"`
 	ClientName   string         `json:"clientName" db:"clientName"`
 	Response     types.Metadata `json:"response" db:"response"`
-	LastAccessed time.Time      `json:"lastAccessed" db:"lastAccessed"`
+	LastAccessed string         `json:"lastAccessed" db:"lastAccessed"`
 }
 
 // CreateClientCacheParams represent the http request data for create ClientCache
@@ -60,6 +61,7 @@ type FindAllClientCachesParams struct {
 	Limit      int    `json:"limit"`
 	Method     string `json:"method"`
 	ClientName string `json:"clientName"`
+	URL        string `json:"url"`
 }
 
 // ClientCacheStorage represents the interface for manage ClientCache object
@@ -70,6 +72,7 @@ type ClientCacheStorage interface {
 	Insert(ctx *gin.Context, clientCache *ClientCache) (*ClientCache, *types.Error)
 	Update(ctx *gin.Context, clientCache *ClientCache) (*ClientCache, *types.Error)
 	Delete(ctx *gin.Context, clientCache *ClientCache) *types.Error
+	Purge(ctx *gin.Context, params *FindAllClientCachesParams) (int, *types.Error)
 }
 
 // ClientCacheServiceInterface represents the interface for servicing ClientCache object
@@ -80,6 +83,7 @@ type ClientCacheServiceInterface interface {
 	CreateClientCache(ctx *gin.Context, params *CreateClientCacheParams) (*ClientCache, *types.Error)
 	UpdateClientCache(ctx *gin.Context, clientCache int, params *UpdateClientCacheParams) (*ClientCache, *types.Error)
 	DeleteClientCache(ctx *gin.Context, clientCacheID int) *types.Error
+	PurgeClientCaches(ctx *gin.Context, params *FindAllClientCachesParams) (int, *types.Error)
 	IsClientNeedToBeCache(ctx *gin.Context, url string, method string) (bool, *types.Error)
 	GetClientCacheByURL(ctx *gin.Context, params *GetClientCacheByURLParams) (*ClientCache, *types.Error)
 }
@@ -175,22 +179,14 @@ func (s *ClientCacheService) ListClientCaches(ctx *gin.Context, params *FindAllC
 
 // CreateClientCache creates a new ClientCache
 func (s *ClientCacheService) CreateClientCache(ctx *gin.Context, params *CreateClientCacheParams) (*ClientCache, *types.Error) {
-	validate := validator.New()
-	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
-		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
-		if name == "-" {
-			return ""
-		}
-		return name
-	})
-
-	errValidation := validate.Struct(params)
+	errValidation := validator.Shared().StructCtx(ctx, params)
 	if errValidation != nil {
 		return nil, &types.Error{
-			Path:    ".ClientCacheService->CreateClientCache()",
-			Message: errValidation.Error(),
-			Error:   errValidation,
-			Type:    "validation-error",
+			Path:       ".ClientCacheService->CreateClientCache()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
 		}
 	}
 
@@ -199,7 +195,7 @@ func (s *ClientCacheService) CreateClientCache(ctx *gin.Context, params *CreateC
 		Method:       params.Method,
 		ClientID:     params.ClientID,
 		ClientName:   params.ClientName,
-		LastAccessed: time.Now().UTC(),
+		LastAccessed: library.UTCPlus7().Format("2006-01-02 15:04:05"),
 		Response:     params.Response,
 	}
 
@@ -214,22 +210,14 @@ func (s *ClientCacheService) CreateClientCache(ctx *gin.Context, params *CreateC
 
 // UpdateClientCache updates a ClientCache
 func (s *ClientCacheService) UpdateClientCache(ctx *gin.Context, clientCacheID int, params *UpdateClientCacheParams) (*ClientCache, *types.Error) {
-	validate := validator.New()
-	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
-		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
-		if name == "-" {
-			return ""
-		}
-		return name
-	})
-
-	errValidation := validate.Struct(params)
+	errValidation := validator.Shared().StructCtx(ctx, params)
 	if errValidation != nil {
 		return nil, &types.Error{
-			Path:    ".ClientCacheService->UpdateClientCache()",
-			Message: errValidation.Error(),
-			Error:   errValidation,
-			Type:    "validation-error",
+			Path:       ".ClientCacheService->UpdateClientCache()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
 		}
 	}
 
@@ -243,7 +231,7 @@ func (s *ClientCacheService) UpdateClientCache(ctx *gin.Context, clientCacheID i
 	clientCache.Method = params.Method
 	clientCache.ClientID = params.ClientID
 	clientCache.ClientName = params.ClientName
-	clientCache.LastAccessed = time.Now().UTC()
+	clientCache.LastAccessed = library.UTCPlus7().Format("2006-01-02 15:04:05")
 	clientCache.Response = params.Response
 
 	clientCache, err = s.clientCacheRepository.Update(ctx, clientCache)
@@ -272,6 +260,36 @@ func (s *ClientCacheService) DeleteClientCache(ctx *gin.Context, clientCacheID i
 	return nil
 }
 
+// PurgeClientCaches deletes the ClientCache matching the method, client name & url, every one when none is given.
+// It returns the number of ClientCache deleted
+func (s *ClientCacheService) PurgeClientCaches(ctx *gin.Context, params *FindAllClientCachesParams) (int, *types.Error) {
+	count, err := s.clientCacheRepository.Purge(ctx, params)
+	if err != nil {
+		err.Path = ".ClientCacheService->PurgeClientCaches()" + err.Path
+		return 0, err
+	}
+
+	return count, nil
+}
+
+var defaultClientCacheService struct {
+	sync.RWMutex
+	service ClientCacheServiceInterface
+}
+
+// RegisterClientCacheService sets the client cache every new http client caches its responses in
+func RegisterClientCacheService(service ClientCacheServiceInterface) {
+	defaultClientCacheService.Lock()
+	defer defaultClientCacheService.Unlock()
+	defaultClientCacheService.service = service
+}
+
+func clientCacheService() ClientCacheServiceInterface {
+	defaultClientCacheService.RLock()
+	defer defaultClientCacheService.RUnlock()
+	return defaultClientCacheService.service
+}
+
 // NewClientCacheService creates new ClientCache service
 func NewClientCacheService(
 	clientCacheRepository ClientCacheStorage,
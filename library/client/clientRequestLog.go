@@ -1,46 +1,236 @@
 package client
 
 import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"luxe-beb-go/library/appcontext"
 	"luxe-beb-go/library/types"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Enum value for client request log status
+const (
+	RequestLogStatusPending = "pending"
+	RequestLogStatusSuccess = "success"
+	RequestLogStatusFailed  = "failed"
 )
 
+// CorrelationIDHeader is the header carrying the correlation id of the incoming request to the client
+const CorrelationIDHeader = "X-Correlation-ID"
+
+// DefaultMaxLogBodySize is the size a logged request or response body is cut to
+const DefaultMaxLogBodySize = 16 * 1024
+
+// redactedHeaders are never logged, headers named after a token, secret, password or key neither
+var redactedHeaders = []string{"authorization", "cookie", "set-cookie", "proxy-authorization", "app_key", "device_id", "fsid", "clientid", "clientsecret"}
+
 // ClientRequest encapsulated object of http client and client request log for acknowledge used
 type ClientRequest struct {
 	Client  *HTTPClient
 	Request *ClientRequestLog
 }
 
+// Resend sends the request again through its client, see AcknowledgeSweeper
+func (r ClientRequest) Resend() *ResponseError {
+	return r.Client.resend(r.Request)
+}
+
 // ClientRequestLog object of client request log (log of request to external client)
 // swagger:model
 type ClientRequestLog struct {
-	ID             int            `json:"id" db:"id"`
-	ClientID       int            `json:"clientId" db:"clientId"`
-	ClientType     string         `json:"clientType" db:"clientType"`
-	TransactionID  int            `json:"transactionId" db:"transactionId"`
-	Method         string         `json:"method" db:"method"`
-	URL            string         `json:"url" db:"url"`
-	Header         string         `json:"header" db:"header"`
-	Request        types.Metadata `json:"request" db:"request"`
-	Status         string         `json:"status" db:"status"`
-	HTTPStatusCode int            `json:"httpStatusCode" db:"httpStatusCode"`
-	ReferenceID    int            `json:"referenceId" db:"referenceId"`
+	ID             int    `json:"id" db:"id"`
+	BusinessID     string `json:"businessId" db:"businessId"`
+	ClientID       int    `json:"clientId" db:"clientId"`
+	ClientName     string `json:"clientName" db:"clientName"`
+	ClientType     string `json:"clientType" db:"clientType"`
+	TransactionID  int    `json:"transactionId" db:"transactionId"`
+	CorrelationID  string `json:"correlationId" db:"correlationId"`
+	Method         string `json:"method" db:"method"`
+	URL            string `json:"url" db:"url"`
+	Header         string `json:"header" db:"header"`
+	Request        string `json:"request" db:"request"`
+	Response       string `json:"response" db:"response"`
+	Status         string `json:"status" db:"status"`
+	HTTPStatusCode int    `json:"httpStatusCode" db:"httpStatusCode"`
+	LatencyMs      int64  `json:"latencyMs" db:"latencyMs"`
+	Error          string `json:"error" db:"error"`
+	ReferenceID    int    `json:"referenceId" db:"referenceId"`
+	// IsAcknowledgeNeeded requests are sent again until IsAcknowledged, refused by a 4xx or out of attempts
+	IsAcknowledgeNeeded bool    `json:"isAcknowledgeNeeded" db:"isAcknowledgeNeeded"`
+	IsAcknowledged      bool    `json:"isAcknowledged" db:"isAcknowledged"`
+	IdempotencyKey      string  `json:"idempotencyKey" db:"idempotencyKey"`
+	Attempts            int     `json:"attempts" db:"attempts"`
+	NextAttemptAt       *string `json:"nextAttemptAt" db:"nextAttemptAt"`
+	CreatedAt           string  `json:"createdAt" db:"created_at"`
 }
 
 // FindAllClientRequestLogs represents params to get All Client Request Logs
 // swagger:model
 type FindAllClientRequestLogs struct {
-	Search string `json:"search"`
-	Page   int    `json:"page"`
-	Limit  int    `json:"limit"`
+	Search        string `json:"search"`
+	Page          int    `json:"page"`
+	Limit         int    `json:"limit"`
+	ClientName    string `json:"clientName"`
+	Status        string `json:"status"`
+	CorrelationID string `json:"correlationId"`
+	// IsUnacknowledged lists the acknowledged requests still waiting for a 2xx
+	IsUnacknowledged bool `json:"isUnacknowledged"`
 }
 
 // ClientRequestLogStorage represents the interface for manage client request log object
 type ClientRequestLogStorage interface {
-	FindAll(ctx *gin.Context, params *FindAllClientRequestLogs) []*ClientRequestLog
-	FindByID(ctx *gin.Context, clientRequestLogID int) *ClientRequestLog
-	Insert(ctx *gin.Context, clientRequestLog *ClientRequestLog) *ClientRequestLog
-	Update(ctx *gin.Context, clientRequestLog *ClientRequestLog) *ClientRequestLog
-	Delete(ctx *gin.Context, clientRequestLogID int)
+	FindAll(ctx *gin.Context, params *FindAllClientRequestLogs) ([]*ClientRequestLog, *types.Error)
+	FindByID(ctx *gin.Context, clientRequestLogID int) (*ClientRequestLog, *types.Error)
+	Insert(ctx *gin.Context, clientRequestLog *ClientRequestLog) (*ClientRequestLog, *types.Error)
+	Update(ctx *gin.Context, clientRequestLog *ClientRequestLog) (*ClientRequestLog, *types.Error)
+	Delete(ctx *gin.Context, clientRequestLogID int) *types.Error
+}
+
+var defaultRequestLogStorage struct {
+	sync.RWMutex
+	storage ClientRequestLogStorage
+}
+
+// RegisterRequestLogStorage sets the storage every new http client logs its requests to.
+// A client created before keeps no log, so it is registered at start up before any client
+func RegisterRequestLogStorage(storage ClientRequestLogStorage) {
+	defaultRequestLogStorage.Lock()
+	defer defaultRequestLogStorage.Unlock()
+	defaultRequestLogStorage.storage = storage
+}
+
+func requestLogStorage() ClientRequestLogStorage {
+	defaultRequestLogStorage.RLock()
+	defer defaultRequestLogStorage.RUnlock()
+	return defaultRequestLogStorage.storage
+}
+
+// CorrelationID returns the correlation id of the incoming request, a new one when it has none
+func CorrelationID(ctx *gin.Context) string {
+	if ctx != nil {
+		if id, ok := ctx.Get(CorrelationIDHeader); ok {
+			return fmt.Sprintf("%v", id)
+		}
+		if ctx.Request != nil {
+			for _, header := range []string{CorrelationIDHeader, "X-Request-ID"} {
+				if id := ctx.GetHeader(header); id != "" {
+					ctx.Set(CorrelationIDHeader, id)
+					return id
+				}
+			}
+		}
+	}
+
+	id := uuid.New().String()
+	if ctx != nil {
+		ctx.Set(CorrelationIDHeader, id)
+	}
+	return id
+}
+
+// logRequest writes the call to the client request log. The log is written outside the transaction
+// of the caller so it is kept when the caller rolls back, a failing log never fails the call
+func (c *HTTPClient) logRequest(ctx *gin.Context, req *http.Request, body []byte, response string, errDo *ResponseError, latency time.Duration) *ClientRequestLog {
+	if c.clientRequestLogStorage == nil {
+		return nil
+	}
+
+	header, _ := json.Marshal(RedactHeader(req.Header))
+	requestLog := &ClientRequestLog{
+		BusinessID:    appcontext.BusinessID(ctx),
+		ClientID:      c.ClientID,
+		ClientName:    c.ClientName,
+		CorrelationID: req.Header.Get(CorrelationIDHeader),
+		Method:        req.Method,
+		URL:           req.URL.String(),
+		Header:        string(header),
+		Request:       c.truncateBody(string(body)),
+		Response:      c.truncateBody(response),
+		Status:        RequestLogStatusSuccess,
+		LatencyMs:     latency.Milliseconds(),
+	}
+	if errDo != nil {
+		requestLog.HTTPStatusCode = errDo.StatusCode
+		if errDo.Error != nil {
+			requestLog.Status = RequestLogStatusFailed
+			requestLog.Error = errDo.Error.Error()
+			requestLog.Response = c.truncateBody(errDo.Body)
+		}
+	}
+
+	requestLog, err := c.clientRequestLogStorage.Insert(detachedContext(ctx), requestLog)
+	if err != nil {
+		log.Printf("client request log: %s %s: %v\n", req.Method, req.URL.String(), err.Error)
+		return nil
+	}
+
+	return requestLog
+}
+
+func (c *HTTPClient) truncateBody(body string) string {
+	limit := c.MaxLogBodySize
+	if limit == 0 {
+		limit = DefaultMaxLogBodySize
+	}
+	if limit < 0 || len(body) <= limit {
+		return body
+	}
+	return fmt.Sprintf("%s...(%d bytes truncated)", body[:limit], len(body)-limit)
+}
+
+// RedactHeader returns the header with the value of the credentials hidden
+func RedactHeader(header http.Header) http.Header {
+	result := http.Header{}
+	for name, values := range header {
+		if isRedactedHeader(name) {
+			result[name] = []string{"[REDACTED]"}
+			continue
+		}
+		result[name] = values
+	}
+	return result
+}
+
+func isRedactedHeader(name string) bool {
+	name = strings.ToLower(name)
+	for _, header := range redactedHeaders {
+		if name == header {
+			return true
+		}
+	}
+	for _, part := range []string{"token", "secret", "password", "key"} {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// detachedContext returns a context with the user of ctx but without its transaction,
+// the system (user id 0) when there is no user. The logs are written by the system whatever
+// the businesses of the user are, they keep the business of ctx in BusinessID.
+func detachedContext(ctx *gin.Context) *gin.Context {
+	userID, userName := "0", "system"
+	if ctx != nil {
+		if v := appcontext.UserID(ctx); v != nil {
+			userID = *v
+		}
+		if v := appcontext.UserName(ctx); v != nil {
+			userName = *v
+		}
+	}
+
+	detached := &gin.Context{}
+	detached.Set(string(appcontext.KeyUserID), userID)
+	detached.Set(string(appcontext.KeyUserName), userName)
+	detached.Set(string(appcontext.KeyIsSuperAdmin), true)
+	return detached
 }
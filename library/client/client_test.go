@@ -0,0 +1,245 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type echoResponse struct {
+	Method string                 `json:"method"`
+	Path   string                 `json:"path"`
+	Body   map[string]interface{} `json:"body"`
+}
+
+func newTestContext() *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	return c
+}
+
+func newTestClient(server *httptest.Server) *HTTPClient {
+	return NewHTTPClient(HTTPClient{APIURL: server.URL, HTTPClient: server.Client(), ClientName: "test"})
+}
+
+func TestCallClientMethods(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := echoResponse{Method: r.Method, Path: r.URL.Path}
+		body, _ := ioutil.ReadAll(r.Body)
+		if len(body) > 0 {
+			json.Unmarshal(body, &res.Body)
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer server.Close()
+
+	c := newTestClient(server)
+	c.AddAuthentication(nil, AuthorizationType{HeaderName: "Authorization", HeaderType: "Bearer", HeaderTypeValue: "Bearer ", Token: "token"})
+
+	for _, method := range []Method{GET, POST, PUT, PATCH, DELETE} {
+		t.Run(string(method), func(t *testing.T) {
+			var request interface{}
+			if method != GET && method != DELETE {
+				request = map[string]interface{}{"name": "BCA"}
+			}
+
+			var result echoResponse
+			errDo := c.CallClient(newTestContext(), "banks/1", method, request, &result, false)
+			if errDo != nil && errDo.Error != nil {
+				t.Fatalf("CallClient: %v", errDo.Error)
+			}
+			if result.Method != string(method) || result.Path != "/banks/1" {
+				t.Errorf("server got %s %s, want %s /banks/1", result.Method, result.Path, method)
+			}
+			if request != nil && result.Body["name"] != "BCA" {
+				t.Errorf("server got body %v, want the request", result.Body)
+			}
+		})
+	}
+}
+
+func TestCallClientErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"code":"INVALID_NAME","message":"name is required"}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(server)
+
+	t.Run("CallClient", func(t *testing.T) {
+		errDo := c.CallClient(newTestContext(), "banks", POST, map[string]string{}, nil, false)
+		if errDo == nil || errDo.Error == nil {
+			t.Fatal("CallClient succeeded, want an error")
+		}
+		if errDo.StatusCode != http.StatusUnprocessableEntity || errDo.Code != "INVALID_NAME" || errDo.Message != "name is required" {
+			t.Errorf("error = %d %s %q, want 422 INVALID_NAME \"name is required\"", errDo.StatusCode, errDo.Code, errDo.Message)
+		}
+	})
+
+	t.Run("CallClientWithCustomizedError", func(t *testing.T) {
+		var result struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+		errDo := c.CallClientWithCustomizedError(newTestContext(), "banks", POST, nil, map[string]string{}, &result, false)
+		if errDo == nil || errDo.StatusCode != http.StatusUnprocessableEntity {
+			t.Fatalf("error = %+v, want 422", errDo)
+		}
+		if result.Code != "INVALID_NAME" || result.Message != "name is required" {
+			t.Errorf("result = %+v, want the error body", result)
+		}
+	})
+
+	t.Run("Send", func(t *testing.T) {
+		_, err := c.Send(newTestContext(), Request{Method: POST, Path: "banks", Body: map[string]string{}})
+
+		var e *Error
+		if !errors.As(err, &e) {
+			t.Fatalf("Send error = %v, want an *Error", err)
+		}
+		if e.StatusCode != http.StatusUnprocessableEntity || e.Code != "INVALID_NAME" || e.Message != "name is required" {
+			t.Errorf("error = %d %s %q, want 422 INVALID_NAME \"name is required\"", e.StatusCode, e.Code, e.Message)
+		}
+		if e.Parsed["code"] != "INVALID_NAME" {
+			t.Errorf("parsed body = %v, want the error body", e.Parsed)
+		}
+	})
+}
+
+func TestCallClientWithCircuitBreaker(t *testing.T) {
+	var calls int32
+	var isDown int32 = 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if atomic.LoadInt32(&isDown) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(server)
+	c.CircuitBreaker = CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: 50 * time.Millisecond}
+
+	for i := 0; i < 2; i++ {
+		if errDo := c.CallClientWithCircuitBreaker(newTestContext(), "health", GET, nil, nil, false); errDo == nil || errDo.StatusCode != http.StatusInternalServerError {
+			t.Fatalf("call %d = %+v, want 500", i, errDo)
+		}
+	}
+
+	errDo := c.CallClientWithCircuitBreaker(newTestContext(), "health", GET, nil, nil, false)
+	if errDo == nil || !errors.Is(errDo.Error, ErrCircuitOpen) {
+		t.Fatalf("call on an open circuit = %+v, want ErrCircuitOpen", errDo)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("server called %d times, want the open circuit to skip it", n)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	atomic.StoreInt32(&isDown, 0)
+
+	errDo = c.CallClientWithCircuitBreaker(newTestContext(), "health", GET, nil, nil, false)
+	if errDo != nil && errDo.Error != nil {
+		t.Fatalf("probing call = %v, want success", errDo.Error)
+	}
+
+	breaker := HostCircuitBreaker(server.Listener.Addr().String(), c.CircuitBreaker)
+	if state := breaker.State(); state != CircuitClosed {
+		t.Errorf("state = %s, want %s once the probing call succeeded", state, CircuitClosed)
+	}
+}
+
+func TestCircuitBreakerProbeFailureReopens(t *testing.T) {
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: 10 * time.Millisecond})
+
+	breaker.Failure()
+	if breaker.Allow() {
+		t.Fatal("open circuit allowed a call")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if !breaker.Allow() {
+		t.Fatal("half-open circuit refused the probing call")
+	}
+	if breaker.Allow() {
+		t.Error("half-open circuit allowed a second call while probing")
+	}
+
+	breaker.Failure()
+	if state := breaker.State(); state != CircuitOpen {
+		t.Errorf("state = %s, want %s after a failed probing call", state, CircuitOpen)
+	}
+}
+
+func TestHostCircuitBreakerConfig(t *testing.T) {
+	config := CircuitBreakerConfig{FailureThreshold: 3, OpenTimeout: time.Second}
+
+	if HostCircuitBreaker("breaker.test", config) != HostCircuitBreaker("breaker.test", config) {
+		t.Error("clients with the same config got different breakers for a host")
+	}
+	if HostCircuitBreaker("breaker.test", config) == HostCircuitBreaker("breaker.test", CircuitBreakerConfig{FailureThreshold: 10}) {
+		t.Error("clients with another config shared a breaker")
+	}
+	if HostCircuitBreaker("breaker.test", CircuitBreakerConfig{}) != HostCircuitBreaker("breaker.test", CircuitBreakerConfig{FailureThreshold: defaultFailureThreshold, OpenTimeout: defaultOpenTimeout}) {
+		t.Error("the zero config did not share the breaker of the default config")
+	}
+}
+
+func TestDoRetriesOnlyReplayableBody(t *testing.T) {
+	var calls int32
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := NewHTTPClient(HTTPClient{APIURL: server.URL, HTTPClient: server.Client(), ClientName: "test", RetryPolicy: &RetryPolicy{MaxRetries: 2, NoSleep: true}})
+
+	t.Run("GetBody", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		bodies = nil
+		req, _ := http.NewRequest(http.MethodPut, server.URL+"/banks/1", bytes.NewBufferString(`{"name":"BCA"}`))
+
+		if _, errDo := c.Do(req); errDo != nil && errDo.Error != nil {
+			t.Fatalf("Do: %v", errDo.Error)
+		}
+		if len(bodies) != 2 || bodies[1] != `{"name":"BCA"}` {
+			t.Errorf("server got bodies %q, want the body sent twice", bodies)
+		}
+	})
+
+	t.Run("WithoutGetBody", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		bodies = nil
+		req, _ := http.NewRequest(http.MethodPut, server.URL+"/banks/1", ioutil.NopCloser(bytes.NewBufferString(`{"name":"BCA"}`)))
+
+		_, errDo := c.Do(req)
+		if errDo == nil || errDo.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("error = %+v, want the 503 of the only attempt", errDo)
+		}
+		if len(bodies) != 1 {
+			t.Errorf("server got bodies %q, want a single attempt", bodies)
+		}
+	})
+}
@@ -0,0 +1,429 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// RecorderMode tells the Recorder whether it calls the real endpoints or replays its cassette
+type RecorderMode int
+
+const (
+	// ModeReplay answers from the cassette only, it fails when the cassette file is missing
+	ModeReplay RecorderMode = iota
+	// ModeRecord calls the real endpoints and writes the interactions to the cassette on Stop
+	ModeRecord
+	// ModeRecordOnce records when the cassette file is missing and replays it otherwise
+	ModeRecordOnce
+)
+
+// redactedValue replaces the scrubbed secrets in a cassette, a recorded redactedValue matches any value on replay
+const redactedValue = "[REDACTED]"
+
+// ErrUnexpectedRequest is returned on replay for a request the cassette has no interaction left for
+var ErrUnexpectedRequest = errors.New("client: unexpected request, no interaction of the cassette matches")
+
+// Cassette is the file the interactions of a Recorder are kept in
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request with the response it got
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the request of an interaction, scrubbed of its secrets
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is the response of an interaction, scrubbed of its secrets
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Matcher tells whether a request is the one of a recorded interaction
+type Matcher func(req *http.Request, body []byte, recorded RecordedRequest) bool
+
+// Recorder is a http.RoundTripper recording the interactions with the real endpoints into a cassette
+// and replaying them, so the code calling through HTTPClient can be tested offline:
+//
+//	rec, err := client.NewRecorder("testdata/partner.json", client.ModeRecordOnce)
+//	defer rec.Stop()
+//	c := client.NewHTTPClient(client.HTTPClient{APIURL: url, HTTPClient: rec.Client()})
+type Recorder struct {
+	Path string
+	Mode RecorderMode
+	// Transport calls the real endpoints while recording, http.DefaultTransport by default
+	Transport http.RoundTripper
+	// Matcher finds the interaction of a request on replay, MatchDefault by default
+	Matcher Matcher
+	// ScrubFields are scrubbed from the headers, queries & json bodies on top of the ones never logged, see RedactHeader
+	ScrubFields []string
+
+	mu        sync.Mutex
+	recording bool
+	cassette  Cassette
+	played    []bool
+}
+
+// NewRecorder loads the cassette at path unless the mode records it
+func NewRecorder(path string, mode RecorderMode) (*Recorder, error) {
+	r := &Recorder{Path: path, Mode: mode}
+
+	recording := mode == ModeRecord
+	if mode == ModeRecordOnce {
+		_, err := os.Stat(path)
+		switch {
+		case os.IsNotExist(err):
+			recording = true
+		case err != nil:
+			return nil, fmt.Errorf("client: the cassette could not be read: %w", err)
+		}
+	}
+	r.recording = recording
+
+	if recording {
+		return r, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("client: the cassette could not be read: %w", err)
+	}
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("client: the cassette %s is not valid: %w", path, err)
+	}
+	r.played = make([]bool, len(r.cassette.Interactions))
+
+	return r, nil
+}
+
+// Client returns a http client for HTTPClient.HTTPClient going through the recorder
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// IsRecording tells whether the real endpoints are called
+func (r *Recorder) IsRecording() bool {
+	return r.recording
+}
+
+// RoundTrip records or replays the request
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if r.recording {
+		return r.record(req, body)
+	}
+
+	return r.replay(req, body)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	out := req.Clone(req.Context())
+	out.Body = ioutil.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+
+	res, err := transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    r.scrubURL(req.URL),
+			Header: r.scrubHeader(req.Header),
+			Body:   string(r.scrubBody(body)),
+		},
+		Response: RecordedResponse{
+			StatusCode: res.StatusCode,
+			Header:     r.scrubHeader(res.Header),
+			Body:       string(r.scrubBody(resBody)),
+		},
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+	res.ContentLength = int64(len(resBody))
+	return res, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	matcher := r.Matcher
+	if matcher == nil {
+		matcher = MatchDefault
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.played[i] || !matcher(req, body, interaction.Request) {
+			continue
+		}
+		r.played[i] = true
+
+		recorded := interaction.Response
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+			StatusCode:    recorded.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        recorded.Header.Clone(),
+			Body:          ioutil.NopCloser(strings.NewReader(recorded.Body)),
+			ContentLength: int64(len(recorded.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrUnexpectedRequest, req.Method, req.URL.String())
+}
+
+// Unplayed returns the interactions of the cassette no request was replayed from
+func (r *Recorder) Unplayed() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := []Interaction{}
+	for i, interaction := range r.cassette.Interactions {
+		if !r.played[i] {
+			result = append(result, interaction)
+		}
+	}
+	return result
+}
+
+// Stop writes the cassette when recording. On replay the cassette is left as is,
+// Stop fails when some of its interactions were not replayed so a test notices the calls it stopped making
+func (r *Recorder) Stop() error {
+	if !r.recording {
+		if unplayed := r.Unplayed(); len(unplayed) > 0 {
+			return fmt.Errorf("client: %d interactions of the cassette %s were not replayed, the first is %s %s",
+				len(unplayed), r.Path, unplayed[0].Request.Method, unplayed[0].Request.URL)
+		}
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.Path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(r.Path, data, 0644)
+}
+
+func (r *Recorder) isScrubbed(name string) bool {
+	if isRedactedHeader(name) {
+		return true
+	}
+	for _, field := range r.ScrubFields {
+		if strings.EqualFold(name, field) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Recorder) scrubHeader(header http.Header) http.Header {
+	result := http.Header{}
+	for name, values := range header {
+		if r.isScrubbed(name) {
+			result[name] = []string{redactedValue}
+			continue
+		}
+		result[name] = values
+	}
+	return result
+}
+
+func (r *Recorder) scrubURL(u *url.URL) string {
+	scrubbed := *u
+	query := scrubbed.Query()
+	for name := range query {
+		if r.isScrubbed(name) {
+			query[name] = []string{redactedValue}
+		}
+	}
+	scrubbed.RawQuery = query.Encode()
+	scrubbed.User = nil
+	return scrubbed.String()
+}
+
+// scrubBody scrubs a json body, any other body is kept as is
+func (r *Recorder) scrubBody(body []byte) []byte {
+	var value interface{}
+	if len(body) == 0 || json.Unmarshal(body, &value) != nil {
+		return body
+	}
+
+	data, err := json.Marshal(r.scrubValue(value))
+	if err != nil {
+		return body
+	}
+	return data
+}
+
+func (r *Recorder) scrubValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, field := range v {
+			if r.isScrubbed(name) {
+				v[name] = redactedValue
+				continue
+			}
+			v[name] = r.scrubValue(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.scrubValue(item)
+		}
+	}
+	return value
+}
+
+// MatchAll matches when every matcher does
+func MatchAll(matchers ...Matcher) Matcher {
+	return func(req *http.Request, body []byte, recorded RecordedRequest) bool {
+		for _, matcher := range matchers {
+			if !matcher(req, body, recorded) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// MatchDefault matches on the method, path, query & body
+func MatchDefault(req *http.Request, body []byte, recorded RecordedRequest) bool {
+	return MatchAll(MatchMethod, MatchPath, MatchQuery, MatchBody)(req, body, recorded)
+}
+
+// MatchMethod matches on the method
+func MatchMethod(req *http.Request, body []byte, recorded RecordedRequest) bool {
+	return strings.EqualFold(req.Method, recorded.Method)
+}
+
+// MatchPath matches on the host & path
+func MatchPath(req *http.Request, body []byte, recorded RecordedRequest) bool {
+	u, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+	return u.Host == req.URL.Host && strings.TrimSuffix(u.Path, "/") == strings.TrimSuffix(req.URL.Path, "/")
+}
+
+// MatchQuery matches on the query whatever its order, a scrubbed value matches any value
+func MatchQuery(req *http.Request, body []byte, recorded RecordedRequest) bool {
+	u, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+
+	want, got := u.Query(), req.URL.Query()
+	if len(want) != len(got) {
+		return false
+	}
+	for name, values := range want {
+		if len(values) == 1 && values[0] == redactedValue {
+			if _, ok := got[name]; !ok {
+				return false
+			}
+			continue
+		}
+		if !reflect.DeepEqual(values, got[name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchBody matches on the body, json bodies whatever their key order with the scrubbed values matching any value
+func MatchBody(req *http.Request, body []byte, recorded RecordedRequest) bool {
+	var want, got interface{}
+	if json.Unmarshal([]byte(recorded.Body), &want) != nil || json.Unmarshal(body, &got) != nil {
+		return string(body) == recorded.Body
+	}
+	return matchJSON(want, got)
+}
+
+func matchJSON(want, got interface{}) bool {
+	if want == redactedValue {
+		return true
+	}
+
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok || len(w) != len(g) {
+			return false
+		}
+		for name, value := range w {
+			field, ok := g[name]
+			if !ok || !matchJSON(value, field) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok || len(w) != len(g) {
+			return false
+		}
+		for i := range w {
+			if !matchJSON(w[i], g[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return reflect.DeepEqual(want, got)
+}
@@ -0,0 +1,248 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type partnerBank struct {
+	AccessToken string `json:"access_token"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+}
+
+func newPartnerServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer live-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(partnerBank{AccessToken: "live-access-token", ID: "2", Name: req["name"]})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newRecorderClient(apiURL string, rec *Recorder, token string) *HTTPClient {
+	c := NewHTTPClient(HTTPClient{APIURL: apiURL, HTTPClient: rec.Client()})
+	c.AddAuthentication(nil, AuthorizationType{HeaderName: "Authorization", HeaderType: "Bearer", HeaderTypeValue: "Bearer ", Token: token})
+	return c
+}
+
+func TestRecorderRecordsAndReplays(t *testing.T) {
+	server := newPartnerServer(t)
+	path := filepath.Join(t.TempDir(), "partner.json")
+
+	rec, err := NewRecorder(path, ModeRecordOnce)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	if !rec.IsRecording() {
+		t.Fatal("a missing cassette is not recorded")
+	}
+	rec.ScrubFields = []string{"pin"}
+
+	request := map[string]string{"name": "Mandiri", "password": "live-password", "pin": "123456"}
+	c := newRecorderClient(server.URL, rec, "live-token")
+	recorded, err := Call[partnerBank](newTestContext(), c, Request{Method: POST, Path: "banks", Query: url.Values{"api_key": {"live-key"}}, Body: request})
+	if err != nil {
+		t.Fatalf("recorded call: %v", err)
+	}
+	if recorded.AccessToken != "live-access-token" {
+		t.Errorf("recorded call got %+v, want the real response", recorded)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	cassette, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read cassette: %v", err)
+	}
+	for _, secret := range []string{"live-token", "live-key", "live-password", "123456", "live-access-token"} {
+		if strings.Contains(string(cassette), secret) {
+			t.Errorf("cassette holds the secret %q:\n%s", secret, cassette)
+		}
+	}
+
+	// the server is gone, the call is answered from the cassette whatever the secrets are now
+	server.Close()
+
+	rec, err = NewRecorder(path, ModeRecordOnce)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	if rec.IsRecording() {
+		t.Fatal("an existing cassette is recorded again")
+	}
+	rec.ScrubFields = []string{"pin"}
+
+	request["password"], request["pin"] = "other-password", "654321"
+	c = newRecorderClient(server.URL, rec, "other-token")
+	replayed, err := Call[partnerBank](newTestContext(), c, Request{Method: POST, Path: "banks", Query: url.Values{"api_key": {"other-key"}}, Body: request})
+	if err != nil {
+		t.Fatalf("replayed call: %v", err)
+	}
+	if replayed.ID != "2" || replayed.Name != "Mandiri" || replayed.AccessToken != redactedValue {
+		t.Errorf("replayed call got %+v, want the scrubbed recorded response", replayed)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Errorf("Stop: %v", err)
+	}
+}
+
+func TestRecorderReplaysCassette(t *testing.T) {
+	rec, err := NewRecorder("testdata/partner.json", ModeReplay)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	c := newRecorderClient("https://partner.test", rec, "any-token")
+
+	type banks struct {
+		Data []partnerBank `json:"data"`
+	}
+	list, err := Call[banks](newTestContext(), c, Request{Path: "banks", Query: url.Values{"page": {"1"}, "api_key": {"any-key"}}})
+	if err != nil {
+		t.Fatalf("GET banks: %v", err)
+	}
+	if len(list.Data) != 1 || list.Data[0].Name != "BCA" {
+		t.Errorf("GET banks got %+v, want the recorded bank", list)
+	}
+
+	created, err := Call[partnerBank](newTestContext(), c, Request{Method: POST, Path: "banks", Body: map[string]string{"password": "any", "name": "Mandiri"}})
+	if err != nil {
+		t.Fatalf("POST banks: %v", err)
+	}
+	if created.ID != "2" {
+		t.Errorf("POST banks got %+v, want the recorded bank", created)
+	}
+
+	// an interaction is replayed once only
+	_, err = c.Send(newTestContext(), Request{Method: POST, Path: "banks", Body: map[string]string{"password": "any", "name": "Mandiri"}})
+	if !errors.Is(err, ErrUnexpectedRequest) {
+		t.Errorf("second POST error = %v, want ErrUnexpectedRequest", err)
+	}
+
+	_, err = c.Send(newTestContext(), Request{Method: POST, Path: "banks", Body: map[string]string{"name": "BNI"}})
+	if !errors.Is(err, ErrUnexpectedRequest) {
+		t.Errorf("unrecorded POST error = %v, want ErrUnexpectedRequest", err)
+	}
+
+	unplayed := rec.Unplayed()
+	if len(unplayed) != 1 || unplayed[0].Request.Method != "DELETE" {
+		t.Fatalf("unplayed = %+v, want the DELETE interaction", unplayed)
+	}
+	if err := rec.Stop(); err == nil {
+		t.Error("Stop succeeded with an interaction not replayed")
+	}
+
+	if _, err := c.Send(newTestContext(), Request{Method: DELETE, Path: "banks/2"}); err != nil {
+		t.Fatalf("DELETE banks: %v", err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Errorf("Stop: %v", err)
+	}
+}
+
+func TestNewRecorder(t *testing.T) {
+	if _, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), ModeReplay); err == nil {
+		t.Error("replaying a missing cassette succeeded")
+	}
+
+	// a cassette under a file can not be read, it is not missing
+	file := filepath.Join(t.TempDir(), "file")
+	if err := ioutil.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewRecorder(filepath.Join(file, "partner.json"), ModeRecordOnce); err == nil {
+		t.Error("recording once a cassette which can not be read succeeded")
+	}
+
+	invalid := filepath.Join(t.TempDir(), "invalid.json")
+	if err := ioutil.WriteFile(invalid, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewRecorder(invalid, ModeRecordOnce); err == nil {
+		t.Error("replaying an invalid cassette succeeded")
+	}
+
+	path := filepath.Join(t.TempDir(), "record.json")
+	rec, err := NewRecorder(path, ModeRecord)
+	if err != nil || !rec.IsRecording() {
+		t.Fatalf("NewRecorder = %v, %v, want a recording recorder", rec, err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Stop did not write the cassette: %v", err)
+	}
+}
+
+func TestMatchQuery(t *testing.T) {
+	cases := []struct {
+		name     string
+		recorded string
+		url      string
+		want     bool
+	}{
+		{"same query", "https://partner.test/banks?page=1&size=10", "https://partner.test/banks?page=1&size=10", true},
+		{"other order", "https://partner.test/banks?page=1&size=10", "https://partner.test/banks?size=10&page=1", true},
+		{"other value", "https://partner.test/banks?page=1", "https://partner.test/banks?page=2", false},
+		{"missing field", "https://partner.test/banks?page=1&size=10", "https://partner.test/banks?page=1", false},
+		{"extra field", "https://partner.test/banks?page=1", "https://partner.test/banks?page=1&size=10", false},
+		{"redacted value", "https://partner.test/banks?api_key=%5BREDACTED%5D", "https://partner.test/banks?api_key=any", true},
+		{"redacted field missing", "https://partner.test/banks?api_key=%5BREDACTED%5D", "https://partner.test/banks", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			if got := MatchQuery(req, nil, RecordedRequest{URL: tc.recorded}); got != tc.want {
+				t.Errorf("MatchQuery = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMatchBody(t *testing.T) {
+	cases := []struct {
+		name     string
+		recorded string
+		body     string
+		want     bool
+	}{
+		{"same json", `{"name":"BCA","code":"014"}`, `{"name":"BCA","code":"014"}`, true},
+		{"other key order", `{"name":"BCA","code":"014"}`, `{"code":"014","name":"BCA"}`, true},
+		{"other value", `{"name":"BCA"}`, `{"name":"BNI"}`, false},
+		{"extra field", `{"name":"BCA"}`, `{"name":"BCA","code":"014"}`, false},
+		{"redacted value", `{"name":"BCA","password":"[REDACTED]"}`, `{"password":"secret","name":"BCA"}`, true},
+		{"redacted nested value", `{"user":{"pin":"[REDACTED]"},"items":[1,2]}`, `{"user":{"pin":"1234"},"items":[1,2]}`, true},
+		{"other array", `{"items":[1,2]}`, `{"items":[2,1]}`, false},
+		{"same text", `name=BCA`, `name=BCA`, true},
+		{"other text", `name=BCA`, `name=BNI`, false},
+		{"empty", ``, ``, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "https://partner.test/banks", nil)
+			if got := MatchBody(req, []byte(tc.body), RecordedRequest{Body: tc.recorded}); got != tc.want {
+				t.Errorf("MatchBody = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Request describes a call made with HTTPClient.Send
+type Request struct {
+	Method Method
+	// Path is joined to the api url of the client, a full url is used as is
+	Path string
+	// Query is a struct given to ParseQueryParams or url.Values, added to the query string of the path
+	Query interface{}
+	// Header is added to the authentication of the client for this call only
+	Header http.Header
+	// Body is sent as json, a []byte, string or io.Reader is sent as is
+	Body interface{}
+	// Multipart is sent as multipart/form-data instead of the body
+	Multipart *Multipart
+	// Output receives the response body as it is read instead of Response.Body, for large downloads
+	Output io.Writer
+	// IsWithoutLog leaves the call out of the client request log
+	IsWithoutLog bool
+}
+
+// Multipart represents the fields & files of a multipart/form-data request
+type Multipart struct {
+	Fields map[string]string
+	Files  []MultipartFile
+}
+
+// MultipartFile represents a file of a multipart/form-data request
+type MultipartFile struct {
+	Field       string
+	Filename    string
+	ContentType string
+	Content     io.Reader
+}
+
+// Response represents the response of a call made with HTTPClient.Send
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	// Body is empty when the response was written to Request.Output
+	Body []byte
+	// Size is the number of bytes of the body
+	Size int64
+}
+
+// Error represents a failed call made with HTTPClient.Send, StatusCode is 0 when no response came back
+type Error struct {
+	StatusCode int
+	// Code is the code given in the error body, the status code when there is none
+	Code    string
+	Message string
+	Body    []byte
+	// Parsed is the error body when it is a json object
+	Parsed map[string]interface{}
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("client: %v", e.Err)
+	}
+	return fmt.Sprintf("client: %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Call sends the request and decodes the json response into a T
+func Call[T any](ctx context.Context, c *HTTPClient, r Request) (T, error) {
+	var result T
+
+	if r.Output != nil {
+		return result, errors.New("client: Call decodes the body, it can not be written to an output")
+	}
+
+	res, err := c.Send(ctx, r)
+	if err != nil {
+		return result, err
+	}
+
+	if len(res.Body) == 0 {
+		return result, nil
+	}
+
+	if err := json.Unmarshal(res.Body, &result); err != nil {
+		return result, &Error{StatusCode: res.StatusCode, Code: strconv.Itoa(res.StatusCode), Message: "invalid json response", Body: res.Body, Err: err}
+	}
+
+	return result, nil
+}
+
+// Send calls the client with the request. The call ends with ctx, a *gin.Context ends it with the request it serves.
+// A response outside 2xx is returned as an *Error
+func (c *HTTPClient) Send(ctx context.Context, r Request) (*Response, error) {
+	body, contentType, err := r.encode()
+	if err != nil {
+		return nil, &Error{Err: err}
+	}
+
+	rawURL := r.Path
+	if !strings.Contains(rawURL, "://") {
+		rawURL = fmt.Sprintf("%s/%s", c.APIURL, strings.TrimPrefix(rawURL, "/"))
+	}
+	rawURL, err = withQuery(rawURL, r.Query)
+	if err != nil {
+		return nil, &Error{Err: err}
+	}
+
+	method := r.Method
+	if method == "" {
+		method = GET
+	}
+
+	req, err := http.NewRequestWithContext(requestContext(ctx), string(method), rawURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, &Error{Err: err}
+	}
+
+	for _, authorizationType := range c.AuthorizationTypes {
+		if authorizationType.HeaderType != "APIKey" {
+			req.Header.Add(authorizationType.HeaderName, fmt.Sprintf("%s%s", authorizationType.HeaderTypeValue, authorizationType.Token))
+		}
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for name, values := range r.Header {
+		req.Header[textproto.CanonicalMIMEHeaderKey(name)] = values
+	}
+
+	gctx, _ := ctx.(*gin.Context)
+	req.Header.Set(CorrelationIDHeader, CorrelationID(gctx))
+
+	start := time.Now()
+	res, errSend := c.receive(req, r.Output)
+	if !r.IsWithoutLog {
+		c.logResponse(gctx, req, body, res, errSend, r.Output != nil, time.Since(start))
+	}
+
+	return res, errSend
+}
+
+// receive does the request and reads the response into Response.Body or output
+func (c *HTTPClient) receive(req *http.Request, output io.Writer) (*Response, error) {
+	res, done, err := c.roundTrip(req)
+	if err != nil {
+		return nil, &Error{Err: err}
+	}
+	defer done()
+
+	result := &Response{StatusCode: res.StatusCode, Header: res.Header}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(res.Body)
+		return result, newError(res.StatusCode, body)
+	}
+
+	if output != nil {
+		result.Size, err = io.Copy(output, res.Body)
+	} else {
+		result.Body, err = ioutil.ReadAll(res.Body)
+		result.Size = int64(len(result.Body))
+	}
+	if err != nil {
+		return result, &Error{StatusCode: res.StatusCode, Code: strconv.Itoa(res.StatusCode), Message: "the response could not be read", Err: err}
+	}
+
+	return result, nil
+}
+
+// logResponse writes the call to the client request log, a streamed body is logged by its size
+func (c *HTTPClient) logResponse(ctx *gin.Context, req *http.Request, body []byte, res *Response, err error, isStreamed bool, latency time.Duration) {
+	response := ""
+	errDo := &ResponseError{}
+	if res != nil {
+		errDo.StatusCode = res.StatusCode
+		response = string(res.Body)
+		if isStreamed {
+			response = fmt.Sprintf("(%d bytes streamed)", res.Size)
+		}
+	}
+	if err != nil {
+		errDo.Error = err
+		if e, ok := err.(*Error); ok {
+			errDo.Body = string(e.Body)
+		}
+	}
+
+	if strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/") {
+		body = []byte(fmt.Sprintf("(%d bytes multipart)", len(body)))
+	}
+
+	c.logRequest(ctx, req, body, response, errDo, latency)
+}
+
+func newError(statusCode int, body []byte) *Error {
+	e := &Error{StatusCode: statusCode, Code: strconv.Itoa(statusCode), Message: http.StatusText(statusCode), Body: body}
+
+	parsed := map[string]interface{}{}
+	if json.Unmarshal(body, &parsed) == nil {
+		e.Parsed = parsed
+		if code, ok := parsed["code"]; ok && code != nil && fmt.Sprint(code) != "" {
+			e.Code = fmt.Sprint(code)
+		}
+		for _, key := range []string{"message", "error", "error_description"} {
+			if message, ok := parsed[key].(string); ok && message != "" {
+				e.Message = message
+				break
+			}
+		}
+	}
+
+	e.Err = fmt.Errorf("status %d", statusCode)
+	return e
+}
+
+// encode returns the body of the request with its content type
+func (r Request) encode() ([]byte, string, error) {
+	if r.Multipart != nil {
+		return r.Multipart.encode()
+	}
+
+	switch body := r.Body.(type) {
+	case nil:
+		return nil, "", nil
+	case []byte:
+		return body, "", nil
+	case string:
+		return []byte(body), "", nil
+	case io.Reader:
+		data, err := ioutil.ReadAll(body)
+		return data, "", err
+	}
+
+	data, err := json.Marshal(r.Body)
+	return data, "application/json", err
+}
+
+func (m *Multipart) encode() ([]byte, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	for name, value := range m.Fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return nil, "", err
+		}
+	}
+
+	for _, file := range m.Files {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, escapeQuotes(file.Field), escapeQuotes(file.Filename)))
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header.Set("Content-Type", contentType)
+
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := io.Copy(part, file.Content); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
+
+func escapeQuotes(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+// withQuery adds the query to the url, a url.Values as is & a struct through ParseQueryParams
+func withQuery(rawURL string, query interface{}) (string, error) {
+	switch values := query.(type) {
+	case nil:
+		return rawURL, nil
+	case url.Values:
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return "", err
+		}
+		q := u.Query()
+		for name, value := range values {
+			q[name] = append(q[name], value...)
+		}
+		u.RawQuery = q.Encode()
+		return u.String(), nil
+	}
+
+	return ParseQueryParams(rawURL, query), nil
+}
+
+// requestContext is the context of the request a *gin.Context serves, the calls made for it end with it
+func requestContext(ctx context.Context) context.Context {
+	if gctx, ok := ctx.(*gin.Context); ok {
+		if gctx != nil && gctx.Request != nil {
+			return gctx.Request.Context()
+		}
+		return context.Background()
+	}
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
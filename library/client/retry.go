@@ -0,0 +1,168 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy decides which calls HTTPClient.Do sends again and how long it waits in between,
+// zero values use the defaults
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+	// MinDelay is the wait before the first retry, doubled on every next one, 500ms by default
+	MinDelay time.Duration
+	// MaxDelay caps the wait between two attempts, 5 seconds by default. A longer Retry-After is honoured
+	MaxDelay time.Duration
+	// RetryStatusCodes are the responses worth another attempt, 429, 502, 503 & 504 by default
+	RetryStatusCodes []int
+	// RetryMethods are the methods retried, the idempotent ones by default.
+	// A request carrying an Idempotency-Key is retried whatever its method.
+	// A request whose body can not be read again, one without GetBody, is never retried
+	RetryMethods []Method
+	// ShouldRetryError classifies the transport errors, every error but a canceled context
+	// or a request unexpected by a Recorder by default
+	ShouldRetryError func(err error) bool
+	// ShouldRetryResponse classifies the responses, by RetryStatusCodes by default
+	ShouldRetryResponse func(res *http.Response) bool
+	// AttemptTimeout limits every single attempt, on top of the deadline of the request context
+	AttemptTimeout time.Duration
+	// TotalTimeout limits all attempts together, no retry is made which would end after it
+	TotalTimeout time.Duration
+	// NoSleep retries right away, for tests
+	NoSleep bool
+}
+
+var defaultRetryStatusCodes = []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+
+var idempotentMethods = []Method{GET, PUT, DELETE, "HEAD", "OPTIONS", "TRACE"}
+
+// retryPolicy returns the policy of the client, one built from MaxNetworkRetries & UseNormalSleep when it has none
+func (c *HTTPClient) retryPolicy() RetryPolicy {
+	policy := RetryPolicy{MaxRetries: c.MaxNetworkRetries, NoSleep: c.UseNormalSleep}
+	if c.RetryPolicy != nil {
+		policy = *c.RetryPolicy
+	}
+
+	if policy.MinDelay <= 0 {
+		policy.MinDelay = minNetworkRetriesDelay
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = maxNetworkRetriesDelay
+	}
+	if policy.RetryStatusCodes == nil {
+		policy.RetryStatusCodes = defaultRetryStatusCodes
+	}
+	if policy.RetryMethods == nil {
+		policy.RetryMethods = idempotentMethods
+	}
+	if policy.ShouldRetryError == nil {
+		policy.ShouldRetryError = func(err error) bool {
+			return !errors.Is(err, context.Canceled) && !errors.Is(err, ErrUnexpectedRequest)
+		}
+	}
+	if policy.ShouldRetryResponse == nil {
+		statusCodes := policy.RetryStatusCodes
+		policy.ShouldRetryResponse = func(res *http.Response) bool {
+			for _, statusCode := range statusCodes {
+				if res.StatusCode == statusCode {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	return policy
+}
+
+func (p RetryPolicy) shouldRetry(req *http.Request, res *http.Response, err error, retry int) bool {
+	if retry >= p.MaxRetries {
+		return false
+	}
+
+	if !p.isRetryableMethod(req) {
+		return false
+	}
+
+	// the body was read by the failed attempt, without GetBody a retry would send it empty
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	if err != nil {
+		return p.ShouldRetryError(err)
+	}
+
+	return p.ShouldRetryResponse(res)
+}
+
+func (p RetryPolicy) isRetryableMethod(req *http.Request) bool {
+	if req.Header.Get(IdempotencyKeyHeader) != "" {
+		return true
+	}
+
+	for _, method := range p.RetryMethods {
+		if string(method) == req.Method {
+			return true
+		}
+	}
+	return false
+}
+
+// delay is the wait before the retry, the Retry-After of the response when it has one
+func (p RetryPolicy) delay(retry int, res *http.Response) time.Duration {
+	if p.NoSleep {
+		return 0
+	}
+
+	if res != nil {
+		if delay, ok := retryAfter(res.Header.Get("Retry-After")); ok {
+			return delay
+		}
+	}
+
+	// exponentially backoff by 2^numOfRetries
+	delay := p.MinDelay + p.MinDelay*time.Duration(1<<uint(retry))
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	// generate random jitter to prevent thundering herd problem
+	jitter := rand.Int63n(int64(delay/4) + 1)
+	delay -= time.Duration(jitter)
+
+	if delay < p.MinDelay {
+		delay = p.MinDelay
+	}
+
+	return delay
+}
+
+// retryAfter parses the Retry-After header, given in seconds or as a http date
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			seconds = 0
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		delay := time.Until(at)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
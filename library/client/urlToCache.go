@@ -1,20 +1,17 @@
 package client
 
 import (
-	"reflect"
-	"strings"
-
 	"github.com/gin-gonic/gin"
+	"net/http"
 
 	"luxe-beb-go/library/types"
-
-	validator "gopkg.in/go-playground/validator.v9"
+	"luxe-beb-go/library/validator"
 )
 
 // URLToCache object of URLToCache to reflect the url-to-cache data for response purpose
 // swagger:model
 type URLToCache struct {
-	ID           int    `json:"-" db:"id"`
+	ID           int    `json:"id" db:"id"`
 	BaseURL      string `json:"baseUrl" db:"baseUrl"`
 	Method       string `json:"method" db:"method"`
 	ClientID     int    `json:"clientId" db:"clientId"`
@@ -26,22 +23,22 @@ type URLToCache struct {
 // CreateURLToCacheParams represent the http request data for create URLToCache
 // swagger:model
 type CreateURLToCacheParams struct {
-	BaseURL      string `json:"baseUrl"`
-	Method       string `json:"method"`
+	BaseURL      string `json:"baseUrl" validate:"required,url,max=2048"`
+	Method       string `json:"method" validate:"required,oneof=GET POST PUT PATCH DELETE"`
 	ClientID     int    `json:"clientId"`
-	ClientName   string `json:"clientName"`
-	BufferedTime int    `json:"bufferedTime"`
+	ClientName   string `json:"clientName" validate:"max=100"`
+	BufferedTime int    `json:"bufferedTime" validate:"min=0"`
 	IsBlocked    bool   `json:"isBlocked"`
 }
 
 // UpdateURLToCacheParams represent the http request data for update URLToCache
 // swagger:model
 type UpdateURLToCacheParams struct {
-	BaseURL      string `json:"baseUrl"`
-	Method       string `json:"method"`
+	BaseURL      string `json:"baseUrl" validate:"required,url,max=2048"`
+	Method       string `json:"method" validate:"required,oneof=GET POST PUT PATCH DELETE"`
 	ClientID     int    `json:"clientId"`
-	ClientName   string `json:"clientName"`
-	BufferedTime int    `json:"bufferedTime"`
+	ClientName   string `json:"clientName" validate:"max=100"`
+	BufferedTime int    `json:"bufferedTime" validate:"min=0"`
 	IsBlocked    bool   `json:"isBlocked"`
 }
 
@@ -131,22 +128,14 @@ func (s *URLToCacheService) ListURLToCaches(ctx *gin.Context, params *FindAllURL
 
 // CreateURLToCache creates a new URLToCache
 func (s *URLToCacheService) CreateURLToCache(ctx *gin.Context, params *CreateURLToCacheParams) (*URLToCache, *types.Error) {
-	validate := validator.New()
-	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
-		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
-		if name == "-" {
-			return ""
-		}
-		return name
-	})
-
-	errValidation := validate.Struct(params)
+	errValidation := validator.Shared().StructCtx(ctx, params)
 	if errValidation != nil {
 		return nil, &types.Error{
-			Path:    ".URLToCacheService->CreateURLToCache()",
-			Message: errValidation.Error(),
-			Error:   errValidation,
-			Type:    "validation-error",
+			Path:       ".URLToCacheService->CreateURLToCache()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
 		}
 	}
 
@@ -170,22 +159,14 @@ func (s *URLToCacheService) CreateURLToCache(ctx *gin.Context, params *CreateURL
 
 // UpdateURLToCache updates a URLToCache
 func (s *URLToCacheService) UpdateURLToCache(ctx *gin.Context, urlToCacheID int, params *UpdateURLToCacheParams) (*URLToCache, *types.Error) {
-	validate := validator.New()
-	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
-		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
-		if name == "-" {
-			return ""
-		}
-		return name
-	})
-
-	errValidation := validate.Struct(params)
+	errValidation := validator.Shared().StructCtx(ctx, params)
 	if errValidation != nil {
 		return nil, &types.Error{
-			Path:    ".URLToCacheService->UpdateURLToCache()",
-			Message: errValidation.Error(),
-			Error:   errValidation,
-			Type:    "validation-error",
+			Path:       ".URLToCacheService->UpdateURLToCache()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
 		}
 	}
 
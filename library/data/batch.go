@@ -0,0 +1,98 @@
+package data
+
+import (
+	"net/http"
+
+	"luxe-beb-go/library/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BatchResult is the result of one ID of a batch
+type BatchResult struct {
+	ID         string      `json:"ID"`
+	IsSuccess  bool        `json:"IsSuccess"`
+	StatusCode int         `json:"StatusCode"`
+	Message    string      `json:"Message"`
+	Data       interface{} `json:"Data,omitempty"`
+}
+
+// RunBatch runs f for every ID and returns the result of each of them, it tells whether every ID succeeded.
+// Without partial success the IDs run in a single transaction, every change is rolled back when one ID fails
+// and the IDs which succeeded are reported as rolled back. With partial success every ID runs in its own transaction
+// so the IDs which succeeded are kept, it is a new transaction even when the context already holds one
+func (m *Manager) RunBatch(ctx *gin.Context, ids []string, isPartialSuccess bool, f func(tctx *gin.Context, id string) (interface{}, *types.Error)) ([]BatchResult, bool) {
+	results := make([]BatchResult, len(ids))
+	isSuccess := true
+
+	run := func(tctx *gin.Context, i int) *types.Error {
+		data, err := f(tctx, ids[i])
+		if err != nil {
+			results[i] = BatchResult{ID: ids[i], StatusCode: batchStatusCode(err), Message: err.Message}
+			return err
+		}
+
+		results[i] = BatchResult{ID: ids[i], IsSuccess: true, StatusCode: http.StatusOK, Message: "OK", Data: data}
+		return nil
+	}
+
+	if isPartialSuccess {
+		for i := range ids {
+			if errTransaction := m.RunInTransaction(withoutTransaction(ctx), func(tctx *gin.Context) *types.Error {
+				return run(tctx, i)
+			}); errTransaction != nil {
+				isSuccess = false
+				if results[i].ID == "" {
+					results[i] = BatchResult{ID: ids[i], StatusCode: batchStatusCode(errTransaction), Message: errTransaction.Message}
+				}
+			}
+		}
+
+		return results, isSuccess
+	}
+
+	var errBatch *types.Error
+	errTransaction := m.RunInTransaction(ctx, func(tctx *gin.Context) *types.Error {
+		// every ID runs so all of their errors are reported, the batch is rolled back afterwards
+		for i := range ids {
+			if err := run(tctx, i); err != nil && errBatch == nil {
+				errBatch = err
+			}
+		}
+
+		return errBatch
+	})
+	if errTransaction == nil {
+		return results, true
+	}
+
+	for i := range results {
+		switch {
+		case results[i].ID == "":
+			results[i] = BatchResult{ID: ids[i], StatusCode: batchStatusCode(errTransaction), Message: errTransaction.Message}
+		case results[i].IsSuccess:
+			results[i] = BatchResult{ID: ids[i], StatusCode: http.StatusFailedDependency, Message: "rolled back, another ID of the batch failed"}
+		}
+	}
+
+	return results, false
+}
+
+// withoutTransaction returns a copy of the context without its transaction, so RunInTransaction begins a new one
+// instead of joining it
+func withoutTransaction(ctx *gin.Context) *gin.Context {
+	tctx := ctx.Copy()
+	clearContext(tctx)
+	tctx.Set(writtenTablesKey, nil)
+	return tctx
+}
+
+func batchStatusCode(err *types.Error) int {
+	switch {
+	case err.StatusCode != 0:
+		return err.StatusCode
+	case err.Error == ErrNotFound:
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,69 @@
+package data
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// writtenTablesKey keeps the tables written in a transaction until it is committed
+const writtenTablesKey = "writtenTables"
+
+// TableWriteHook is called with the name of a table written through a MySQLStorage
+type TableWriteHook func(table string)
+
+var (
+	tableWriteHooksMu sync.RWMutex
+	tableWriteHooks   []TableWriteHook
+)
+
+// OnTableWrite registers a hook called every time a table is written,
+// the writes made in a transaction are only reported once it is committed
+func OnTableWrite(hook TableWriteHook) {
+	tableWriteHooksMu.Lock()
+	defer tableWriteHooksMu.Unlock()
+
+	tableWriteHooks = append(tableWriteHooks, hook)
+}
+
+// written reports the write of the storage table, it waits for the commit when a transaction is in the context
+func (r *MySQLStorage) written(ctx *gin.Context) {
+	if ctx != nil {
+		if _, ok := TxFromContext(ctx); ok {
+			tables, _ := ctx.Value(writtenTablesKey).(map[string]bool)
+			if tables == nil {
+				tables = map[string]bool{}
+				ctx.Set(writtenTablesKey, tables)
+			}
+			tables[r.tableName] = true
+			return
+		}
+	}
+
+	notifyTableWrite(r.tableName)
+}
+
+// flushWrittenTables reports the tables written in the committed transaction of the context
+func flushWrittenTables(ctx *gin.Context) {
+	tables, _ := ctx.Value(writtenTablesKey).(map[string]bool)
+	ctx.Set(writtenTablesKey, nil)
+
+	for table := range tables {
+		notifyTableWrite(table)
+	}
+}
+
+// discardWrittenTables forgets the tables written in the rolled back transaction of the context
+func discardWrittenTables(ctx *gin.Context) {
+	ctx.Set(writtenTablesKey, nil)
+}
+
+func notifyTableWrite(table string) {
+	tableWriteHooksMu.RLock()
+	hooks := tableWriteHooks
+	tableWriteHooksMu.RUnlock()
+
+	for _, hook := range hooks {
+		hook(table)
+	}
+}
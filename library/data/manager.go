@@ -14,8 +14,13 @@ type Manager struct {
 	db *sqlx.DB
 }
 
-// RunInTransaction runs the f with the transaction queryable inside the context
+// RunInTransaction runs the f with the transaction queryable inside the context.
+// A nested call joins the transaction already in the context, so everything commits or rolls back together.
 func (m *Manager) RunInTransaction(ctx *gin.Context, f func(tctx *gin.Context) *types.Error) *types.Error {
+	if _, ok := TxFromContext(ctx); ok {
+		return f(ctx)
+	}
+
 	tx, err := m.db.Beginx()
 	if err != nil {
 		tx.Rollback()
@@ -30,17 +35,18 @@ func (m *Manager) RunInTransaction(ctx *gin.Context, f func(tctx *gin.Context) *
 	}
 
 	ctx = NewContext(ctx, tx)
-	if err != nil {
-		fmt.Printf("\n[RunInTransaction - Prepare] Error: %v\n", err)
-	}
+	defer clearContext(ctx)
+
 	errTransaction := f(ctx)
 	if errTransaction != nil {
 		tx.Rollback()
+		discardWrittenTables(ctx)
 		return errTransaction
 	}
 
 	err = tx.Commit()
 	if err != nil {
+		discardWrittenTables(ctx)
 		err := &types.Error{
 			Path:    ".DealingHandler->Create()",
 			Message: fmt.Sprintf("error when committing transaction: %v", err),
@@ -49,6 +55,7 @@ func (m *Manager) RunInTransaction(ctx *gin.Context, f func(tctx *gin.Context) *
 		}
 		return err
 	}
+	flushWrittenTables(ctx)
 
 	return nil
 }
@@ -28,6 +28,11 @@ func NewContext(ctx *gin.Context, q Queryer) *gin.Context {
 	return ctx
 }
 
+// clearContext removes the transaction from the data context once it is committed or rolled back
+func clearContext(ctx *gin.Context) {
+	ctx.Set("transaction", nil)
+}
+
 // TxFromContext returns the trasanction object from the context
 func TxFromContext(ctx *gin.Context) (Queryer, bool) {
 	q := (*ctx).Value("transaction")
@@ -0,0 +1,180 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"luxe-beb-go/library"
+	"luxe-beb-go/library/appcontext"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+// DefaultSequenceFormat is the code format used when a sequence has none, e.g. BAG/2026/000123
+const DefaultSequenceFormat = "{PREFIX}/{YYYY}/{SEQ:6}"
+
+var (
+	// ErrSequenceNotFound declare specific error for a prefix without code sequence
+	ErrSequenceNotFound = fmt.Errorf("code sequence not found")
+
+	// ErrNoTransaction declare specific error for a reservation made outside of RunInTransaction
+	ErrNoTransaction = fmt.Errorf("code sequence must be reserved inside a transaction")
+
+	seqTokenRegex = regexp.MustCompile(`\{SEQ(?::(\d+))?\}`)
+)
+
+// Sequence represents a row of code_sequences, the last number given for a prefix in a year
+type Sequence struct {
+	ID          string `json:"ID" db:"id"`
+	BusinessID  string `json:"BusinessID" db:"business_id"`
+	Prefix      string `json:"Prefix" db:"prefix"`
+	Name        string `json:"Name" db:"name"`
+	Year        int    `json:"Year" db:"year"`
+	Sequence    int    `json:"Sequence" db:"sequence"`
+	Format      string `json:"Format" db:"format"`
+	ResetYearly bool   `json:"ResetYearly" db:"reset_yearly"`
+}
+
+// SequenceService reserves document codes from code_sequences
+type SequenceService struct {
+	db *sqlx.DB
+}
+
+// NewSequenceService creates a new sequence service
+func NewSequenceService(db *sqlx.DB) *SequenceService {
+	return &SequenceService{db: db}
+}
+
+// Next reserves the next code of the prefix. It has to be called inside RunInTransaction:
+// the row is locked with SELECT ... FOR UPDATE until the transaction ends, and a rollback
+// gives the number back, so the codes stay gap-free.
+// Yearly sequences start again from 1 on the first reservation of a new year.
+// The sequence of the current business is used, the shared one (without business) when the business has none.
+func (s *SequenceService) Next(ctx *gin.Context, prefix string) (string, error) {
+	tx, ok := TxFromContext(ctx)
+	if !ok {
+		return "", ErrNoTransaction
+	}
+
+	now := library.UTCPlus7()
+	businessID, err := s.owner(tx, appcontext.BusinessID(ctx), prefix)
+	if err != nil {
+		return "", err
+	}
+
+	seq, err := s.lock(tx, businessID, prefix, now.Year())
+	if err == ErrSequenceNotFound {
+		seq, err = s.startYear(tx, businessID, prefix, now.Year())
+	}
+	if err != nil {
+		return "", err
+	}
+
+	seq.Sequence++
+
+	statement, err := tx.PrepareNamed(`UPDATE code_sequences SET sequence = :sequence WHERE id = :id`)
+	if err != nil {
+		return "", err
+	}
+	defer statement.Close()
+
+	_, err = statement.Exec(map[string]interface{}{
+		"sequence": seq.Sequence,
+		"id":       seq.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return FormatSequence(seq.Format, seq.Prefix, now, seq.Sequence), nil
+}
+
+// lock selects & locks the sequence of the prefix used in the year by the business
+func (s *SequenceService) lock(tx Queryer, businessID string, prefix string, year int) (*Sequence, error) {
+	seq := Sequence{}
+	err := tx.Get(&seq, `
+  SELECT id, business_id, prefix, name, year, sequence, format, reset_yearly
+  FROM code_sequences
+  WHERE business_id = ? AND prefix = ? AND (reset_yearly = 0 OR year = ?)
+  ORDER BY year DESC
+  LIMIT 1
+  FOR UPDATE`, businessID, prefix, year)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSequenceNotFound
+		}
+		return nil, err
+	}
+
+	return &seq, nil
+}
+
+// startYear creates the sequence of a new year from the latest one of the prefix then locks it
+func (s *SequenceService) startYear(tx Queryer, businessID string, prefix string, year int) (*Sequence, error) {
+	statement, err := tx.PrepareNamed(`
+  INSERT IGNORE INTO code_sequences (id, business_id, prefix, name, year, sequence, format, reset_yearly)
+  SELECT UUID(), business_id, prefix, name, :year, 0, format, reset_yearly
+  FROM code_sequences
+  WHERE business_id = :business_id AND prefix = :prefix
+  ORDER BY year DESC
+  LIMIT 1`)
+	if err != nil {
+		return nil, err
+	}
+	defer statement.Close()
+
+	_, err = statement.Exec(map[string]interface{}{
+		"business_id": businessID,
+		"prefix":      prefix,
+		"year":        year,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.lock(tx, businessID, prefix, year)
+}
+
+// owner returns the business whose sequences of the prefix are used: the business itself when it has one,
+// the shared sequences (empty business) otherwise
+func (s *SequenceService) owner(tx Queryer, businessID string, prefix string) (string, error) {
+	if businessID == "" {
+		return "", nil
+	}
+
+	count := 0
+	err := tx.Get(&count, `SELECT COUNT(*) FROM code_sequences WHERE business_id = ? AND prefix = ?`, businessID, prefix)
+	if err != nil {
+		return "", err
+	}
+	if count == 0 {
+		return "", nil
+	}
+
+	return businessID, nil
+}
+
+// FormatSequence builds the code from the format tokens:
+// {PREFIX}, {YYYY}, {YY}, {MM} and {SEQ} or {SEQ:n} for a number padded to n digits
+func FormatSequence(format string, prefix string, t time.Time, sequence int) string {
+	if format == "" {
+		format = DefaultSequenceFormat
+	}
+
+	code := strings.NewReplacer(
+		"{PREFIX}", prefix,
+		"{YYYY}", t.Format("2006"),
+		"{YY}", t.Format("06"),
+		"{MM}", t.Format("01"),
+	).Replace(format)
+
+	return seqTokenRegex.ReplaceAllStringFunc(code, func(token string) string {
+		width, _ := strconv.Atoi(seqTokenRegex.FindStringSubmatch(token)[1])
+		return fmt.Sprintf("%0*d", width, sequence)
+	})
+}
@@ -22,6 +22,10 @@ import (
 var (
 	ErrNotFound     = fmt.Errorf("data is not found")
 	ErrAlreadyExist = fmt.Errorf("data already exists")
+	// ErrVersionConflict is returned when the row was updated since the version of the element was read
+	ErrVersionConflict = fmt.Errorf("data has been changed since it was read")
+	// ErrInvalidStatus is returned when the status is not one of the status table
+	ErrInvalidStatus = fmt.Errorf("invalid status input")
 )
 
 // GenericStorage represents the generic Storage
@@ -75,6 +79,12 @@ type MySQLStorage struct {
 	insertParams        string
 	updateSetFields     string
 	updateManySetFields string
+	businessColumn      string
+	sharedBusiness      bool
+	businessTable       string
+	businessKey         string
+	outletColumn        string
+	hasVersion          bool
 	logStorage          LogStorage
 }
 
@@ -88,8 +98,18 @@ type LogStorage struct {
 }
 
 // MysqlConfig represents the configuration for the postgres Storage.
+// BusinessColumn & OutletColumn name the columns used to scope the table
+// to the business & outlet allowed in the context, leave them empty for global tables.
+// SharedBusiness lists the rows without business to every business, only the super admin writes them.
+// A table without business column is scoped through BusinessTable, a link table
+// holding the id of the row in BusinessKey next to its business_id.
 type MysqlConfig struct {
-	IsImmutable bool
+	IsImmutable    bool
+	BusinessColumn string
+	SharedBusiness bool
+	BusinessTable  string
+	BusinessKey    string
+	OutletColumn   string
 }
 
 // Single queries an element according to the query & argument provided
@@ -104,13 +124,21 @@ func (r *MySQLStorage) Single(ctx *gin.Context, elem interface{}, where string,
 	// 	where = fmt.Sprintf(`"deletedAt" IS NULL AND %s`, where)
 	// }
 
-	statement, err := db.PrepareNamed(fmt.Sprintf("SELECT %s FROM `%s` WHERE %s", r.selectFields, r.tableName, where))
+	source, arg := r.tenantSource(ctx, arg)
+
+	query, args, err := sqlx.Named(fmt.Sprintf("SELECT %s FROM %s WHERE %s", r.selectFields, source, where), arg)
 	if err != nil {
 		return err
 	}
-	defer statement.Close()
 
-	err = statement.Get(elem, arg)
+	query, args, err = sqlx.In(query, args...)
+	if err != nil {
+		return err
+	}
+
+	query = db.Rebind(query)
+
+	err = db.Get(elem, query, args...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return ErrNotFound
@@ -132,14 +160,21 @@ func (r *MySQLStorage) SinglePOSTEMP(ctx *gin.Context, elem interface{}, where s
 	// 	where = fmt.Sprintf(`"deletedAt" IS NULL AND %s`, where)
 	// }
 
-	statement, err := db.PrepareNamed(fmt.Sprintf("SELECT %s FROM `%s` WHERE %s",
-		r.selectFields, r.tableName, where))
+	source, arg := r.tenantSource(ctx, arg)
+
+	query, args, err := sqlx.Named(fmt.Sprintf("SELECT %s FROM %s WHERE %s", r.selectFields, source, where), arg)
+	if err != nil {
+		return err
+	}
+
+	query, args, err = sqlx.In(query, args...)
 	if err != nil {
 		return err
 	}
-	defer statement.Close()
 
-	err = statement.Get(elem, arg)
+	query = db.Rebind(query)
+
+	err = db.Get(elem, query, args...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return ErrNotFound
@@ -162,7 +197,8 @@ func (r *MySQLStorage) Where(ctx *gin.Context, elems interface{}, where string,
 	// 	where = fmt.Sprintf(`"deletedAt" IS NULL AND %s`, where)
 	// }
 
-	query := fmt.Sprintf("SELECT %s FROM `%s` WHERE %s", r.selectFields, r.tableName, where)
+	source, arg := r.tenantSource(ctx, arg)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s", r.selectFields, source, where)
 
 	query, args, err := sqlx.Named(query, arg)
 	if err != nil {
@@ -196,7 +232,8 @@ func (r *MySQLStorage) WherePOSTEMP(ctx *gin.Context, elems interface{}, where s
 	// 	where = fmt.Sprintf(`"deletedAt" IS NULL AND %s`, where)
 	// }
 
-	query := fmt.Sprintf("SELECT %s FROM `%s` WHERE %s", r.selectFields, r.tableName, where)
+	source, arg := r.tenantSource(ctx, arg)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s", r.selectFields, source, where)
 	query, args, err := sqlx.Named(query, arg)
 	if err != nil {
 		return err
@@ -225,6 +262,8 @@ func (r *MySQLStorage) SelectWithQuery(ctx *gin.Context, elems interface{}, quer
 		db = tx
 	}
 
+	query, arg = r.tenantQuery(ctx, query, arg)
+
 	query, args, err := sqlx.Named(query, arg)
 	if err != nil {
 		return err
@@ -323,6 +362,11 @@ func (r *MySQLStorage) Insert(ctx *gin.Context, elem interface{}) (*sql.Result,
 	defer statement.Close()
 
 	dbArgs := r.insertArgs(*currentUserID, elem, 0)
+	err = r.checkTenantWrite(ctx, dbArgs, "")
+	if err != nil {
+		return nil, err
+	}
+
 	result, err := statement.Exec(dbArgs)
 	if err != nil {
 		return nil, err
@@ -335,14 +379,28 @@ func (r *MySQLStorage) Insert(ctx *gin.Context, elem interface{}) (*sql.Result,
 	// 	return nil, err
 	// }
 
-	// Assuming id is pre-generated before this
+	// Assuming id is pre-generated before this, an integer id left empty is taken from the auto increment
 	lastID := r.findID(elem)
+	if id, ok := lastID.(int); ok && id == 0 {
+		insertID, err := result.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+		r.setID(elem, insertID)
+		lastID = insertID
+	}
+
+	err = r.linkTenant(ctx, db, lastID)
+	if err != nil {
+		return nil, err
+	}
 
-	_, err = r.InsertTrail(ctx, lastID.(string))
+	_, err = r.InsertTrail(ctx, fmt.Sprintf("%v", lastID))
 	if err != nil {
 		return nil, err
 	}
 
+	r.written(ctx)
 	return &result, nil
 }
 
@@ -490,6 +548,9 @@ func (r *MySQLStorage) InsertMany(ctx *gin.Context, elem interface{}) error {
 		for i := 0; i < datas.Len(); i++ {
 			sqlStr += fmt.Sprintf("(%s),", insertParams(r.elemType, r.isImmutable, i+1))
 			arg := r.insertArgs(*currentUserID, datas.Index(i), i+1)
+			if err := r.checkTenantWrite(ctx, arg, strconv.Itoa(i+1)); err != nil {
+				return err
+			}
 			if indexData == 0 {
 				dbArgs = arg
 			} else {
@@ -517,6 +578,9 @@ func (r *MySQLStorage) InsertMany(ctx *gin.Context, elem interface{}) error {
 		for key, element := range datas.MapKeys() {
 			sqlStr += fmt.Sprintf("(%s),", insertParams(r.elemType, r.isImmutable, key+1))
 			arg := r.insertArgs(*currentUserID, datas.MapIndex(element), key+1)
+			if err := r.checkTenantWrite(ctx, arg, strconv.Itoa(key+1)); err != nil {
+				return err
+			}
 			if indexData == 0 {
 				dbArgs = arg
 			} else {
@@ -553,6 +617,14 @@ func (r *MySQLStorage) InsertMany(ctx *gin.Context, elem interface{}) error {
 		return err
 	}
 
+	if r.businessTable != "" {
+		err = r.linkTenant(ctx, db, r.elemIDs(datas)...)
+		if err != nil {
+			return err
+		}
+	}
+
+	r.written(ctx)
 	return nil
 }
 
@@ -577,6 +649,9 @@ func (r *MySQLStorage) InsertManyWithTime(ctx *gin.Context, elem interface{}, cr
 
 			arg := r.insertArgs(*currentUserID, datas.Index(i), i+1)
 			arg[fmt.Sprintf("created_at%d", i+1)] = created_at
+			if err := r.checkTenantWrite(ctx, arg, strconv.Itoa(i+1)); err != nil {
+				return err
+			}
 			if indexData == 0 {
 				dbArgs = arg
 			} else {
@@ -605,6 +680,9 @@ func (r *MySQLStorage) InsertManyWithTime(ctx *gin.Context, elem interface{}, cr
 			sqlStr += fmt.Sprintf("(%s),", insertParams(r.elemType, r.isImmutable, key+1))
 			arg := r.insertArgs(*currentUserID, datas.MapIndex(element), key+1)
 			arg[fmt.Sprintf("created_at%d", key+1)] = created_at
+			if err := r.checkTenantWrite(ctx, arg, strconv.Itoa(key+1)); err != nil {
+				return err
+			}
 			if indexData == 0 {
 				dbArgs = arg
 			} else {
@@ -656,6 +734,7 @@ func (r *MySQLStorage) insertData(ctx *gin.Context, sqlStr string, dbArgs map[st
 		return err
 	}
 
+	r.written(ctx)
 	return nil
 }
 
@@ -708,10 +787,20 @@ func (r *MySQLStorage) Update(ctx *gin.Context, elem interface{}) error {
 		return err
 	}
 
+	// a versioned row is only updated from the version it was read at
+	where := "id = :id"
+	if r.hasVersion {
+		if r.findVersion(existingElem) != r.findVersion(elem) {
+			return ErrVersionConflict
+		}
+		where = "id = :id AND `version` = :version"
+	}
+
 	statement, err := db.PrepareNamed(fmt.Sprintf(`
-    UPDATE %s SET %s WHERE id = :id`,
+    UPDATE %s SET %s WHERE %s`,
 		r.tableName,
-		r.updateSetFields))
+		r.updateSetFields,
+		where))
 	if err != nil {
 		return err
 	}
@@ -719,16 +808,35 @@ func (r *MySQLStorage) Update(ctx *gin.Context, elem interface{}) error {
 
 	updateArgs := r.updateArgs(*currentUserID, existingElem, elem)
 	updateArgs["id"] = id
+	if r.hasVersion {
+		updateArgs["version"] = r.findVersion(elem)
+	}
+
+	err = r.checkTenantWrite(ctx, updateArgs, "")
+	if err != nil {
+		return err
+	}
 
-	_, err = statement.Exec(updateArgs)
+	result, err := statement.Exec(updateArgs)
 	if err != nil {
 		return err
 	}
 
+	if r.hasVersion {
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return ErrVersionConflict
+		}
+	}
+
 	_, err = r.UpdateTrail(ctx, existingElem, elem, id)
 	if err != nil {
 		return err
 	}
+	r.written(ctx)
 	return nil
 }
 
@@ -771,19 +879,33 @@ func (r *MySQLStorage) UpdateStatus(ctx *gin.Context, id string, status_code str
 		db = tx
 	}
 
-	_, errCheck := strconv.Atoi(status_code)
-	if errCheck != nil {
-		return errCheck
+	var statusIDs []string
+	err := r.SelectWithQuery(ctx, &statusIDs, "SELECT `id` FROM `status` WHERE `id` = :status_code", map[string]interface{}{"status_code": status_code})
+	if err != nil {
+		return err
+	}
+	if len(statusIDs) == 0 {
+		return ErrInvalidStatus
 	}
 
-	if status_code != "1" && status_code != "0" {
-		return fmt.Errorf(`invalid status input`)
+	var ids []string
+	err = r.SelectWithQuery(ctx, &ids, fmt.Sprintf("SELECT `id` FROM `%s` WHERE `id` = :id", r.tableName), map[string]interface{}{"id": id})
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return ErrNotFound
+	}
+
+	err = r.checkTenantAccess(ctx, id)
+	if err != nil {
+		return err
 	}
 
 	updated_at := library.UTCPlus7().Format("2006-01-02 15:04:05")
 
 	statement, err := db.PrepareNamed(fmt.Sprintf(`
-    UPDATE %s SET status_id = :status_code, updated_at = :updated_at, updated_by = '%s' WHERE id = '%s'`, r.tableName, *currentUserID, id))
+    UPDATE %s SET status_id = :status_code, updated_at = :updated_at, updated_by = '%s'%s WHERE id = '%s'`, r.tableName, *currentUserID, r.versionIncrement(), id))
 	if err != nil {
 		return err
 	}
@@ -813,6 +935,7 @@ func (r *MySQLStorage) UpdateStatus(ctx *gin.Context, id string, status_code str
 		return err
 	}
 
+	r.written(ctx)
 	return nil
 }
 
@@ -841,6 +964,9 @@ func (r *MySQLStorage) UpdateMany(ctx *gin.Context, elems interface{}) error {
 	indexData := 0
 	if datas.Kind() == reflect.Slice {
 		for i := 0; i < datas.Len(); i++ {
+			if err := r.checkTenantRow(ctx, datas.Index(i)); err != nil {
+				return err
+			}
 			sqlStrIndex, arg := r.updateManyParams(*currentUserID, datas.Index(i), i+1)
 			sqlStr += sqlStrIndex
 			if indexData == 0 {
@@ -871,6 +997,9 @@ func (r *MySQLStorage) UpdateMany(ctx *gin.Context, elems interface{}) error {
 
 	if datas.Kind() == reflect.Map {
 		for key, element := range datas.MapKeys() {
+			if err := r.checkTenantRow(ctx, datas.MapIndex(element)); err != nil {
+				return err
+			}
 			sqlStrIndex, arg := r.updateManyParams(*currentUserID, datas.MapIndex(element), key+1)
 			sqlStr += sqlStrIndex
 			if indexData == 0 {
@@ -917,6 +1046,7 @@ func (r *MySQLStorage) UpdateMany(ctx *gin.Context, elems interface{}) error {
 		return err
 	}
 
+	r.written(ctx)
 	return nil
 }
 
@@ -945,11 +1075,12 @@ func (r *MySQLStorage) updated_ata(ctx *gin.Context, sqlStr string, dbArgs map[s
 		return err
 	}
 
+	r.written(ctx)
 	return nil
 }
 
 func (r *MySQLStorage) updateManyParams(currentUserID string, elem interface{}, index int) (string, map[string]interface{}) {
-	sqlStr := fmt.Sprintf(`(cast(:updated_at%d as timestamp),%d,`, index, currentUserID)
+	sqlStr := fmt.Sprintf(`(cast(:updated_at%d as timestamp),'%s',`, index, currentUserID)
 
 	var v reflect.Value
 
@@ -1037,6 +1168,38 @@ func (r *MySQLStorage) findID(elem interface{}) interface{} {
 	return nil
 }
 
+// findVersion returns the column named "version" of the element
+func (r *MySQLStorage) findVersion(elem interface{}) int {
+	v := reflect.ValueOf(elem).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		dbTag := r.elemType.Field(i).Tag.Get("db")
+		if versionTag(dbTag) {
+			return int(v.Field(i).Int())
+		}
+	}
+	return 0
+}
+
+// versionIncrement is the set clause bumping the version of a versioned row
+func (r *MySQLStorage) versionIncrement() string {
+	if !r.hasVersion {
+		return ""
+	}
+	return ", `version` = `version` + 1"
+}
+
+// setID sets the integer id column named "id" of the element
+func (r *MySQLStorage) setID(elem interface{}, id int64) {
+	v := reflect.ValueOf(elem).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		dbTag := r.elemType.Field(i).Tag.Get("db")
+		if idTag(dbTag) && v.Field(i).CanSet() {
+			v.Field(i).SetInt(id)
+			return
+		}
+	}
+}
+
 func (r *MySQLStorage) updateArgs(currentUserID string, existingElem interface{}, elem interface{}) map[string]interface{} {
 	res := map[string]interface{}{
 		"updated_at": library.UTCPlus7(),
@@ -1078,6 +1241,11 @@ func (r *MySQLStorage) Delete(ctx *gin.Context, id interface{}) error {
 		db = tx
 	}
 
+	err := r.checkTenantAccess(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	statement, err := db.PrepareNamed(fmt.Sprintf(`
     UPDATE %s SET deletedAt = :deletedAt, deletedBy = :deletedBy WHERE id = :id`, r.tableName))
 	if err != nil {
@@ -1095,6 +1263,7 @@ func (r *MySQLStorage) Delete(ctx *gin.Context, id interface{}) error {
 		return err
 	}
 
+	r.written(ctx)
 	return nil
 }
 
@@ -1114,6 +1283,12 @@ func (r *MySQLStorage) DeleteMany(ctx *gin.Context, ids interface{}) error {
 		return fmt.Errorf("ids data should be slices")
 	}
 
+	for i := 0; i < datas.Len(); i++ {
+		if err := r.checkTenantAccess(ctx, datas.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+
 	if r.isImmutable {
 		query := fmt.Sprintf(`DELETE FROM %s WHERE id IN (:ids)`, r.tableName)
 		query, args, err := sqlx.Named(query, map[string]interface{}{
@@ -1156,6 +1331,7 @@ func (r *MySQLStorage) DeleteMany(ctx *gin.Context, ids interface{}) error {
 	if err != nil {
 		return err
 	}
+	r.written(ctx)
 	return nil
 }
 
@@ -1193,6 +1369,11 @@ func (r *MySQLStorage) HardDelete(ctx *gin.Context, id interface{}) error {
 		db = tx
 	}
 
+	err := r.checkTenantAccess(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	statement, err := db.PrepareNamed(fmt.Sprintf(`
     DELETE FROM %s WHERE id = :id
   `, r.tableName))
@@ -1209,6 +1390,7 @@ func (r *MySQLStorage) HardDelete(ctx *gin.Context, id interface{}) error {
 		return err
 	}
 
+	r.written(ctx)
 	return nil
 }
 
@@ -1231,6 +1413,7 @@ func (r *MySQLStorage) ExecQuery(ctx *gin.Context, query string, args map[string
 		return err
 	}
 
+	r.written(ctx)
 	return nil
 }
 
@@ -1242,6 +1425,8 @@ func (r *MySQLStorage) SelectFirstWithQuery(ctx *gin.Context, elems interface{},
 		db = tx
 	}
 
+	query, arg = r.tenantQuery(ctx, query, arg)
+
 	query, args, err := sqlx.Named(query, arg)
 	if err != nil {
 		return err
@@ -1318,6 +1503,12 @@ func NewMySQLStorage(db *sqlx.DB, tableName string, elem interface{}, cfg MysqlC
 		insertParams:        insertParams(elemType, cfg.IsImmutable, 0),
 		updateSetFields:     updateSetFields(elemType),
 		updateManySetFields: updateManySetFields(elemType),
+		businessColumn:      cfg.BusinessColumn,
+		sharedBusiness:      cfg.SharedBusiness,
+		businessTable:       cfg.BusinessTable,
+		businessKey:         cfg.BusinessKey,
+		outletColumn:        cfg.OutletColumn,
+		hasVersion:          hasVersionField(elemType),
 	}
 }
 
@@ -1380,6 +1571,9 @@ func updateSetFields(elemType reflect.Type) string {
 			setFields = append(setFields, fmt.Sprintf("`%s` = :%s", dbTag, dbTag))
 		}
 	}
+	if hasVersionField(elemType) {
+		setFields = append(setFields, "`version` = `version` + 1")
+	}
 	return strings.Join(setFields, ",")
 }
 
@@ -1392,6 +1586,9 @@ func updateManySetFields(elemType reflect.Type) string {
 			setManyFields = append(setManyFields, fmt.Sprintf("`%s` = `updatedTable`.`%s`", dbTag, dbTag))
 		}
 	}
+	if hasVersionField(elemType) {
+		setManyFields = append(setManyFields, "`version` = `currentTable`.`version` + 1")
+	}
 
 	return strings.Join(setManyFields, ",")
 }
@@ -1400,6 +1597,20 @@ func idTag(dbTag string) bool {
 	return dbTag == "id"
 }
 
+// versionTag tells whether the column holds the version of the row, it is set by the database & bumped on every update
+func versionTag(dbTag string) bool {
+	return dbTag == "version"
+}
+
+func hasVersionField(elemType reflect.Type) bool {
+	for i := 0; i < elemType.NumField(); i++ {
+		if versionTag(elemType.Field(i).Tag.Get("db")) {
+			return true
+		}
+	}
+	return false
+}
+
 func emptyTag(dbTag string) bool {
 	emptyTags := []string{"", "-"}
 	for _, t := range emptyTags {
@@ -1411,7 +1622,7 @@ func emptyTag(dbTag string) bool {
 }
 
 func readOnlyTag(dbTag string) bool {
-	readOnlyTags := []string{"created_at", "updated_at", "deletedAt"}
+	readOnlyTags := []string{"created_at", "updated_at", "deletedAt", "version"}
 	for _, t := range readOnlyTags {
 		if dbTag == t {
 			return true
@@ -1442,11 +1653,17 @@ func (r *MySQLStorage) InsertNoTrail(ctx *gin.Context, elem interface{}) (*sql.R
 	defer statement.Close()
 
 	dbArgs := r.insertArgs(*currentUserID, elem, 0)
+	err = r.checkTenantWrite(ctx, dbArgs, "")
+	if err != nil {
+		return nil, err
+	}
+
 	result, err := statement.Exec(dbArgs)
 	if err != nil {
 		return nil, err
 	}
 
+	r.written(ctx)
 	return &result, nil
 }
 
@@ -1468,10 +1685,20 @@ func (r *MySQLStorage) UpdateNoTrail(ctx *gin.Context, elem interface{}) error {
 		return err
 	}
 
+	// a versioned row is only updated from the version it was read at
+	where := "id = :id"
+	if r.hasVersion {
+		if r.findVersion(existingElem) != r.findVersion(elem) {
+			return ErrVersionConflict
+		}
+		where = "id = :id AND `version` = :version"
+	}
+
 	statement, err := db.PrepareNamed(fmt.Sprintf(`
-    UPDATE %s SET %s WHERE id = :id`,
+    UPDATE %s SET %s WHERE %s`,
 		r.tableName,
-		r.updateSetFields))
+		r.updateSetFields,
+		where))
 	if err != nil {
 		return err
 	}
@@ -1479,11 +1706,30 @@ func (r *MySQLStorage) UpdateNoTrail(ctx *gin.Context, elem interface{}) error {
 
 	updateArgs := r.updateArgs(*currentUserID, existingElem, elem)
 	updateArgs["id"] = id
+	if r.hasVersion {
+		updateArgs["version"] = r.findVersion(elem)
+	}
 
-	_, err = statement.Exec(updateArgs)
+	err = r.checkTenantWrite(ctx, updateArgs, "")
 	if err != nil {
 		return err
 	}
 
+	result, err := statement.Exec(updateArgs)
+	if err != nil {
+		return err
+	}
+
+	if r.hasVersion {
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return ErrVersionConflict
+		}
+	}
+
+	r.written(ctx)
 	return nil
 }
@@ -0,0 +1,281 @@
+package data
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"luxe-beb-go/library/appcontext"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrOutOfScope declare specific error for data outside of the caller's business/outlet scope
+var ErrOutOfScope = fmt.Errorf("data is outside of the allowed business or outlet")
+
+// isTenantScoped tells whether the storage table is restricted by business or outlet
+func (r *MySQLStorage) isTenantScoped() bool {
+	return r.businessColumn != "" || r.businessTable != "" || r.outletColumn != ""
+}
+
+// tenantCondition builds the where condition that restricts the table to the business & outlet
+// allowed in the context. It returns an empty condition when the table is not scoped
+// or when the current user holds the super admin permission.
+func (r *MySQLStorage) tenantCondition(ctx *gin.Context) (string, map[string]interface{}) {
+	args := map[string]interface{}{}
+	if !r.isTenantScoped() || appcontext.IsSuperAdmin(ctx) {
+		return "", args
+	}
+
+	conditions := []string{}
+	if r.businessColumn != "" {
+		businessIDs := appcontext.BusinessIDs(ctx)
+		if len(businessIDs) == 0 {
+			return "FALSE", args
+		}
+
+		if r.sharedBusiness {
+			conditions = append(conditions, fmt.Sprintf("(`%s` IN (:tenant_business_ids) OR `%s` = '')", r.businessColumn, r.businessColumn))
+		} else {
+			conditions = append(conditions, fmt.Sprintf("`%s` IN (:tenant_business_ids)", r.businessColumn))
+		}
+		args["tenant_business_ids"] = businessIDs
+	}
+
+	// a table without business column is scoped through the rows of its link table
+	if r.businessTable != "" {
+		businessIDs := appcontext.BusinessIDs(ctx)
+		if len(businessIDs) == 0 {
+			return "FALSE", args
+		}
+
+		conditions = append(conditions, fmt.Sprintf("`id` IN (SELECT `%s` FROM `%s` WHERE `business_id` IN (:tenant_business_ids))", r.businessKey, r.businessTable))
+		args["tenant_business_ids"] = businessIDs
+	}
+
+	// outlet restriction only applies to users that are assigned to specific outlets
+	if r.outletColumn != "" {
+		outletIDs := appcontext.OutletIDs(ctx)
+		if len(outletIDs) > 0 {
+			conditions = append(conditions, fmt.Sprintf("`%s` IN (:tenant_outlet_ids)", r.outletColumn))
+			args["tenant_outlet_ids"] = outletIDs
+		} else if r.businessColumn == "" && r.businessTable == "" {
+			return "FALSE", args
+		}
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// tenantSource returns the table expression to select from, it is a derived table
+// aliased with the table name when the table is scoped so the caller's where clause stays untouched
+func (r *MySQLStorage) tenantSource(ctx *gin.Context, arg map[string]interface{}) (string, map[string]interface{}) {
+	condition, tenantArgs := r.tenantCondition(ctx)
+	if condition == "" {
+		return fmt.Sprintf("`%s`", r.tableName), arg
+	}
+
+	return fmt.Sprintf("(SELECT * FROM `%s` WHERE %s) AS `%s`", r.tableName, condition, r.tableName), mergeArgs(arg, tenantArgs)
+}
+
+// tenantQuery scopes a custom query by replacing every FROM/JOIN of the storage table with the scoped source.
+// The query has to reference the table by its own name (e.g. "FROM banks JOIN status ON banks.status_id = status.id").
+func (r *MySQLStorage) tenantQuery(ctx *gin.Context, query string, arg map[string]interface{}) (string, map[string]interface{}) {
+	condition, tenantArgs := r.tenantCondition(ctx)
+	if condition == "" {
+		return query, arg
+	}
+
+	pattern := regexp.MustCompile(fmt.Sprintf("(?i)\\b(FROM|JOIN)\\s+`?%s`?([\\s,;)]|$)", regexp.QuoteMeta(r.tableName)))
+	query = pattern.ReplaceAllString(query, fmt.Sprintf("$1 (SELECT * FROM `%s` WHERE %s) AS `%s`$2", r.tableName, condition, r.tableName))
+
+	return query, mergeArgs(arg, tenantArgs)
+}
+
+// checkTenantWrite makes sure the business & outlet of the data written are allowed in the context.
+// An empty business is filled with the current business of the context when there is one,
+// on a table with shared rows it stands for a shared row that only the super admin writes.
+func (r *MySQLStorage) checkTenantWrite(ctx *gin.Context, dbArgs map[string]interface{}, suffix string) error {
+	if !r.isTenantScoped() || appcontext.IsSuperAdmin(ctx) {
+		return nil
+	}
+
+	if r.businessColumn != "" {
+		key := r.businessColumn + suffix
+		businessID := fmt.Sprintf("%v", dbArgs[key])
+		if dbArgs[key] == nil || businessID == "" || businessID == "0" {
+			if r.sharedBusiness {
+				return ErrOutOfScope
+			}
+
+			if currentBusinessID := appcontext.BusinessID(ctx); currentBusinessID != "" {
+				businessID = currentBusinessID
+				dbArgs[key] = businessID
+			}
+		}
+
+		if !containsID(appcontext.BusinessIDs(ctx), businessID) {
+			return ErrOutOfScope
+		}
+	}
+
+	if r.outletColumn != "" {
+		outletIDs := appcontext.OutletIDs(ctx)
+		if len(outletIDs) > 0 && !containsID(outletIDs, fmt.Sprintf("%v", dbArgs[r.outletColumn+suffix])) {
+			return ErrOutOfScope
+		}
+	}
+
+	return nil
+}
+
+// checkTenantRow makes sure an element written by UpdateMany is an existing row visible in the context
+// and that it stays in the allowed business & outlet. An empty business is filled the same way as checkTenantWrite does.
+func (r *MySQLStorage) checkTenantRow(ctx *gin.Context, elem reflect.Value) error {
+	if !r.isTenantScoped() || appcontext.IsSuperAdmin(ctx) {
+		return nil
+	}
+
+	v := reflect.Indirect(elem)
+	dbArgs := map[string]interface{}{}
+	for i := 0; i < v.NumField(); i++ {
+		if dbTag := r.elemType.Field(i).Tag.Get("db"); !emptyTag(dbTag) {
+			dbArgs[dbTag] = v.Field(i).Interface()
+		}
+	}
+
+	err := r.checkTenantAccess(ctx, dbArgs["id"])
+	if err != nil {
+		return err
+	}
+
+	err = r.checkTenantWrite(ctx, dbArgs, "")
+	if err != nil {
+		return err
+	}
+
+	if r.businessColumn != "" {
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Field(i)
+			if r.elemType.Field(i).Tag.Get("db") == r.businessColumn && field.Kind() == reflect.String && field.CanSet() {
+				field.SetString(fmt.Sprintf("%v", dbArgs[r.businessColumn]))
+			}
+		}
+	}
+
+	return nil
+}
+
+// linkTenant links the inserted rows of a table scoped through a link table to the current business of the context,
+// without it the rows would not be visible to anyone but the super admin
+func (r *MySQLStorage) linkTenant(ctx *gin.Context, db Queryer, ids ...interface{}) error {
+	if r.businessTable == "" {
+		return nil
+	}
+
+	businessID := appcontext.BusinessID(ctx)
+	if businessID == "" {
+		if appcontext.IsSuperAdmin(ctx) {
+			return nil
+		}
+		return ErrOutOfScope
+	}
+
+	if !appcontext.IsSuperAdmin(ctx) && !containsID(appcontext.BusinessIDs(ctx), businessID) {
+		return ErrOutOfScope
+	}
+
+	statement, err := db.PrepareNamed(fmt.Sprintf("INSERT INTO `%s` (`%s`, `business_id`) VALUES (:id, :business_id)", r.businessTable, r.businessKey))
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	for _, id := range ids {
+		_, err = statement.Exec(map[string]interface{}{"id": id, "business_id": businessID})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkTenantAccess makes sure the existing row with the given id is visible in the context,
+// a shared row is visible but only the super admin writes it
+func (r *MySQLStorage) checkTenantAccess(ctx *gin.Context, id interface{}) error {
+	if !r.isTenantScoped() || appcontext.IsSuperAdmin(ctx) {
+		return nil
+	}
+
+	var rows []struct {
+		ID         string `db:"id"`
+		BusinessID string `db:"business_id"`
+	}
+	businessColumn := "''"
+	if r.businessColumn != "" {
+		businessColumn = fmt.Sprintf("`%s`", r.businessColumn)
+	}
+	query := fmt.Sprintf("SELECT `id`, %s AS business_id FROM `%s` WHERE `id` = :id", businessColumn, r.tableName)
+	err := r.SelectWithQuery(ctx, &rows, query, map[string]interface{}{"id": id})
+	if err != nil {
+		return err
+	}
+
+	if len(rows) == 0 {
+		return ErrNotFound
+	}
+
+	if r.sharedBusiness && rows[0].BusinessID == "" {
+		return ErrOutOfScope
+	}
+
+	return nil
+}
+
+// elemIDs returns the ids of the elements of a slice or map
+func (r *MySQLStorage) elemIDs(datas reflect.Value) []interface{} {
+	elems := []reflect.Value{}
+	if datas.Kind() == reflect.Slice {
+		for i := 0; i < datas.Len(); i++ {
+			elems = append(elems, datas.Index(i))
+		}
+	}
+	if datas.Kind() == reflect.Map {
+		for _, key := range datas.MapKeys() {
+			elems = append(elems, datas.MapIndex(key))
+		}
+	}
+
+	ids := []interface{}{}
+	for _, elem := range elems {
+		v := reflect.Indirect(elem)
+		for i := 0; i < v.NumField(); i++ {
+			if idTag(r.elemType.Field(i).Tag.Get("db")) {
+				ids = append(ids, v.Field(i).Interface())
+			}
+		}
+	}
+	return ids
+}
+
+func containsID(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func mergeArgs(arg map[string]interface{}, extra map[string]interface{}) map[string]interface{} {
+	res := map[string]interface{}{}
+	for k, v := range arg {
+		res[k] = v
+	}
+	for k, v := range extra {
+		res[k] = v
+	}
+	return res
+}
@@ -0,0 +1,264 @@
+package data
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+// recordDriver runs no query, it records every statement with its args and answers with no rows
+type recordDriver struct {
+	mu         sync.Mutex
+	statements []recordedStatement
+}
+
+type recordedStatement struct {
+	query string
+	args  []driver.Value
+}
+
+type recordConn struct{ driver *recordDriver }
+
+type recordStmt struct {
+	driver *recordDriver
+	query  string
+}
+
+type emptyRows struct{}
+
+var (
+	testDriver     = &recordDriver{}
+	testDriverOnce sync.Once
+)
+
+func (d *recordDriver) Open(name string) (driver.Conn, error) { return &recordConn{driver: d}, nil }
+
+func (d *recordDriver) record(query string, args []driver.Value) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.statements = append(d.statements, recordedStatement{query: query, args: args})
+}
+
+// find returns the first recorded statement containing the text
+func (d *recordDriver) find(text string) (recordedStatement, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, s := range d.statements {
+		if strings.Contains(s.query, text) {
+			return s, true
+		}
+	}
+	return recordedStatement{}, false
+}
+
+func (c *recordConn) Prepare(query string) (driver.Stmt, error) {
+	return &recordStmt{driver: c.driver, query: query}, nil
+}
+
+func (c *recordConn) Close() error { return nil }
+
+func (c *recordConn) Begin() (driver.Tx, error) { return c, nil }
+
+func (c *recordConn) Commit() error { return nil }
+
+func (c *recordConn) Rollback() error { return nil }
+
+func (s *recordStmt) Close() error { return nil }
+
+func (s *recordStmt) NumInput() int { return -1 }
+
+func (s *recordStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.driver.record(s.query, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *recordStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.driver.record(s.query, args)
+	return emptyRows{}, nil
+}
+
+func (emptyRows) Columns() []string { return []string{} }
+
+func (emptyRows) Close() error { return nil }
+
+func (emptyRows) Next(dest []driver.Value) error { return io.EOF }
+
+type tenantUser struct {
+	ID   string `db:"id"`
+	Name string `db:"name"`
+}
+
+type tenantBank struct {
+	ID         string `db:"id"`
+	BusinessID string `db:"business_id"`
+	Name       string `db:"name"`
+}
+
+func newTenantDB(t *testing.T) *sqlx.DB {
+	testDriverOnce.Do(func() {
+		sql.Register("faketenant", testDriver)
+	})
+	testDriver.mu.Lock()
+	testDriver.statements = nil
+	testDriver.mu.Unlock()
+
+	db, err := sqlx.Open("faketenant", "")
+	if err != nil {
+		t.Fatalf("open fake database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func newTenantContext(businessID string, businessIDs []string, isSuperAdmin bool) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/", nil)
+	c.Set("UserID", "admin")
+	c.Set("UserName", "admin")
+	c.Set("BusinessID", businessID)
+	c.Set("BusinessIDs", businessIDs)
+	c.Set("OutletIDs", []string{})
+	c.Set("IsSuperAdmin", isSuperAdmin)
+	return c
+}
+
+func newUserStorage(db *sqlx.DB) *MySQLStorage {
+	return NewMySQLStorage(db, "users", tenantUser{}, MysqlConfig{BusinessTable: "user_businesses", BusinessKey: "user_id"})
+}
+
+func TestSelectScopedThroughLinkTable(t *testing.T) {
+	db := newTenantDB(t)
+	storage := newUserStorage(db)
+
+	users := []tenantUser{}
+	ctx := newTenantContext("business-a", []string{"business-a", "business-b"}, false)
+	err := storage.SelectWithQuery(ctx, &users, "SELECT users.id, users.name FROM users WHERE users.name = :name", map[string]interface{}{"name": "budi"})
+	if err != nil {
+		t.Fatalf("SelectWithQuery() error = %v", err)
+	}
+
+	s, ok := testDriver.find("FROM (SELECT * FROM `users` WHERE `id` IN (SELECT `user_id` FROM `user_businesses` WHERE `business_id` IN (?, ?))) AS `users`")
+	if !ok {
+		t.Fatalf("users are not scoped through user_businesses: %v", testDriver.statements)
+	}
+	if len(s.args) != 3 || s.args[0] != "business-a" || s.args[1] != "business-b" || s.args[2] != "budi" {
+		t.Errorf("args = %v, want [business-a business-b budi]", s.args)
+	}
+}
+
+func TestSelectLinkTableSuperAdmin(t *testing.T) {
+	db := newTenantDB(t)
+	storage := newUserStorage(db)
+
+	users := []tenantUser{}
+	ctx := newTenantContext("", nil, true)
+	err := storage.SelectWithQuery(ctx, &users, "SELECT users.id, users.name FROM users", map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("SelectWithQuery() error = %v", err)
+	}
+
+	if _, ok := testDriver.find("user_businesses"); ok {
+		t.Errorf("super admin query is scoped: %v", testDriver.statements)
+	}
+}
+
+func TestSelectLinkTableWithoutBusiness(t *testing.T) {
+	db := newTenantDB(t)
+	storage := newUserStorage(db)
+
+	users := []tenantUser{}
+	ctx := newTenantContext("", []string{}, false)
+	err := storage.SelectWithQuery(ctx, &users, "SELECT users.id, users.name FROM users", map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("SelectWithQuery() error = %v", err)
+	}
+
+	if _, ok := testDriver.find("WHERE FALSE"); !ok {
+		t.Errorf("user without business sees users: %v", testDriver.statements)
+	}
+}
+
+func TestInsertLinksCurrentBusiness(t *testing.T) {
+	db := newTenantDB(t)
+	storage := newUserStorage(db)
+
+	ctx := newTenantContext("business-a", []string{"business-a"}, false)
+	_, err := storage.Insert(ctx, &tenantUser{ID: "user-1", Name: "budi"})
+	if err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+
+	s, ok := testDriver.find("INSERT INTO `user_businesses`")
+	if !ok {
+		t.Fatalf("inserted user is not linked to the business: %v", testDriver.statements)
+	}
+	if len(s.args) != 2 || s.args[0] != "user-1" || s.args[1] != "business-a" {
+		t.Errorf("args = %v, want [user-1 business-a]", s.args)
+	}
+}
+
+func TestInsertLinkOutOfScope(t *testing.T) {
+	db := newTenantDB(t)
+	storage := newUserStorage(db)
+
+	ctx := newTenantContext("business-c", []string{"business-a"}, false)
+	_, err := storage.Insert(ctx, &tenantUser{ID: "user-1", Name: "budi"})
+	if err != ErrOutOfScope {
+		t.Fatalf("Insert() error = %v, want %v", err, ErrOutOfScope)
+	}
+
+	if _, ok := testDriver.find("INSERT INTO `user_businesses`"); ok {
+		t.Errorf("user is linked to a business out of scope")
+	}
+}
+
+func TestUpdateManyChecksEveryRow(t *testing.T) {
+	db := newTenantDB(t)
+	storage := NewMySQLStorage(db, "banks", tenantBank{}, MysqlConfig{BusinessColumn: "business_id"})
+
+	ctx := newTenantContext("business-a", []string{"business-a"}, false)
+	err := storage.UpdateMany(ctx, []*tenantBank{{ID: "bank-1", BusinessID: "business-a", Name: "BCA"}})
+	if err != ErrNotFound {
+		t.Fatalf("UpdateMany() error = %v, want %v", err, ErrNotFound)
+	}
+
+	if _, ok := testDriver.find("UPDATE"); ok {
+		t.Errorf("row out of scope is updated: %v", testDriver.statements)
+	}
+}
+
+func TestSelectListsSharedRows(t *testing.T) {
+	db := newTenantDB(t)
+	storage := NewMySQLStorage(db, "code_sequences", tenantBank{}, MysqlConfig{BusinessColumn: "business_id", SharedBusiness: true})
+
+	rows := []tenantBank{}
+	ctx := newTenantContext("business-a", []string{"business-a"}, false)
+	err := storage.SelectWithQuery(ctx, &rows, "SELECT code_sequences.id FROM code_sequences", map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("SelectWithQuery() error = %v", err)
+	}
+
+	if _, ok := testDriver.find("WHERE (`business_id` IN (?) OR `business_id` = '')"); !ok {
+		t.Errorf("shared rows are not listed: %v", testDriver.statements)
+	}
+}
+
+func TestInsertSharedRowOutOfScope(t *testing.T) {
+	db := newTenantDB(t)
+	storage := NewMySQLStorage(db, "code_sequences", tenantBank{}, MysqlConfig{BusinessColumn: "business_id", SharedBusiness: true})
+
+	ctx := newTenantContext("business-a", []string{"business-a"}, false)
+	_, err := storage.Insert(ctx, &tenantBank{ID: "sequence-1", Name: "Bagasi"})
+	if err != ErrOutOfScope {
+		t.Fatalf("Insert() error = %v, want %v", err, ErrOutOfScope)
+	}
+}
@@ -0,0 +1,179 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Format tells how the values of a column are written
+type Format string
+
+const (
+	FormatText   Format = ""
+	FormatNumber Format = "number"
+	FormatRupiah Format = "rupiah"
+	FormatDate   Format = "date"
+)
+
+// RupiahColumns are the column name suffixes formatted as rupiah
+var RupiahColumns = []string{"Price", "Amount", "Total", "Nominal", "Balance", "Fee"}
+
+// SensitiveColumns are the column name suffixes never written to a file, e.g. the password hash of a user
+var SensitiveColumns = []string{"Password", "Token", "Secret"}
+
+// Column is a column of the exported table
+type Column struct {
+	Header string
+	Format Format
+}
+
+// Table is the data to export, each row holds a value for every column
+type Table struct {
+	Name    string
+	Columns []Column
+	Rows    [][]interface{}
+}
+
+// FromJSON builds the table from a json array of objects, e.g. the Data of a list response.
+// The columns follow the order of the keys in the objects, nested objects are flattened
+// with the keys prefixed ("Status.Name") and arrays are joined by their Name or value.
+// The keys ending with one of the SensitiveColumns are left out.
+func FromJSON(name string, data []byte) (*Table, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	value, err := decodeOrdered(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("export: data is not an array")
+	}
+
+	t := &Table{Name: name}
+	columnIndex := map[string]int{}
+	rows := []map[string]interface{}{}
+	for _, item := range items {
+		row := map[string]interface{}{}
+		flatten(item, "", func(key string, v interface{}) {
+			if isSensitive(key) {
+				return
+			}
+			if _, ok := columnIndex[key]; !ok {
+				columnIndex[key] = len(t.Columns)
+				t.Columns = append(t.Columns, Column{Header: key, Format: jsonColumnFormat(key, v)})
+			}
+			row[key] = v
+		})
+		rows = append(rows, row)
+	}
+
+	for _, r := range rows {
+		row := make([]interface{}, len(t.Columns))
+		for key, v := range r {
+			row[columnIndex[key]] = v
+		}
+		t.Rows = append(t.Rows, row)
+	}
+
+	return t, nil
+}
+
+func isSensitive(key string) bool {
+	for _, suffix := range SensitiveColumns {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonColumnFormat(key string, v interface{}) Format {
+	if _, ok := v.(json.Number); !ok {
+		return FormatText
+	}
+
+	for _, suffix := range RupiahColumns {
+		if strings.HasSuffix(key, suffix) {
+			return FormatRupiah
+		}
+	}
+	return FormatNumber
+}
+
+// orderedObject is a json object keeping the order of its keys
+type orderedObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func decodeOrdered(dec *json.Decoder) (interface{}, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		obj := orderedObject{values: map[string]interface{}{}}
+		for dec.More() {
+			keyToken, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := keyToken.(string)
+
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := obj.values[key]; !ok {
+				obj.keys = append(obj.keys, key)
+			}
+			obj.values[key] = value
+		}
+		_, err = dec.Token()
+		return obj, err
+	case json.Delim('['):
+		arr := []interface{}{}
+		for dec.More() {
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		_, err = dec.Token()
+		return arr, err
+	}
+
+	return token, nil
+}
+
+func flatten(v interface{}, prefix string, set func(key string, v interface{})) {
+	switch value := v.(type) {
+	case orderedObject:
+		for _, key := range value.keys {
+			flatten(value.values[key], prefix+key+".", set)
+		}
+	case []interface{}:
+		parts := []string{}
+		for _, item := range value {
+			if obj, ok := item.(orderedObject); ok {
+				if name, ok := obj.values["Name"]; ok {
+					parts = append(parts, fmt.Sprintf("%v", name))
+					continue
+				}
+			}
+			parts = append(parts, fmt.Sprintf("%v", item))
+		}
+		set(strings.TrimSuffix(prefix, "."), strings.Join(parts, ", "))
+	default:
+		set(strings.TrimSuffix(prefix, "."), value)
+	}
+}
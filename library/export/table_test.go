@@ -0,0 +1,34 @@
+package export
+
+import (
+	"testing"
+)
+
+func TestFromJSONLeavesOutSensitiveColumns(t *testing.T) {
+	data := []byte(`[{"ID":"1","Username":"budi","Password":"5f4dcc3b5aa765d61d8327deb882cf99","Status":{"ID":"1","Name":"Active"}}]`)
+
+	table, err := FromJSON("users", data)
+	if err != nil {
+		t.Fatalf("FromJSON() error = %v", err)
+	}
+
+	headers := []string{}
+	for _, c := range table.Columns {
+		headers = append(headers, c.Header)
+	}
+	want := []string{"ID", "Username", "Status.ID", "Status.Name"}
+	if len(headers) != len(want) {
+		t.Fatalf("headers = %v, want %v", headers, want)
+	}
+	for i := range want {
+		if headers[i] != want[i] {
+			t.Fatalf("headers = %v, want %v", headers, want)
+		}
+	}
+
+	for _, v := range table.Rows[0] {
+		if v == "5f4dcc3b5aa765d61d8327deb882cf99" {
+			t.Errorf("row %v holds the password hash", table.Rows[0])
+		}
+	}
+}
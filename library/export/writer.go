@@ -0,0 +1,145 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"luxe-beb-go/library/helpers"
+	"luxe-beb-go/library/xlsx"
+)
+
+const dateFormat = "2006-01-02 15:04:05"
+
+// WriteXLSX streams the table as an xlsx workbook with a header row and typed cells
+func (t *Table) WriteXLSX(w io.Writer) error {
+	name := t.Name
+	if name == "" {
+		name = "Sheet1"
+	}
+
+	xw, err := xlsx.NewWriter(w, name)
+	if err != nil {
+		return err
+	}
+
+	headers := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		headers[i] = c.Header
+	}
+	if err := xw.WriteHeader(headers); err != nil {
+		return err
+	}
+
+	for _, row := range t.Rows {
+		cells := make([]xlsx.Cell, len(row))
+		for i, v := range row {
+			cells[i] = xlsx.Cell{Value: v, Style: cellStyle(t.Columns[i].Format, v)}
+		}
+		if err := xw.WriteRow(cells); err != nil {
+			return err
+		}
+	}
+
+	return xw.Close()
+}
+
+// WriteCSV streams the table as csv, rupiah columns are written with helpers.ConvertRupiah
+func (t *Table) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	headers := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		headers[i] = c.Header
+	}
+	if err := cw.Write(headers); err != nil {
+		return err
+	}
+
+	for _, row := range t.Rows {
+		record := make([]string, len(row))
+		for i, v := range row {
+			record[i] = textValue(t.Columns[i].Format, v)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func cellStyle(format Format, v interface{}) xlsx.Style {
+	switch format {
+	case FormatRupiah:
+		return xlsx.StyleRupiah
+	case FormatDate:
+		return xlsx.StyleDate
+	case FormatNumber:
+		if f, ok := number(v); ok && f != math.Trunc(f) {
+			return xlsx.StyleDecimal
+		}
+		return xlsx.StyleNumber
+	}
+	return xlsx.StyleDefault
+}
+
+func textValue(format Format, v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return value.Format(dateFormat)
+	case string:
+		return value
+	}
+
+	if format == FormatRupiah {
+		if f, ok := number(v); ok {
+			return helpers.ConvertRupiah(int(math.Round(f)), true)
+		}
+	}
+
+	return fmt.Sprintf("%v", v)
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
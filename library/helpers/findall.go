@@ -75,6 +75,7 @@ func FilterFindAllParam(c *gin.Context) types.FindAllParams {
 	var statusID string
 	var businessID string
 	var outletID string
+	var Businesses []string
 	var Outlets []string
 	var sort string
 	var op string
@@ -82,7 +83,7 @@ func FilterFindAllParam(c *gin.Context) types.FindAllParams {
 	userType := appcontext.Type(c)
 	if userType != nil {
 		if *userType != "Web" {
-			businessID = fmt.Sprintf("%d", appcontext.BusinessID(c))
+			businessID = appcontext.BusinessID(c)
 		}
 
 	}
@@ -91,12 +92,12 @@ func FilterFindAllParam(c *gin.Context) types.FindAllParams {
 		businessID = fmt.Sprintf("%v", c.Query("BusinessID"))
 	}
 
-	outletID = fmt.Sprintf("%d", appcontext.OutletID(c))
+	outletID = appcontext.OutletID(c)
 	if c.Query("OutletID") != "" {
 		outletID = fmt.Sprintf("%v", c.Query("OutletID"))
 	}
 
-	findallparams := types.FindAllParams{-1, 10, "", "code", "desc", "", "", "", Outlets}
+	findallparams := types.FindAllParams{-1, 10, "", "code", "desc", "", "", "", Businesses, Outlets}
 	sortName := Underscore(c.Query("SortName"))
 	sortBy := strings.ToLower(c.Query("SortBy"))
 
@@ -114,10 +115,16 @@ func FilterFindAllParam(c *gin.Context) types.FindAllParams {
 		statusID = c.Query("StatusID")
 	}
 
-	explodeOutlets := strings.Split(outletID, ",")
-	for _, vOutlet := range explodeOutlets {
-		if vOutlet != "-1" && vOutlet != "" {
-			Outlets = append(Outlets, vOutlet)
+	Businesses = splitIDs(businessID)
+	Outlets = splitIDs(outletID)
+
+	// query param hanya boleh mempersempit akses business & outlet user
+	if !appcontext.IsSuperAdmin(c) {
+		if appcontext.BusinessIDs(c) != nil {
+			Businesses = restrictIDs(Businesses, appcontext.BusinessIDs(c))
+		}
+		if len(appcontext.OutletIDs(c)) > 0 {
+			Outlets = restrictIDs(Outlets, appcontext.OutletIDs(c))
 		}
 	}
 
@@ -133,23 +140,15 @@ func FilterFindAllParam(c *gin.Context) types.FindAllParams {
 		}
 	}
 
-	bID := MultiValueFilterCheck(businessID) // make sure its all int
-	explodeBusiness := strings.Split(bID, ",")
-	for _, b := range explodeBusiness {
-		if b != "-1" && b != "" && b != "0" {
-			JoinStringBusiness := strings.Join(explodeBusiness, ",")
-			businessID = "business_id IN (" + JoinStringBusiness + ")"
-			break
-		} else {
-			businessID = ""
-			break
-		}
+	// id business & outlet berupa uuid, nilainya di-bind lewat :business_ids & :outlet_ids
+	businessID = ""
+	if len(Businesses) > 0 {
+		businessID = "business_id IN (:business_ids)"
 	}
 
-	if outletID != "-1" && outletID != "" && outletID != "0" {
-		outletID = op + " outlet_id = " + outletID
-	} else {
-		outletID = ""
+	outletID = ""
+	if len(Outlets) > 0 {
+		outletID = op + " outlet_id IN (:outlet_ids)"
 	}
 
 	if sortName != "" {
@@ -159,9 +158,37 @@ func FilterFindAllParam(c *gin.Context) types.FindAllParams {
 	dataFinder := DataFinder(c.Query("KeywordName"), c.Query("Keyword"))
 	page, _ := strconv.Atoi(c.Query("Page"))
 	size, _ := strconv.Atoi(c.Query("Size"))
-	findallparams = types.FindAllParams{Page: page, Size: size, StatusID: statusID, DataFinder: dataFinder, SortName: sortName, SortBy: sort, BusinessID: businessID, OutletID: outletID, Outlets: Outlets}
+	findallparams = types.FindAllParams{Page: page, Size: size, StatusID: statusID, DataFinder: dataFinder, SortName: sortName, SortBy: sort, BusinessID: businessID, OutletID: outletID, Businesses: Businesses, Outlets: Outlets}
 	return findallparams
 }
+
+// splitIDs splits the comma separated ids of a filter, the wildcard "-1" and the empty id "0" are left out
+func splitIDs(ids string) []string {
+	var res []string
+	for _, id := range strings.Split(ids, ",") {
+		id = strings.TrimSpace(id)
+		if id != "" && id != "-1" && id != "0" {
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+// restrictIDs keeps only the ids that are part of the allowed ids,
+// no id left means no filter since the storage already limits the data
+func restrictIDs(ids []string, allowed []string) []string {
+	var res []string
+	for _, id := range ids {
+		for _, a := range allowed {
+			if id == a {
+				res = append(res, id)
+				break
+			}
+		}
+	}
+	return res
+}
+
 func sanitize(text string) string {
 	return strings.NewReplacer("'", "", `"`, "").Replace(text)
 }
@@ -0,0 +1,93 @@
+package helpers
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	businessA = "0b9f6f3e-7c1a-4d7e-9d4b-3b1f1e5a2c01"
+	businessB = "5d2c8a41-2f6e-4b8a-a7c3-9e0d4f6b1a02"
+	outletA   = "a3e1c7d2-6b4f-4e8a-9c1d-2f7b5e8a3c11"
+	outletB   = "c8f2a6e4-1d3b-4a7c-8e5f-6b9d0c2e4a12"
+)
+
+func newFindAllContext(query string, businessIDs []string, outletIDs []string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/outlets?"+query, nil)
+	c.Set("BusinessIDs", businessIDs)
+	c.Set("OutletIDs", outletIDs)
+	return c
+}
+
+func TestFilterFindAllParamOutlets(t *testing.T) {
+	cases := []struct {
+		name      string
+		query     string
+		outletIDs []string
+		want      string
+		outlets   []string
+	}{
+		{"one outlet", "OutletID=3", nil, " outlet_id IN (:outlet_ids)", []string{"3"}},
+		{"many outlets", "OutletID=3,4", nil, " outlet_id IN (:outlet_ids)", []string{"3", "4"}},
+		{"restricted outlets", "OutletID=3,4,5", []string{"3", "5"}, " outlet_id IN (:outlet_ids)", []string{"3", "5"}},
+		{"uuid outlets", "OutletID=" + outletA + "," + outletB, nil, " outlet_id IN (:outlet_ids)", []string{outletA, outletB}},
+		{"restricted uuid outlets", "OutletID=" + outletA + "," + outletB, []string{outletB}, " outlet_id IN (:outlet_ids)", []string{outletB}},
+		{"no allowed outlet", "OutletID=4", []string{"3"}, "", nil},
+		{"no filter", "", nil, "", nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := FilterFindAllParam(newFindAllContext(tc.query, []string{"1"}, tc.outletIDs))
+			if params.OutletID != tc.want {
+				t.Errorf("OutletID = %q, want %q", params.OutletID, tc.want)
+			}
+			if !reflect.DeepEqual(params.Outlets, tc.outlets) {
+				t.Errorf("Outlets = %v, want %v", params.Outlets, tc.outlets)
+			}
+		})
+	}
+}
+
+func TestFilterFindAllParamBusinesses(t *testing.T) {
+	cases := []struct {
+		name       string
+		query      string
+		want       string
+		businesses []string
+	}{
+		{"one business", "BusinessID=" + businessA, "business_id IN (:business_ids)", []string{businessA}},
+		{"many businesses", "BusinessID=" + businessA + ",%20" + businessB, "business_id IN (:business_ids)", []string{businessA, businessB}},
+		{"restricted businesses", "BusinessID=" + businessA + ",9e8d7c6b-5a49-4382-b1a0-f9e8d7c6b5a4", "business_id IN (:business_ids)", []string{businessA}},
+		{"no allowed business", "BusinessID=9e8d7c6b-5a49-4382-b1a0-f9e8d7c6b5a4", "", nil},
+		{"no filter", "", "", nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := FilterFindAllParam(newFindAllContext(tc.query, []string{businessA, businessB}, nil))
+			if params.BusinessID != tc.want {
+				t.Errorf("BusinessID = %q, want %q", params.BusinessID, tc.want)
+			}
+			if !reflect.DeepEqual(params.Businesses, tc.businesses) {
+				t.Errorf("Businesses = %v, want %v", params.Businesses, tc.businesses)
+			}
+		})
+	}
+}
+
+func TestFilterFindAllParamCurrentBusiness(t *testing.T) {
+	c := newFindAllContext("", []string{businessA}, nil)
+	c.Set("Type", "POS")
+	c.Set("BusinessID", businessA)
+
+	params := FilterFindAllParam(c)
+	if !reflect.DeepEqual(params.Businesses, []string{businessA}) {
+		t.Errorf("Businesses = %v, want %v", params.Businesses, []string{businessA})
+	}
+}
@@ -0,0 +1,80 @@
+package request
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/library/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Bind reads the request body (json, form or multipart) into obj based on the content type,
+// then validates obj with its `validate` tags
+func Bind(c *gin.Context, obj interface{}) *types.Error {
+	b := binding.Default(c.Request.Method, c.ContentType())
+
+	errBind := c.ShouldBindWith(obj, b)
+	if errBind != nil && errBind != io.EOF {
+		return &types.Error{
+			Path:       ".Bind()",
+			Message:    errBind.Error(),
+			Error:      errBind,
+			StatusCode: http.StatusBadRequest,
+			Type:       "bind-error",
+		}
+	}
+
+	errValidation := validator.Shared().StructCtx(c, obj)
+	if errValidation != nil {
+		return &types.Error{
+			Path:       ".Bind()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	return nil
+}
+
+// IfMatch returns the version the row was read at, from the If-Match header or else the version given in the body.
+// An update without any of them is refused with 428 so a row is never overwritten blindly
+func IfMatch(c *gin.Context, version int) (int, *types.Error) {
+	ifMatch := strings.TrimSpace(c.GetHeader("If-Match"))
+	if ifMatch == "" {
+		if version > 0 {
+			return version, nil
+		}
+
+		err := fmt.Errorf("header If-Match atau Version wajib diisi")
+		return 0, &types.Error{
+			Path:       ".IfMatch()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusPreconditionRequired,
+			Type:       "validation-error",
+		}
+	}
+
+	etag := strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`)
+	result, errParse := strconv.Atoi(etag)
+	if errParse != nil || result < 1 {
+		err := fmt.Errorf("header If-Match tidak valid: %s", ifMatch)
+		return 0, &types.Error{
+			Path:       ".IfMatch()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusBadRequest,
+			Type:       "validation-error",
+		}
+	}
+
+	return result, nil
+}
@@ -69,19 +69,17 @@ func Error(c *gin.Context, n notif.Notifier, data string, status int, err types.
 		errorCode = "InternalServerError"
 	case http.StatusNotImplemented:
 		errorCode = "NotImplemented"
+	case http.StatusPreconditionFailed:
+		errorCode = "PreconditionFailed"
+	case http.StatusPreconditionRequired:
+		errorCode = "PreconditionRequired"
 	}
 
 	errorFields := []*FieldError{}
 
 	switch err.Error.(type) {
 	case validator.ValidationErrors:
-		for _, err := range err.Error.(validator.ValidationErrors) {
-			e := MakeFieldError(
-				err.Field(),
-				err.ActualTag())
-
-			errorFields = append(errorFields, e)
-		}
+		errorFields = FieldErrors(c, err.Error.(validator.ValidationErrors))
 
 		data = "Unprocessable Entity"
 		errorCode = "UnprocessableEntity"
@@ -144,7 +142,7 @@ func AdvancedError(w http.ResponseWriter, slackNotifier notif.Notifier, logNotif
 	case validator.ValidationErrors:
 		for _, err := range err.Error.(validator.ValidationErrors) {
 			errorFields = append(errorFields,
-				MakeFieldError(err.Field(), err.ActualTag()))
+				MakeFieldError(err.Field(), FieldErrorMessage(err, "id")))
 		}
 		errorCode = "BadRequest"
 		status = http.StatusBadRequest
@@ -0,0 +1,25 @@
+package response
+
+import (
+	"fmt"
+	"net/http"
+
+	"luxe-beb-go/library/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETag sets the ETag header to the version of the row, it is sent back in If-Match to update it
+func ETag(c *gin.Context, version int) {
+	c.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// PreconditionFailed answers an update made from an outdated version with 412 and the current state of the row
+func PreconditionFailed(c *gin.Context, message string, version int, current interface{}) {
+	ETag(c, version)
+
+	dataresponse := types.Result{Status: "Warning", StatusCode: http.StatusPreconditionFailed, Message: message, Data: current}
+	c.JSON(http.StatusPreconditionFailed, gin.H{
+		"result": dataresponse,
+	})
+}
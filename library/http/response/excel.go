@@ -2,16 +2,41 @@ package response
 
 import (
 	"fmt"
+	"log"
 	"net/http"
 	"strings"
+
+	"luxe-beb-go/library/export"
 )
 
-// EXCEL writes excel http response
+// EXCEL writes excel http response, an *export.Table is streamed as a real xlsx workbook
 func EXCEL(w http.ResponseWriter, status int, data interface{}, filename string) {
 	w.Header().Set("Content-Type", "application/octet-stream")
 	w.Header().Set("Content-Disposition", "attachment; filename="+filename+".xlsx")
 	w.Header().Set("Content-Transfer-Encoding", "binary")
 	w.Header().Set("Expires", "0")
+
+	if table, ok := data.(*export.Table); ok {
+		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		w.WriteHeader(status)
+		if err := table.WriteXLSX(w); err != nil {
+			log.Printf("ERROR: writing xlsx %s: %v\n", filename, err)
+		}
+		return
+	}
+
 	writer := strings.NewReader(string([]byte(fmt.Sprintf("%s", data))))
 	writer.WriteTo(w)
 }
+
+// CSV writes csv http response
+func CSV(w http.ResponseWriter, status int, table *export.Table, filename string) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename+".csv")
+	w.Header().Set("Expires", "0")
+	w.WriteHeader(status)
+
+	if err := table.WriteCSV(w); err != nil {
+		log.Printf("ERROR: writing csv %s: %v\n", filename, err)
+	}
+}
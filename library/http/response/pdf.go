@@ -9,5 +9,11 @@ import (
 func PDF(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/pdf")
 	w.WriteHeader(status)
+
+	// raw bytes are written as is, fmt would print them as a list of numbers
+	if b, ok := data.([]byte); ok {
+		w.Write(b)
+		return
+	}
 	fmt.Fprint(w, data)
 }
@@ -0,0 +1,107 @@
+package response
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+// validationMessages holds the message of each validation tag per language,
+// %[1]s is replaced by the field name and %[2]s by the tag param
+var validationMessages = map[string]map[string]string{
+	"id": {
+		"required":      "%[1]s wajib diisi",
+		"email":         "%[1]s harus berupa alamat email yang valid",
+		"min":           "%[1]s minimal %[2]s",
+		"max":           "%[1]s maksimal %[2]s",
+		"len":           "%[1]s harus %[2]s",
+		"gte":           "%[1]s harus lebih besar atau sama dengan %[2]s",
+		"lte":           "%[1]s harus lebih kecil atau sama dengan %[2]s",
+		"gt":            "%[1]s harus lebih besar dari %[2]s",
+		"lt":            "%[1]s harus lebih kecil dari %[2]s",
+		"oneof":         "%[1]s harus salah satu dari [%[2]s]",
+		"numeric":       "%[1]s harus berupa angka",
+		"eqfield":       "%[1]s harus sama dengan %[2]s",
+		"url":           "%[1]s harus berupa URL yang valid",
+		"uuid":          "%[1]s harus berupa UUID yang valid",
+		"phone_id":      "%[1]s harus berupa nomor telepon Indonesia yang valid",
+		"npwp":          "%[1]s harus berupa NPWP 15 atau 16 digit",
+		"bank_account":  "%[1]s harus berupa nomor rekening 6 sampai 20 digit",
+		"unique":        "%[1]s tidak boleh berisi nilai yang sama",
+		"unique_in":     "%[1]s sudah digunakan",
+		"required_if":   "%[1]s wajib diisi",
+		"regexp":        "%[1]s harus berupa regular expression yang valid",
+		"pattern_field": "%[1]s tidak sesuai dengan format yang ditentukan",
+		"clock":         "%[1]s harus berupa jam dengan format HH:MM",
+		"text_template": "%[1]s harus berupa template yang valid",
+		"default":       "%[1]s tidak valid",
+	},
+	"en": {
+		"required":      "%[1]s is required",
+		"email":         "%[1]s must be a valid email address",
+		"min":           "%[1]s must be at least %[2]s",
+		"max":           "%[1]s must be at most %[2]s",
+		"len":           "%[1]s must be %[2]s",
+		"gte":           "%[1]s must be greater than or equal to %[2]s",
+		"lte":           "%[1]s must be less than or equal to %[2]s",
+		"gt":            "%[1]s must be greater than %[2]s",
+		"lt":            "%[1]s must be less than %[2]s",
+		"oneof":         "%[1]s must be one of [%[2]s]",
+		"numeric":       "%[1]s must be a number",
+		"eqfield":       "%[1]s must be equal to %[2]s",
+		"url":           "%[1]s must be a valid URL",
+		"uuid":          "%[1]s must be a valid UUID",
+		"phone_id":      "%[1]s must be a valid Indonesian phone number",
+		"npwp":          "%[1]s must be an NPWP of 15 or 16 digits",
+		"bank_account":  "%[1]s must be a bank account number of 6 to 20 digits",
+		"unique":        "%[1]s must not contain duplicate values",
+		"unique_in":     "%[1]s is already used",
+		"required_if":   "%[1]s is required",
+		"regexp":        "%[1]s must be a valid regular expression",
+		"pattern_field": "%[1]s does not match the required format",
+		"clock":         "%[1]s must be a time of the day as HH:MM",
+		"text_template": "%[1]s must be a valid template",
+		"default":       "%[1]s is invalid",
+	},
+}
+
+// Language gets the language of the messages from the Accept-Language header, Indonesian by default
+func Language(c *gin.Context) string {
+	if c == nil || c.Request == nil {
+		return "id"
+	}
+
+	if strings.HasPrefix(strings.ToLower(c.GetHeader("Accept-Language")), "en") {
+		return "en"
+	}
+	return "id"
+}
+
+// FieldErrorMessage makes a human-friendly message of a validation error in the given language
+func FieldErrorMessage(fe validator.FieldError, lang string) string {
+	messages, ok := validationMessages[lang]
+	if !ok {
+		messages = validationMessages["id"]
+	}
+
+	message, ok := messages[fe.Tag()]
+	if !ok {
+		message = messages["default"]
+	}
+
+	return fmt.Sprintf(message, fe.Field(), fe.Param())
+}
+
+// FieldErrors converts validation errors into field errors with messages in the language of the request
+func FieldErrors(c *gin.Context, errs validator.ValidationErrors) []*FieldError {
+	lang := Language(c)
+
+	errorFields := []*FieldError{}
+	for _, err := range errs {
+		errorFields = append(errorFields, MakeFieldError(err.Field(), FieldErrorMessage(err, lang)))
+	}
+
+	return errorFields
+}
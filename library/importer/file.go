@@ -0,0 +1,178 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"luxe-beb-go/library/xlsx"
+)
+
+// MaxFileSize is the biggest file accepted for an import
+const MaxFileSize = 10 << 20
+
+var (
+	// ErrUnsupportedFile declare specific error for a file that is neither csv nor xlsx
+	ErrUnsupportedFile = fmt.Errorf("file must be a .csv or .xlsx file")
+
+	// ErrFileTooLarge declare specific error for a file bigger than MaxFileSize
+	ErrFileTooLarge = fmt.Errorf("file must not be larger than %d MB", MaxFileSize>>20)
+
+	// ErrEmptyFile declare specific error for a file without any data row
+	ErrEmptyFile = fmt.Errorf("file has no data row")
+)
+
+// Row is a data row of the imported file, the values are keyed by the column header
+type Row struct {
+	Number int
+	Values map[string]string
+}
+
+// Get returns the value of the column, the header is matched case insensitively
+func (r Row) Get(column string) string {
+	return r.Values[normalizeHeader(column)]
+}
+
+// ReadFile reads the uploaded csv or xlsx file, the first row is the header
+// and every following non empty row is a data row numbered as in the file
+func ReadFile(fh *multipart.FileHeader) ([]Row, error) {
+	if fh.Size > MaxFileSize {
+		return nil, ErrFileTooLarge
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	content, err := io.ReadAll(io.LimitReader(f, MaxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > MaxFileSize {
+		return nil, ErrFileTooLarge
+	}
+
+	var records [][]string
+	switch strings.ToLower(filepath.Ext(fh.Filename)) {
+	case ".csv":
+		reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		records, err = reader.ReadAll()
+	case ".xlsx":
+		records, err = xlsx.ReadRows(bytes.NewReader(content), int64(len(content)))
+	default:
+		return nil, ErrUnsupportedFile
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toRows(records)
+}
+
+func toRows(records [][]string) ([]Row, error) {
+	if len(records) < 2 {
+		return nil, ErrEmptyFile
+	}
+
+	headers := make([]string, len(records[0]))
+	for i, h := range records[0] {
+		headers[i] = normalizeHeader(h)
+	}
+
+	rows := []Row{}
+	for i, record := range records[1:] {
+		values := map[string]string{}
+		empty := true
+		for j, v := range record {
+			if j >= len(headers) || headers[j] == "" {
+				continue
+			}
+
+			v = strings.TrimSpace(v)
+			if v != "" {
+				empty = false
+			}
+			values[headers[j]] = v
+		}
+
+		if empty {
+			continue
+		}
+
+		rows = append(rows, Row{Number: i + 2, Values: values})
+	}
+
+	if len(rows) == 0 {
+		return nil, ErrEmptyFile
+	}
+
+	return rows, nil
+}
+
+// Decode fills the fields of the struct pointed by obj from the row,
+// each field is read from the column named by its form tag (or json tag when there is none)
+func Decode(row Row, obj interface{}) error {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("importer: decode target must be a pointer to struct")
+	}
+	v = v.Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		column := strings.SplitN(field.Tag.Get("form"), ",", 2)[0]
+		if column == "" {
+			column = strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		}
+		if column == "" || column == "-" {
+			continue
+		}
+
+		value, ok := row.Values[normalizeHeader(column)]
+		if !ok || value == "" {
+			continue
+		}
+
+		fv := v.Field(i)
+		switch fv.Kind() {
+		case reflect.String:
+			fv.SetString(value)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(strings.ToLower(value))
+			if err != nil {
+				return fmt.Errorf("%s must be true or false", column)
+			}
+			fv.SetBool(b)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("%s must be a number", column)
+			}
+			fv.SetInt(n)
+		case reflect.Float32, reflect.Float64:
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("%s must be a number", column)
+			}
+			fv.SetFloat(n)
+		}
+	}
+
+	return nil
+}
+
+// normalizeHeader makes "Account Number", "account_number" and "AccountNumber" the same column
+func normalizeHeader(header string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "", "\ufeff", "").Replace(strings.TrimSpace(header)))
+}
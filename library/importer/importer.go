@@ -0,0 +1,235 @@
+package importer
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/http/response"
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/library/validator"
+
+	"github.com/gin-gonic/gin"
+	playground "gopkg.in/go-playground/validator.v9"
+)
+
+// Mode decides what happens to the valid rows when some rows fail
+type Mode string
+
+const (
+	// ModeAtomic imports every row in a single transaction, one failed row rolls back the whole file
+	ModeAtomic Mode = "atomic"
+	// ModePartial commits each valid row on its own and skips the failed ones
+	ModePartial Mode = "partial"
+
+	RowStatusSuccess = "success"
+	RowStatusFailed  = "failed"
+)
+
+// errRollback rolls back the transaction of an atomic import with failed rows or of a dry run
+var errRollback = fmt.Errorf("import rolled back")
+
+// Options are the choices of the caller for an import
+type Options struct {
+	Mode   Mode
+	DryRun bool
+}
+
+// RowResult is the outcome of a row of the file
+type RowResult struct {
+	Row     int                    `json:"Row"`
+	Status  string                 `json:"Status"`
+	ID      string                 `json:"ID,omitempty"`
+	Message string                 `json:"Message,omitempty"`
+	Fields  []*response.FieldError `json:"Fields,omitempty"`
+}
+
+// Report is the row level report of an import
+type Report struct {
+	Mode      Mode         `json:"Mode"`
+	DryRun    bool         `json:"DryRun"`
+	Committed bool         `json:"Committed"`
+	TotalRows int          `json:"TotalRows"`
+	Succeeded int          `json:"Succeeded"`
+	Failed    int          `json:"Failed"`
+	Rows      []*RowResult `json:"Rows"`
+}
+
+// RowFunc imports a single row and returns the id of the created data
+type RowFunc func(tctx *gin.Context, row Row) (string, *types.Error)
+
+// Importer runs the rows of an uploaded file through a RowFunc inside transactions
+type Importer struct {
+	dataManager *data.Manager
+}
+
+// New creates a new importer
+func New(dataManager *data.Manager) *Importer {
+	return &Importer{dataManager: dataManager}
+}
+
+// OptionsFromRequest reads the Mode (atomic by default) and DryRun fields from the form or the query
+func OptionsFromRequest(c *gin.Context) (Options, *types.Error) {
+	options := Options{Mode: ModeAtomic}
+
+	mode := strings.ToLower(c.DefaultPostForm("Mode", c.Query("Mode")))
+	switch Mode(mode) {
+	case "", ModeAtomic:
+	case ModePartial:
+		options.Mode = ModePartial
+	default:
+		err := fmt.Errorf("Mode must be one of [%s %s]", ModeAtomic, ModePartial)
+		return options, &types.Error{
+			Path:       ".Importer->OptionsFromRequest()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusBadRequest,
+			Type:       "bind-error",
+		}
+	}
+
+	if dryRun := c.DefaultPostForm("DryRun", c.Query("DryRun")); dryRun != "" {
+		b, err := strconv.ParseBool(dryRun)
+		if err != nil {
+			err = fmt.Errorf("DryRun must be true or false")
+			return options, &types.Error{
+				Path:       ".Importer->OptionsFromRequest()",
+				Message:    err.Error(),
+				Error:      err,
+				StatusCode: http.StatusBadRequest,
+				Type:       "bind-error",
+			}
+		}
+		options.DryRun = b
+	}
+
+	return options, nil
+}
+
+// ReadRequest reads the rows of the file uploaded in the multipart field
+func ReadRequest(c *gin.Context, field string) ([]Row, *types.Error) {
+	fh, err := c.FormFile(field)
+	if err != nil {
+		err = fmt.Errorf("%s must be an uploaded .csv or .xlsx file", field)
+		return nil, &types.Error{
+			Path:       ".Importer->ReadRequest()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusBadRequest,
+			Type:       "bind-error",
+		}
+	}
+
+	rows, err := ReadFile(fh)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".Importer->ReadRequest()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusBadRequest,
+			Type:       "bind-error",
+		}
+	}
+
+	return rows, nil
+}
+
+// Run imports the rows with f and reports the result of every row.
+// An atomic import runs in one transaction that is only committed when every row succeeds,
+// a partial import commits each row in its own transaction,
+// and a dry run goes through every row the same way but never commits anything.
+func (i *Importer) Run(c *gin.Context, rows []Row, options Options, f RowFunc) (*Report, *types.Error) {
+	report := &Report{
+		Mode:      options.Mode,
+		DryRun:    options.DryRun,
+		TotalRows: len(rows),
+		Rows:      make([]*RowResult, 0, len(rows)),
+	}
+
+	importRow := func(tctx *gin.Context, row Row) {
+		result := &RowResult{Row: row.Number, Status: RowStatusSuccess}
+
+		id, err := f(tctx, row)
+		if err != nil {
+			result.Status = RowStatusFailed
+			result.Message = err.Message
+			if errs, ok := err.Error.(playground.ValidationErrors); ok {
+				result.Message = "Unprocessable Entity"
+				result.Fields = response.FieldErrors(c, errs)
+			}
+			report.Failed++
+		} else {
+			result.ID = id
+			report.Succeeded++
+		}
+
+		report.Rows = append(report.Rows, result)
+	}
+
+	// a partial import commits row by row, unless it is a dry run which runs in one rolled back transaction
+	if options.Mode == ModePartial && !options.DryRun {
+		for _, row := range rows {
+			row := row
+			errTransaction := i.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+				importRow(tctx, row)
+				if report.Rows[len(report.Rows)-1].Status == RowStatusFailed {
+					return &types.Error{Error: errRollback}
+				}
+				return nil
+			})
+			if errTransaction != nil && errTransaction.Error != errRollback {
+				errTransaction.Path = ".Importer->Run()" + errTransaction.Path
+				return nil, errTransaction
+			}
+		}
+
+		report.Committed = report.Succeeded > 0
+		return report, nil
+	}
+
+	errTransaction := i.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		for _, row := range rows {
+			importRow(tctx, row)
+		}
+
+		if options.DryRun || report.Failed > 0 {
+			return &types.Error{Error: errRollback}
+		}
+		return nil
+	})
+	if errTransaction != nil && errTransaction.Error != errRollback {
+		errTransaction.Path = ".Importer->Run()" + errTransaction.Path
+		return nil, errTransaction
+	}
+
+	report.Committed = errTransaction == nil
+	return report, nil
+}
+
+// DecodeRequest decodes the row into the request DTO and validates it with the shared rules,
+// the errors have the shape returned by the usecases so they end up in the row report the same way
+func DecodeRequest(tctx *gin.Context, row Row, obj interface{}) *types.Error {
+	if err := Decode(row, obj); err != nil {
+		return &types.Error{
+			Path:       ".Importer->DecodeRequest()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	if err := validator.Shared().StructCtx(tctx, obj); err != nil {
+		return &types.Error{
+			Path:       ".Importer->DecodeRequest()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	return nil
+}
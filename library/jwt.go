@@ -18,6 +18,8 @@ type Credential struct {
 	Username string `json:"Username"`
 	Email    string `json:"Email"`
 	Type     string `json:"Type"`
+	// BusinessID is the business the user works on, the data written without business belongs to it
+	BusinessID string `json:"BusinessID"`
 
 	FsId         string `json:"fsid"`
 	ClientId     string `json:"clientid"`
@@ -47,9 +49,11 @@ func JwtSignString(c Credential) (string, error) {
 
 	claims["ID"] = c.ID
 	claims["Email"] = c.Email
+	claims["Username"] = c.Username
 	claims["LoginTime"] = UTCPlus7()
 	claims["Exp"] = UTCPlus7().Add(time.Duration(config.JwtTimeOut) * time.Second)
 	claims["Type"] = c.Type
+	claims["BusinessID"] = c.BusinessID
 
 	redisClient := redis.NewClient(&redis.Options{
 		Addr:     config.RedisAddr,
@@ -0,0 +1,20 @@
+package mail
+
+import "context"
+
+// NotificationChannel sends notifications as plain text mails, it is the email channel of the notif router
+type NotificationChannel struct {
+	Mailer Mailer
+	From   Address
+}
+
+// Send mails the notification to the recipient address
+func (c NotificationChannel) Send(ctx context.Context, recipient string, subject string, body string) error {
+	_, err := c.Mailer.Send(ctx, &Message{
+		From:    c.From,
+		To:      []Address{{Email: recipient}},
+		Subject: subject,
+		Text:    body,
+	})
+	return err
+}
@@ -0,0 +1,307 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	"luxe-beb-go/library"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrLockLost declare specific error for a message claimed again by another dispatcher while it was being sent,
+// the result of the attempt is dropped so it does not overwrite the one of the new owner
+var ErrLockLost = fmt.Errorf("mail: outbox message is no longer claimed by this dispatcher")
+
+// DispatcherConfig tunes the outbox dispatcher, zero values use the defaults
+type DispatcherConfig struct {
+	// Interval between two polls of the outbox, 5 seconds by default
+	Interval time.Duration
+	// BatchSize is the number of messages claimed per poll, 20 by default
+	BatchSize int
+	// BaseDelay is the wait after the first failure, doubled on every next one, 30 seconds by default
+	BaseDelay time.Duration
+	// MaxDelay caps the wait between two attempts, 6 hours by default
+	MaxDelay time.Duration
+	// SendTimeout limits a single send, 60 seconds by default
+	SendTimeout time.Duration
+	// LockTimeout frees a message claimed by a dispatcher that died, 10 minutes by default
+	LockTimeout time.Duration
+}
+
+// Dispatcher sends the due messages of the outbox and records every attempt
+type Dispatcher struct {
+	db     *sqlx.DB
+	mailer Mailer
+	config DispatcherConfig
+}
+
+// NewDispatcher creates a dispatcher sending through the mailer
+func NewDispatcher(db *sqlx.DB, mailer Mailer, config DispatcherConfig) *Dispatcher {
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 20
+	}
+	if config.BaseDelay <= 0 {
+		config.BaseDelay = 30 * time.Second
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = 6 * time.Hour
+	}
+	if config.SendTimeout <= 0 {
+		config.SendTimeout = 60 * time.Second
+	}
+	if config.LockTimeout <= 0 {
+		config.LockTimeout = 10 * time.Minute
+	}
+	return &Dispatcher{db: db, mailer: mailer, config: config}
+}
+
+// Run polls the outbox until the context is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DispatchDue(ctx); err != nil {
+			log.Println("mail dispatcher: ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue claims the due messages and sends them, it returns the number of messages tried
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	token := uuid.New().String()
+	now := library.UTCPlus7()
+
+	// a message stuck in sending belongs to a dispatcher that stopped in the middle, it is claimed again
+	_, err := d.db.ExecContext(ctx, `
+  UPDATE email_outbox
+  SET status = ?, lock_token = ?, locked_at = ?
+  WHERE (status = ? AND next_attempt_at <= ?) OR (status = ? AND locked_at < ?)
+  ORDER BY next_attempt_at
+  LIMIT ?`,
+		StatusSending, token, now,
+		StatusPending, now, StatusSending, now.Add(-d.config.LockTimeout),
+		d.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	rows := []outboxRow{}
+	err = d.db.SelectContext(ctx, &rows, `
+  SELECT id, from_email, from_name, reply_to, subject,
+    COALESCE(html_body, '') AS html_body, COALESCE(text_body, '') AS text_body,
+    attempts, max_attempts
+  FROM email_outbox
+  WHERE lock_token = ?`, token)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, row := range rows {
+		if err := d.dispatch(ctx, row, token); err != nil {
+			log.Printf("mail dispatcher: outbox %s: %v\n", row.ID, err)
+		}
+	}
+
+	return len(rows), nil
+}
+
+// dispatch sends one claimed message to its undelivered recipients
+func (d *Dispatcher) dispatch(ctx context.Context, row outboxRow, token string) error {
+	recipients := []recipientRow{}
+	err := d.db.SelectContext(ctx, &recipients, `
+  SELECT id, kind, email, name FROM email_outbox_recipients
+  WHERE outbox_id = ? AND status <> ?`, row.ID, StatusSent)
+	if err != nil {
+		return err
+	}
+
+	attachments := []attachmentRow{}
+	err = d.db.SelectContext(ctx, &attachments, `
+  SELECT filename, content_type, content_id, content FROM email_outbox_attachments
+  WHERE outbox_id = ?`, row.ID)
+	if err != nil {
+		return err
+	}
+
+	attempt := row.Attempts + 1
+	startedAt := library.UTCPlus7()
+
+	sendCtx, cancel := context.WithTimeout(ctx, d.config.SendTimeout)
+	receipt, sendErr := d.mailer.Send(sendCtx, row.message(recipients, attachments))
+	cancel()
+
+	finishedAt := library.UTCPlus7()
+
+	tx, err := d.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	provider, response, lastError := "", "", ""
+	status := StatusSent
+	if receipt != nil {
+		provider, response = receipt.Provider, receipt.Response
+	}
+	if sendErr != nil {
+		status, lastError = StatusFailed, sendErr.Error()
+	}
+
+	err = namedExec(tx, `
+  INSERT INTO email_outbox_attempts (id, outbox_id, attempt, status, provider, response, error, started_at, finished_at)
+  VALUES (UUID(), :outbox_id, :attempt, :status, :provider, :response, :error, :started_at, :finished_at)`, map[string]interface{}{
+		"outbox_id":   row.ID,
+		"attempt":     attempt,
+		"status":      status,
+		"provider":    provider,
+		"response":    response,
+		"error":       lastError,
+		"started_at":  startedAt,
+		"finished_at": finishedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	if sendErr != nil {
+		err = d.failed(tx, row, token, attempt, lastError, finishedAt)
+	} else {
+		err = d.sent(tx, row, token, attempt, recipients, receipt, finishedAt)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// sent marks the message & every recipient accepted by the provider as sent
+func (d *Dispatcher) sent(tx *sqlx.Tx, row outboxRow, token string, attempt int, recipients []recipientRow, receipt *Receipt, now time.Time) error {
+	for _, rc := range recipients {
+		status, messageID, rejected := StatusSent, receipt.MessageID, receipt.Rejected(rc.Email)
+		for _, result := range receipt.Recipients {
+			if result.Email == rc.Email && result.MessageID != "" {
+				messageID = result.MessageID
+			}
+		}
+
+		var lastError interface{}
+		var sentAt interface{} = now
+		if rejected != "" {
+			status, messageID, lastError, sentAt = StatusRejected, "", rejected, nil
+		}
+
+		err := namedExec(tx, `
+  UPDATE email_outbox_recipients
+  SET status = :status, provider_message_id = :message_id, last_error = :last_error, sent_at = :sent_at
+  WHERE id = :id`, map[string]interface{}{
+			"id":         rc.ID,
+			"status":     status,
+			"message_id": messageID,
+			"last_error": lastError,
+			"sent_at":    sentAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return updateClaimed(tx, `
+  UPDATE email_outbox
+  SET status = :status, attempts = :attempts, provider = :provider, provider_message_id = :message_id,
+    last_error = NULL, sent_at = :now, lock_token = NULL, locked_at = NULL, updated_at = :now
+  WHERE id = :id AND lock_token = :token`, map[string]interface{}{
+		"id":         row.ID,
+		"token":      token,
+		"status":     StatusSent,
+		"attempts":   attempt,
+		"provider":   receipt.Provider,
+		"message_id": receipt.MessageID,
+		"now":        now,
+	})
+}
+
+// failed schedules the next attempt of the message, or fails it once the attempts are used up
+func (d *Dispatcher) failed(tx *sqlx.Tx, row outboxRow, token string, attempt int, lastError string, now time.Time) error {
+	status := StatusPending
+	if attempt >= row.MaxAttempts {
+		status = StatusFailed
+
+		err := namedExec(tx, `
+  UPDATE email_outbox_recipients SET status = :failed, last_error = :last_error
+  WHERE outbox_id = :id AND status = :pending`, map[string]interface{}{
+			"id":         row.ID,
+			"failed":     StatusFailed,
+			"pending":    StatusPending,
+			"last_error": lastError,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return updateClaimed(tx, `
+  UPDATE email_outbox
+  SET status = :status, attempts = :attempts, next_attempt_at = :next_attempt_at,
+    last_error = :last_error, lock_token = NULL, locked_at = NULL, updated_at = :now
+  WHERE id = :id AND lock_token = :token`, map[string]interface{}{
+		"id":              row.ID,
+		"token":           token,
+		"status":          status,
+		"attempts":        attempt,
+		"next_attempt_at": now.Add(Backoff(attempt, d.config.BaseDelay, d.config.MaxDelay)),
+		"last_error":      lastError,
+		"now":             now,
+	})
+}
+
+// updateClaimed updates the message only while it is still claimed with the token of this dispatcher,
+// a message freed after LockTimeout & claimed again belongs to the new dispatcher
+func updateClaimed(tx *sqlx.Tx, query string, arg map[string]interface{}) error {
+	result, err := tx.NamedExec(query, arg)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrLockLost
+	}
+
+	return nil
+}
+
+// Backoff returns the wait after the failed attempt: base doubled for every previous failure,
+// with up to 10% of jitter, capped at max so the retries of a provider outage are spread out
+func Backoff(attempt int, base time.Duration, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+
+	if jitter := int64(delay / 10); jitter > 0 {
+		delay += time.Duration(rand.Int63n(jitter))
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
@@ -0,0 +1,104 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileMailer writes every message as an .eml file in a directory, for development and tests
+type FileMailer struct {
+	dir string
+}
+
+// NewFileMailer creates a mailer writing into dir, "mails" when empty
+func NewFileMailer(dir string) *FileMailer {
+	if dir == "" {
+		dir = "mails"
+	}
+	return &FileMailer{dir: dir}
+}
+
+// Send writes the message into a new file named by the time and the subject
+func (m *FileMailer) Send(ctx context.Context, msg *Message) (*Receipt, error) {
+	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+
+	body, err := msg.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102T150405.000000000"), randomID()[:8])
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, body, 0644); err != nil {
+		return nil, err
+	}
+
+	return &Receipt{Provider: TransportFile, MessageID: name, Response: "written to " + path}, nil
+}
+
+// MboxMailer appends every message to a single mbox file, for development and tests
+type MboxMailer struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewMboxMailer creates a mailer appending to the mbox file at path, "mails.mbox" when empty
+func NewMboxMailer(path string) *MboxMailer {
+	if path == "" {
+		path = "mails.mbox"
+	}
+	return &MboxMailer{path: path}
+}
+
+// Send appends the message to the mbox file
+func (m *MboxMailer) Send(ctx context.Context, msg *Message) (*Receipt, error) {
+	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+
+	body, err := msg.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if dir := filepath.Dir(m.path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// mboxrd: lines starting with "From " (after any ">") are quoted
+	lines := strings.Split(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimLeft(line, ">"), "From ") {
+			lines[i] = ">" + line
+		}
+	}
+
+	_, err = fmt.Fprintf(f, "From %s %s\n%s\n\n", msg.From.Email, time.Now().UTC().Format(time.ANSIC), strings.Join(lines, "\n"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Receipt{Provider: TransportMbox, Response: "appended to " + m.path}, nil
+}
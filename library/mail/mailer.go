@@ -0,0 +1,74 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"luxe-beb-go/configs"
+)
+
+const (
+	TransportMailjet = "mailjet"
+	TransportSMTP    = "smtp"
+	TransportFile    = "file"
+	TransportMbox    = "mbox"
+)
+
+// Receipt is the answer of the provider for a sent message
+type Receipt struct {
+	Provider  string
+	MessageID string
+	// Response is the raw answer of the provider, kept for the delivery log
+	Response string
+	// Recipients holds the result per address when the provider gives one
+	Recipients []RecipientResult
+}
+
+// RecipientResult is the answer of the provider for one recipient, Error is empty when accepted
+type RecipientResult struct {
+	Email     string
+	MessageID string
+	Error     string
+}
+
+// Rejected returns the error of the provider for the email, empty when it was accepted or unknown
+func (r *Receipt) Rejected(email string) string {
+	for _, result := range r.Recipients {
+		if strings.EqualFold(result.Email, email) {
+			return result.Error
+		}
+	}
+	return ""
+}
+
+// Mailer sends messages through a transport
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) (*Receipt, error)
+}
+
+// NewFromConfig creates the mailer of the MAIL_TRANSPORT in the configuration, mailjet by default
+func NewFromConfig(config *configs.Config) (Mailer, error) {
+	switch config.MailTransport {
+	case "", TransportMailjet:
+		return NewMailjetMailer(config.MjApikeyPublic, config.MjApikeyPrivate), nil
+	case TransportSMTP:
+		return NewSMTPMailer(SMTPConfig{
+			Host:     config.SMTPHost,
+			Port:     config.SMTPPort,
+			Username: config.SMTPUsername,
+			Password: config.SMTPPassword,
+		}), nil
+	case TransportFile:
+		return NewFileMailer(config.MailFilePath), nil
+	case TransportMbox:
+		return NewMboxMailer(config.MailFilePath), nil
+	}
+
+	return nil, fmt.Errorf("mail: unknown transport %q", config.MailTransport)
+}
+
+// DefaultSender returns the sender address of the configuration
+func DefaultSender(config *configs.Config) Address {
+	return Address{Email: config.MjSenderEmail, Name: config.MjSenderName}
+}
@@ -0,0 +1,106 @@
+package mail
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	mailjet "github.com/mailjet/mailjet-apiv3-go"
+)
+
+// MailjetMailer sends messages through the Mailjet send API v3.1
+type MailjetMailer struct {
+	client *mailjet.Client
+}
+
+// NewMailjetMailer creates a mailer with the Mailjet api keys
+func NewMailjetMailer(apiKeyPublic string, apiKeyPrivate string) *MailjetMailer {
+	return &MailjetMailer{client: mailjet.NewMailjetClient(apiKeyPublic, apiKeyPrivate)}
+}
+
+// Send sends the message, the mailjet client does not take a context so only a done context is checked
+func (m *MailjetMailer) Send(ctx context.Context, msg *Message) (*Receipt, error) {
+	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	info := mailjet.InfoMessagesV31{
+		From:     &mailjet.RecipientV31{Email: msg.From.Email, Name: msg.From.Name},
+		To:       recipients(msg.To),
+		Cc:       recipients(msg.Cc),
+		Bcc:      recipients(msg.Bcc),
+		Subject:  msg.Subject,
+		TextPart: msg.PlainText(),
+		HTMLPart: msg.HTML,
+	}
+	if msg.ReplyTo != nil {
+		info.ReplyTo = &mailjet.RecipientV31{Email: msg.ReplyTo.Email, Name: msg.ReplyTo.Name}
+	}
+
+	if len(msg.Attachments) > 0 {
+		attachments := mailjet.AttachmentsV31{}
+		for _, a := range msg.Attachments {
+			attachments = append(attachments, mailjetAttachment(a))
+		}
+		info.Attachments = &attachments
+	}
+
+	if len(msg.Inlines) > 0 {
+		inlines := mailjet.InlinedAttachmentsV31{}
+		for _, a := range msg.Inlines {
+			inlines = append(inlines, mailjet.InlinedAttachmentV31{AttachmentV31: mailjetAttachment(a), ContentID: a.ContentID})
+		}
+		info.InlinedAttachments = &inlines
+	}
+
+	res, err := m.client.SendMailV31(&mailjet.MessagesV31{Info: []mailjet.InfoMessagesV31{info}})
+	if err != nil {
+		return nil, err
+	}
+
+	response, _ := json.Marshal(res)
+	receipt := &Receipt{Provider: TransportMailjet, Response: string(response)}
+	if len(res.ResultsV31) > 0 {
+		result := res.ResultsV31[0]
+		for _, generated := range append(append(result.To, result.Cc...), result.Bcc...) {
+			receipt.Recipients = append(receipt.Recipients, RecipientResult{
+				Email:     generated.Email,
+				MessageID: generated.MessageUUID,
+			})
+		}
+		if len(result.To) > 0 {
+			receipt.MessageID = fmt.Sprintf("%d", result.To[0].MessageID)
+		}
+	}
+
+	return receipt, nil
+}
+
+func recipients(addresses []Address) *mailjet.RecipientsV31 {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	list := mailjet.RecipientsV31{}
+	for _, a := range addresses {
+		list = append(list, mailjet.RecipientV31{Email: a.Email, Name: a.Name})
+	}
+	return &list
+}
+
+func mailjetAttachment(a Attachment) mailjet.AttachmentV31 {
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return mailjet.AttachmentV31{
+		ContentType:   contentType,
+		Filename:      a.Filename,
+		Base64Content: base64.StdEncoding.EncodeToString(a.Content),
+	}
+}
@@ -0,0 +1,301 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"regexp"
+	"strings"
+	"time"
+
+	"luxe-beb-go/library"
+)
+
+var (
+	// ErrNoRecipient declare specific error for a message without any to, cc or bcc address
+	ErrNoRecipient = fmt.Errorf("mail: message has no recipient")
+
+	// ErrNoSender declare specific error for a message without from address
+	ErrNoSender = fmt.Errorf("mail: message has no sender")
+
+	tagRegex        = regexp.MustCompile(`(?is)<(script|style|head)[^>]*>.*?</(script|style|head)>|<[^>]+>`)
+	breakRegex      = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|tr|h[1-6]|li|table)>`)
+	blankLinesRegex = regexp.MustCompile(`\n\s*\n\s*\n+`)
+	spacesRegex     = regexp.MustCompile(`[ \t]+`)
+)
+
+// Address is a mailbox, e.g. "Budi <budi@example.com>"
+type Address struct {
+	Email string
+	Name  string
+}
+
+func (a Address) String() string {
+	return (&mail.Address{Name: a.Name, Address: a.Email}).String()
+}
+
+// Attachment is a file attached to the message. An inline attachment has a ContentID
+// and is shown in the html part with <img src="cid:ContentID">
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+	ContentID   string
+}
+
+// Message is an email with an html part, a plain text part and attachments
+type Message struct {
+	From    Address
+	ReplyTo *Address
+	To      []Address
+	Cc      []Address
+	Bcc     []Address
+	Subject string
+
+	HTML string
+	// Text is the plain text part, it is made from HTML when empty
+	Text string
+
+	Attachments []Attachment
+	Inlines     []Attachment
+}
+
+// Recipients returns the to, cc & bcc addresses
+func (m *Message) Recipients() []Address {
+	recipients := []Address{}
+	recipients = append(recipients, m.To...)
+	recipients = append(recipients, m.Cc...)
+	recipients = append(recipients, m.Bcc...)
+	return recipients
+}
+
+// Validate checks the message has a sender, at least one recipient and valid addresses
+func (m *Message) Validate() error {
+	if m.From.Email == "" {
+		return ErrNoSender
+	}
+	if len(m.Recipients()) == 0 {
+		return ErrNoRecipient
+	}
+
+	for _, a := range append(m.Recipients(), m.From) {
+		if !library.IsEmailValid(a.Email) {
+			return fmt.Errorf("mail: invalid address %q", a.Email)
+		}
+	}
+
+	return nil
+}
+
+// PlainText returns the text part, made from the html when the message has none
+func (m *Message) PlainText() string {
+	if m.Text != "" || m.HTML == "" {
+		return m.Text
+	}
+
+	return HTMLToText(m.HTML)
+}
+
+// HTMLToText turns an html document into readable plain text
+func HTMLToText(document string) string {
+	text := breakRegex.ReplaceAllString(document, "\n")
+	text = tagRegex.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	text = spacesRegex.ReplaceAllString(text, " ")
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	text = strings.Join(lines, "\n")
+	text = blankLinesRegex.ReplaceAllString(text, "\n\n")
+
+	return strings.TrimSpace(text)
+}
+
+// Bytes builds the MIME message (RFC 5322), the bcc addresses are left out of the headers
+func (m *Message) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+
+	headers := []struct {
+		key   string
+		value string
+	}{
+		{"From", m.From.String()},
+		{"To", joinAddresses(m.To)},
+		{"Cc", joinAddresses(m.Cc)},
+		{"Subject", mime.QEncoding.Encode("utf-8", m.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%s@%s>", randomID(), domain(m.From.Email))},
+		{"MIME-Version", "1.0"},
+	}
+	if m.ReplyTo != nil {
+		headers = append(headers, struct {
+			key   string
+			value string
+		}{"Reply-To", m.ReplyTo.String()})
+	}
+
+	for _, h := range headers {
+		if h.value != "" {
+			fmt.Fprintf(&buf, "%s: %s\r\n", h.key, h.value)
+		}
+	}
+
+	mixed := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mixed.Boundary())
+
+	// text & html are alternatives, the html goes with its inline images
+	alternativeBoundary := randomID()
+	alternativePart, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {fmt.Sprintf("multipart/alternative; boundary=%s", alternativeBoundary)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	alternative := multipart.NewWriter(alternativePart)
+	alternative.SetBoundary(alternativeBoundary)
+
+	if err := writeText(alternative, "text/plain; charset=utf-8", m.PlainText()); err != nil {
+		return nil, err
+	}
+
+	if m.HTML != "" {
+		if len(m.Inlines) == 0 {
+			if err := writeText(alternative, "text/html; charset=utf-8", m.HTML); err != nil {
+				return nil, err
+			}
+		} else {
+			relatedBoundary := randomID()
+			relatedPart, err := alternative.CreatePart(textproto.MIMEHeader{
+				"Content-Type": {fmt.Sprintf("multipart/related; boundary=%s", relatedBoundary)},
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			related := multipart.NewWriter(relatedPart)
+			related.SetBoundary(relatedBoundary)
+			if err := writeText(related, "text/html; charset=utf-8", m.HTML); err != nil {
+				return nil, err
+			}
+			for _, inline := range m.Inlines {
+				if err := writeAttachment(related, inline, true); err != nil {
+					return nil, err
+				}
+			}
+			if err := related.Close(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := alternative.Close(); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range m.Attachments {
+		if err := writeAttachment(mixed, attachment, false); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeText(w *multipart.Writer, contentType string, text string) error {
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	qp := quotedprintable.NewWriter(part)
+	if _, err := io.WriteString(qp, text); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+func writeAttachment(w *multipart.Writer, a Attachment, inline bool) error {
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(strings.ToLower(extension(a.Filename)))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	disposition := "attachment"
+	if inline {
+		disposition = "inline"
+	}
+
+	header := textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType(contentType, map[string]string{"name": a.Filename})},
+		"Content-Disposition":       {mime.FormatMediaType(disposition, map[string]string{"filename": a.Filename})},
+		"Content-Transfer-Encoding": {"base64"},
+	}
+	if a.ContentID != "" {
+		header.Set("Content-ID", "<"+a.ContentID+">")
+	}
+
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	// base64 lines are limited to 76 characters
+	encoded := base64.StdEncoding.EncodeToString(a.Content)
+	for len(encoded) > 76 {
+		if _, err := io.WriteString(part, encoded[:76]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err = io.WriteString(part, encoded+"\r\n")
+	return err
+}
+
+func joinAddresses(addresses []Address) string {
+	list := make([]string, len(addresses))
+	for i, a := range addresses {
+		list[i] = a.String()
+	}
+	return strings.Join(list, ", ")
+}
+
+func extension(filename string) string {
+	if i := strings.LastIndex(filename, "."); i >= 0 {
+		return filename[i:]
+	}
+	return ""
+}
+
+func domain(email string) string {
+	if i := strings.LastIndex(email, "@"); i >= 0 {
+		return email[i+1:]
+	}
+	return "localhost"
+}
+
+func randomID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return fmt.Sprintf("%x", b)
+}
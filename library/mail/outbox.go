@@ -0,0 +1,256 @@
+package mail
+
+import (
+	"fmt"
+	netmail "net/mail"
+
+	"luxe-beb-go/library"
+	"luxe-beb-go/library/appcontext"
+	"luxe-beb-go/library/data"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// outbox & recipient statuses
+const (
+	StatusPending  = "pending"
+	StatusSending  = "sending"
+	StatusSent     = "sent"
+	StatusFailed   = "failed"
+	StatusRejected = "rejected"
+)
+
+// DefaultMaxAttempts is the number of sends tried before a message is failed
+const DefaultMaxAttempts = 8
+
+var (
+	// ErrNoTransaction declare specific error for a message queued outside of RunInTransaction
+	ErrNoTransaction = fmt.Errorf("mail must be queued inside a transaction")
+
+	// ErrNotResendable declare specific error for a resend of a message that is still being delivered
+	ErrNotResendable = fmt.Errorf("only a sent or failed mail can be resent")
+)
+
+// Outbox persists messages in email_outbox, they are sent later by the Dispatcher
+type Outbox struct {
+	db          *sqlx.DB
+	maxAttempts int
+}
+
+// NewOutbox creates a new outbox, maxAttempts below 1 uses DefaultMaxAttempts
+func NewOutbox(db *sqlx.DB, maxAttempts int) *Outbox {
+	if maxAttempts < 1 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	return &Outbox{db: db, maxAttempts: maxAttempts}
+}
+
+// Enqueue stores the message inside the transaction of the context, so the mail only leaves
+// when the data it talks about is committed. The message belongs to the current business of the context.
+// It returns the id of the outbox row.
+func (o *Outbox) Enqueue(ctx *gin.Context, msg *Message) (string, error) {
+	tx, ok := data.TxFromContext(ctx)
+	if !ok {
+		return "", ErrNoTransaction
+	}
+
+	if err := msg.Validate(); err != nil {
+		return "", err
+	}
+
+	now := library.UTCPlus7()
+	id := uuid.New().String()
+
+	replyTo := ""
+	if msg.ReplyTo != nil {
+		replyTo = msg.ReplyTo.String()
+	}
+
+	err := namedExec(tx, `
+  INSERT INTO email_outbox (id, business_id, from_email, from_name, reply_to, subject, html_body, text_body,
+    status, attempts, max_attempts, next_attempt_at, created_at, updated_at)
+  VALUES (:id, :business_id, :from_email, :from_name, :reply_to, :subject, :html_body, :text_body,
+    :status, 0, :max_attempts, :now, :now, :now)`, map[string]interface{}{
+		"id":           id,
+		"business_id":  appcontext.BusinessID(ctx),
+		"from_email":   msg.From.Email,
+		"from_name":    msg.From.Name,
+		"reply_to":     replyTo,
+		"subject":      msg.Subject,
+		"html_body":    msg.HTML,
+		"text_body":    msg.Text,
+		"status":       StatusPending,
+		"max_attempts": o.maxAttempts,
+		"now":          now,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	kinds := []struct {
+		kind      string
+		addresses []Address
+	}{{"to", msg.To}, {"cc", msg.Cc}, {"bcc", msg.Bcc}}
+	for _, k := range kinds {
+		for _, a := range k.addresses {
+			err := namedExec(tx, `
+  INSERT INTO email_outbox_recipients (id, outbox_id, kind, email, name, status)
+  VALUES (UUID(), :outbox_id, :kind, :email, :name, :status)`, map[string]interface{}{
+				"outbox_id": id,
+				"kind":      k.kind,
+				"email":     a.Email,
+				"name":      a.Name,
+				"status":    StatusPending,
+			})
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	for _, a := range append(append([]Attachment{}, msg.Attachments...), msg.Inlines...) {
+		err := namedExec(tx, `
+  INSERT INTO email_outbox_attachments (id, outbox_id, filename, content_type, content_id, content)
+  VALUES (UUID(), :outbox_id, :filename, :content_type, :content_id, :content)`, map[string]interface{}{
+			"outbox_id":    id,
+			"filename":     a.Filename,
+			"content_type": a.ContentType,
+			"content_id":   a.ContentID,
+			"content":      a.Content,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return id, nil
+}
+
+// Resend queues a sent or failed message again for the recipients that did not get it,
+// or for every recipient when all of them got it. The attempts start again from zero.
+func (o *Outbox) Resend(ctx *gin.Context, id string) error {
+	var q data.Queryer = o.db
+	if tx, ok := data.TxFromContext(ctx); ok {
+		q = tx
+	}
+
+	status := ""
+	err := q.Get(&status, `SELECT status FROM email_outbox WHERE id = ? FOR UPDATE`, id)
+	if err != nil {
+		return err
+	}
+	if status != StatusSent && status != StatusFailed {
+		return ErrNotResendable
+	}
+
+	undelivered := 0
+	err = q.Get(&undelivered, `SELECT COUNT(*) FROM email_outbox_recipients WHERE outbox_id = ? AND status <> ?`, id, StatusSent)
+	if err != nil {
+		return err
+	}
+
+	where := `outbox_id = :id AND status <> :sent`
+	if undelivered == 0 {
+		where = `outbox_id = :id`
+	}
+
+	err = namedExec(q, fmt.Sprintf(`
+  UPDATE email_outbox_recipients SET status = :pending, last_error = NULL
+  WHERE %s`, where), map[string]interface{}{
+		"id":      id,
+		"sent":    StatusSent,
+		"pending": StatusPending,
+	})
+	if err != nil {
+		return err
+	}
+
+	return namedExec(q, `
+  UPDATE email_outbox
+  SET status = :pending, attempts = 0, next_attempt_at = :now, last_error = NULL,
+    lock_token = NULL, locked_at = NULL, updated_at = :now
+  WHERE id = :id`, map[string]interface{}{
+		"id":      id,
+		"pending": StatusPending,
+		"now":     library.UTCPlus7(),
+	})
+}
+
+// outboxRow is a claimed row of email_outbox
+type outboxRow struct {
+	ID          string `db:"id"`
+	FromEmail   string `db:"from_email"`
+	FromName    string `db:"from_name"`
+	ReplyTo     string `db:"reply_to"`
+	Subject     string `db:"subject"`
+	HTML        string `db:"html_body"`
+	Text        string `db:"text_body"`
+	Attempts    int    `db:"attempts"`
+	MaxAttempts int    `db:"max_attempts"`
+}
+
+type recipientRow struct {
+	ID    string `db:"id"`
+	Kind  string `db:"kind"`
+	Email string `db:"email"`
+	Name  string `db:"name"`
+}
+
+type attachmentRow struct {
+	Filename    string `db:"filename"`
+	ContentType string `db:"content_type"`
+	ContentID   string `db:"content_id"`
+	Content     []byte `db:"content"`
+}
+
+// message rebuilds the message of the row for the recipients still to be delivered
+func (r outboxRow) message(recipients []recipientRow, attachments []attachmentRow) *Message {
+	msg := &Message{
+		From:    Address{Email: r.FromEmail, Name: r.FromName},
+		Subject: r.Subject,
+		HTML:    r.HTML,
+		Text:    r.Text,
+	}
+
+	if r.ReplyTo != "" {
+		if a, err := netmail.ParseAddress(r.ReplyTo); err == nil {
+			msg.ReplyTo = &Address{Email: a.Address, Name: a.Name}
+		}
+	}
+
+	for _, rc := range recipients {
+		a := Address{Email: rc.Email, Name: rc.Name}
+		switch rc.Kind {
+		case "cc":
+			msg.Cc = append(msg.Cc, a)
+		case "bcc":
+			msg.Bcc = append(msg.Bcc, a)
+		default:
+			msg.To = append(msg.To, a)
+		}
+	}
+
+	for _, a := range attachments {
+		attachment := Attachment{Filename: a.Filename, ContentType: a.ContentType, ContentID: a.ContentID, Content: a.Content}
+		if a.ContentID != "" {
+			msg.Inlines = append(msg.Inlines, attachment)
+		} else {
+			msg.Attachments = append(msg.Attachments, attachment)
+		}
+	}
+
+	return msg
+}
+
+func namedExec(q data.Queryer, query string, arg map[string]interface{}) error {
+	statement, err := q.PrepareNamed(query)
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	_, err = statement.Exec(arg)
+	return err
+}
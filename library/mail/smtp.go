@@ -0,0 +1,131 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTPConfig is the server used by the smtp mailer
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// Timeout limits the whole send when the context has no deadline
+	Timeout time.Duration
+}
+
+// SMTPMailer sends messages to an smtp server, STARTTLS is used when the server offers it
+// and the port 465 is dialed with implicit tls
+type SMTPMailer struct {
+	config SMTPConfig
+}
+
+// NewSMTPMailer creates a mailer for the smtp server
+func NewSMTPMailer(config SMTPConfig) *SMTPMailer {
+	if config.Port == 0 {
+		config.Port = 587
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	return &SMTPMailer{config: config}
+}
+
+// Send delivers the message to every to, cc & bcc recipient
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) (*Receipt, error) {
+	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+
+	body, err := msg.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.config.Timeout)
+		defer cancel()
+	}
+
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	tlsConfig := &tls.Config{ServerName: m.config.Host}
+
+	var conn net.Conn
+	dialer := &net.Dialer{}
+	if m.config.Port == 465 {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the smtp client has no context, the deadline of the connection stops a stuck server
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && m.config.Port != 465 {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	if m.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := client.Mail(msg.From.Email); err != nil {
+		return nil, err
+	}
+	// a rejected recipient does not stop the others, the message fails only when nobody accepts it
+	receipt := &Receipt{Provider: TransportSMTP}
+	accepted := 0
+	for _, r := range msg.Recipients() {
+		result := RecipientResult{Email: r.Email}
+		if err := client.Rcpt(r.Email); err != nil {
+			result.Error = err.Error()
+		} else {
+			accepted++
+		}
+		receipt.Recipients = append(receipt.Recipients, result)
+	}
+	if accepted == 0 {
+		return nil, fmt.Errorf("mail: no recipient accepted by %s: %s", addr, receipt.Recipients[0].Error)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(body); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := client.Quit(); err != nil {
+		return nil, err
+	}
+
+	receipt.Response = "250 queued by " + addr
+	return receipt, nil
+}
@@ -1,15 +1,14 @@
 package mailjet
 
 import (
+	"context"
 	"fmt"
-	"log"
-	"strings"
 
 	"luxe-beb-go/configs"
+	"luxe-beb-go/library/mail"
 	"luxe-beb-go/library/templatehtml"
 
-	"github.com/PuerkitoBio/goquery"
-	mailjet "github.com/mailjet/mailjet-apiv3-go"
+	"github.com/gin-gonic/gin"
 )
 
 type ContentMailjet struct {
@@ -24,90 +23,103 @@ type ContentMailjet struct {
 	FromName string
 	Subject  string
 	TextPart string
+
+	Attachments []mail.Attachment
 }
 
-// sales
-func SendMailInvoice(content ContentMailjet) {
+// SendMailInvoice sends the invoice filled with the data to the recipient of the content
+func SendMailInvoice(content ContentMailjet, invoice templatehtml.InvoiceData) error {
 	config, errConfig := configs.GetConfiguration()
 	if errConfig != nil {
-		log.Fatalln("failed to get configuration: ", errConfig)
+		return fmt.Errorf("failed to get configuration: %v", errConfig)
 	}
 
-	var contentMailjet ContentMailjet
-	contentMailjet.Content = GetInvoiceTemplate()
-	contentMailjet.To = content.To
-	contentMailjet.ToName = content.ToName
-	contentMailjet.TextPart = ""
-	contentMailjet.Subject = "Subject here"
+	contentMailjet, err := invoiceContent(content, invoice)
+	if err != nil {
+		return err
+	}
 
-	SendMail(config, contentMailjet)
+	return SendMail(config, contentMailjet)
 }
 
-func SendMail(config *configs.Config, contentMailjet ContentMailjet) error {
-	var err error
-	var content string
-
-	content = contentMailjet.Content
-	contentMailjet.From = config.MjSenderEmail
-	contentMailjet.FromName = config.MjSenderName
-	mailjetClient := mailjet.NewMailjetClient(config.MjApikeyPublic, config.MjApikeyPrivate)
-	messagesInfo := []mailjet.InfoMessagesV31{
-		mailjet.InfoMessagesV31{
-			From: &mailjet.RecipientV31{
-				Email: contentMailjet.From,
-				Name:  contentMailjet.FromName,
-			},
-			To: &mailjet.RecipientsV31{
-				mailjet.RecipientV31{
-					Email: contentMailjet.To,
-					Name:  contentMailjet.ToName,
-				},
-			},
-			Subject:  contentMailjet.Subject,
-			TextPart: contentMailjet.TextPart,
-			HTMLPart: content,
-		},
+// QueueMailInvoice queues the invoice in the outbox inside the transaction of the context
+func QueueMailInvoice(ctx *gin.Context, outbox *mail.Outbox, content ContentMailjet, invoice templatehtml.InvoiceData) (string, error) {
+	config, errConfig := configs.GetConfiguration()
+	if errConfig != nil {
+		return "", fmt.Errorf("failed to get configuration: %v", errConfig)
 	}
 
-	messages := mailjet.MessagesV31{Info: messagesInfo}
-	res, err := mailjetClient.SendMailV31(&messages)
+	contentMailjet, err := invoiceContent(content, invoice)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	fmt.Printf("Data: %+v\n", res)
-
-	return nil
+	return QueueMail(ctx, outbox, config, contentMailjet)
 }
 
-func GetInvoiceTemplate() string {
-	sampleHTML := templatehtml.TemplateInvoice()
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(sampleHTML))
+func invoiceContent(content ContentMailjet, invoice templatehtml.InvoiceData) (ContentMailjet, error) {
+	html, err := GetInvoiceTemplate(invoice)
 	if err != nil {
-		log.Fatal(err)
+		return ContentMailjet{}, err
 	}
 
-	modifiedHTML, err := goquery.OuterHtml(doc.Selection)
-	if err != nil {
-		log.Fatal(err)
+	var contentMailjet ContentMailjet
+	contentMailjet.Content = html
+	contentMailjet.To = content.To
+	contentMailjet.ToName = content.ToName
+	contentMailjet.Cc = content.Cc
+	contentMailjet.CcName = content.CcName
+	contentMailjet.Bcc = content.Bcc
+	contentMailjet.BccName = content.BccName
+	contentMailjet.Attachments = content.Attachments
+	contentMailjet.Subject = content.Subject
+	if contentMailjet.Subject == "" {
+		contentMailjet.Subject = fmt.Sprintf("Invoice %s", invoice.Number)
 	}
 
-	// str := html.EscapeString(modifiedHTML)
+	return contentMailjet, nil
+}
+
+// SendMail sends the content right away through the mail transport of the configuration
+func SendMail(config *configs.Config, contentMailjet ContentMailjet) error {
+	mailer, err := mail.NewFromConfig(config)
+	if err != nil {
+		return err
+	}
 
-	// // for dev
-	// f, err := os.Create("check.html")
-	// if err != nil {
-	// 	log.Fatal(err)
-	// }
+	_, err = mailer.Send(context.Background(), message(config, contentMailjet))
+	return err
+}
 
-	// defer f.Close()
+// QueueMail stores the content in the outbox inside the transaction of the context,
+// the dispatcher sends it once the transaction is committed
+func QueueMail(ctx *gin.Context, outbox *mail.Outbox, config *configs.Config, contentMailjet ContentMailjet) (string, error) {
+	return outbox.Enqueue(ctx, message(config, contentMailjet))
+}
 
-	// _, err2 := f.WriteString(modifiedHTML)
-	// if err2 != nil {
-	// 	log.Fatal(err2)
-	// }
+func message(config *configs.Config, contentMailjet ContentMailjet) *mail.Message {
+	msg := &mail.Message{
+		From:        mail.DefaultSender(config),
+		To:          []mail.Address{{Email: contentMailjet.To, Name: contentMailjet.ToName}},
+		Subject:     contentMailjet.Subject,
+		HTML:        contentMailjet.Content,
+		Text:        contentMailjet.TextPart,
+		Attachments: contentMailjet.Attachments,
+	}
+	if contentMailjet.From != "" {
+		msg.From = mail.Address{Email: contentMailjet.From, Name: contentMailjet.FromName}
+	}
+	if contentMailjet.Cc != "" {
+		msg.Cc = []mail.Address{{Email: contentMailjet.Cc, Name: contentMailjet.CcName}}
+	}
+	if contentMailjet.Bcc != "" {
+		msg.Bcc = []mail.Address{{Email: contentMailjet.Bcc, Name: contentMailjet.BccName}}
+	}
 
-	// fmt.Println("done")
+	return msg
+}
 
-	return modifiedHTML
+// GetInvoiceTemplate returns the invoice template filled with the data
+func GetInvoiceTemplate(invoice templatehtml.InvoiceData) (string, error) {
+	return templatehtml.RenderInvoice(invoice)
 }
@@ -0,0 +1,27 @@
+package notif
+
+import "context"
+
+// channel names used by the notification rules
+const (
+	ChannelSlack    = "slack"
+	ChannelEmail    = "email"
+	ChannelWhatsapp = "whatsapp"
+	ChannelInApp    = "in_app"
+)
+
+// Channel is the interface that wraps the Send method.
+//
+// Send delivers a notification to a recipient of the channel. The recipient depends on the channel:
+// a slack channel, an email address or a phone number. An empty recipient is the default one of the channel.
+type Channel interface {
+	Send(ctx context.Context, recipient string, subject string, body string) error
+}
+
+// ChannelFunc adapts a function to a Channel
+type ChannelFunc func(ctx context.Context, recipient string, subject string, body string) error
+
+// Send calls f(ctx, recipient, subject, body)
+func (f ChannelFunc) Send(ctx context.Context, recipient string, subject string, body string) error {
+	return f(ctx, recipient, subject, body)
+}
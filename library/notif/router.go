@@ -0,0 +1,483 @@
+package notif
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"text/template"
+	"time"
+
+	"luxe-beb-go/library"
+	"luxe-beb-go/library/appcontext"
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/templatehtml"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// events routed by the notification rules
+const (
+	EventUserCreated     = "user.created"
+	EventPasswordReset   = "user.password_reset"
+	EventPaymentReceived = "payment.received"
+	EventErrorAlert      = "error.alert"
+)
+
+// TargetUser is the rule target sending to the user of the event, any other target is the recipient itself
+const TargetUser = "user"
+
+// notification statuses
+const (
+	StatusPending = "pending"
+	StatusSending = "sending"
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+)
+
+// Event is something that happened and may be notified, the rules of its name decide where it goes
+type Event struct {
+	Name string
+	// UserID is the user the event is about, the recipient of the rules targeting "user"
+	UserID string
+	// Subject & Body are used by the rules without template
+	Subject string
+	Body    string
+	// Data is given to the templates of the rules, next to .Event, .Subject & .Body
+	Data map[string]interface{}
+	// DedupKey identifies repeats of the event, the subject & body are used when empty
+	DedupKey string
+}
+
+// RouterConfig tunes the delivery of the router, zero values use the defaults
+type RouterConfig struct {
+	// Interval between two polls of the pending notifications, 5 seconds by default
+	Interval time.Duration
+	// BatchSize is the number of notifications claimed per poll, 50 by default
+	BatchSize int
+	// MaxAttempts is the number of sends tried before a notification is failed, 5 by default
+	MaxAttempts int
+	// RetryDelay is the wait after the first failure, doubled on every next one, 1 minute by default
+	RetryDelay time.Duration
+	// LockTimeout frees a notification claimed by a worker that died, 10 minutes by default
+	LockTimeout time.Duration
+}
+
+// Router turns events into notifications following the rules of notification_rules and
+// the preferences of the recipients, then delivers them through the registered channels.
+//
+// Publish only writes the notifications, inside the transaction of the context when there is one,
+// so the events of a rolled back request are never sent. Run delivers them in the background.
+type Router struct {
+	db       *sqlx.DB
+	channels map[string]Channel
+	config   RouterConfig
+}
+
+type rule struct {
+	ID               string `db:"id"`
+	Channel          string `db:"channel"`
+	Target           string `db:"target"`
+	SubjectTemplate  string `db:"subject_template"`
+	BodyTemplate     string `db:"body_template"`
+	DedupSeconds     int    `db:"dedup_seconds"`
+	IgnoreQuietHours bool   `db:"ignore_quiet_hours"`
+}
+
+type preference struct {
+	Enabled    bool   `db:"enabled"`
+	QuietStart string `db:"quiet_start"`
+	QuietEnd   string `db:"quiet_end"`
+}
+
+type notification struct {
+	ID        string `db:"id"`
+	Channel   string `db:"channel"`
+	Recipient string `db:"recipient"`
+	Subject   string `db:"subject"`
+	Body      string `db:"body"`
+	Attempts  int    `db:"attempts"`
+}
+
+// NewRouter creates a new router
+func NewRouter(db *sqlx.DB, config RouterConfig) *Router {
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 50
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 5
+	}
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = time.Minute
+	}
+	if config.LockTimeout <= 0 {
+		config.LockTimeout = 10 * time.Minute
+	}
+	return &Router{db: db, channels: map[string]Channel{}, config: config}
+}
+
+// Register adds the channel under the name used by the rules
+func (r *Router) Register(name string, channel Channel) *Router {
+	r.channels[name] = channel
+	return r
+}
+
+// Notify publishes the message as an error alert, so the router can be used as a Notifier
+func (r *Router) Notify(message string) error {
+	return r.Publish(nil, Event{Name: EventErrorAlert, Subject: "Error alert", Body: message})
+}
+
+// Publish writes the notifications of the event for every active rule of its name (or "*"),
+// the shared rules and the ones of the current business. A recipient who turned the event or channel off gets nothing, one in quiet hours gets it when they end,
+// and the repeats of an event within the dedup window of its rule are only counted.
+func (r *Router) Publish(ctx *gin.Context, event Event) error {
+	var q data.Queryer = r.db
+	if ctx != nil {
+		if tx, ok := data.TxFromContext(ctx); ok {
+			q = tx
+		}
+	}
+	businessID := appcontext.BusinessID(ctx)
+
+	rules := []rule{}
+	err := q.Select(&rules, `
+  SELECT id, channel, target,
+    COALESCE(subject_template, '') AS subject_template,
+    COALESCE(body_template, '') AS body_template,
+    dedup_seconds, ignore_quiet_hours
+  FROM notification_rules
+  WHERE event IN (?, '*') AND business_id IN ('', ?) AND status_id = '1'`, event.Name, businessID)
+	if err != nil {
+		return err
+	}
+
+	now := library.UTCPlus7()
+	for _, rl := range rules {
+		if err := r.route(q, rl, businessID, event, now); err != nil {
+			return fmt.Errorf("notification rule %s: %v", rl.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// route writes the notification of one rule for the business the event happened in
+func (r *Router) route(q data.Queryer, rl rule, businessID string, event Event, now time.Time) error {
+	recipient, userID := rl.Target, ""
+	deliverAfter := now
+
+	if rl.Target == TargetUser {
+		if event.UserID == "" {
+			return nil
+		}
+		userID = event.UserID
+
+		pref, err := r.preference(q, userID, event.Name, rl.Channel)
+		if err != nil {
+			return err
+		}
+		if pref != nil && !pref.Enabled {
+			return nil
+		}
+		if pref != nil && !rl.IgnoreQuietHours && rl.Channel != ChannelInApp {
+			deliverAfter = quietHoursEnd(now, pref.QuietStart, pref.QuietEnd)
+		}
+
+		recipient, err = r.address(q, userID, rl.Channel)
+		if err != nil {
+			return err
+		}
+		if recipient == "" {
+			return nil
+		}
+	}
+
+	subject, err := render(rl.SubjectTemplate, event.Subject, event)
+	if err != nil {
+		return err
+	}
+	body, err := render(rl.BodyTemplate, event.Body, event)
+	if err != nil {
+		return err
+	}
+
+	dedupKey := event.DedupKey
+	if dedupKey == "" {
+		dedupKey = subject + "\n" + body
+	}
+	hash := sha1.Sum([]byte(rl.ID + "\n" + recipient + "\n" + dedupKey))
+	dedupHash := hex.EncodeToString(hash[:])
+
+	// a shared rule fires for every business, the repeats are only counted within the same business
+	if rl.DedupSeconds > 0 {
+		existingID := ""
+		err := q.Get(&existingID, `
+  SELECT id FROM notifications
+  WHERE dedup_key = ? AND business_id = ? AND created_at >= ?
+  ORDER BY created_at DESC
+  LIMIT 1`, dedupHash, businessID, now.Add(-time.Duration(rl.DedupSeconds)*time.Second))
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if existingID != "" {
+			return namedExec(q, `
+  UPDATE notifications SET repeat_count = repeat_count + 1, updated_at = :now WHERE id = :id`, map[string]interface{}{
+				"id":  existingID,
+				"now": now,
+			})
+		}
+	}
+
+	// an in-app notification is delivered by being written
+	status := StatusPending
+	var sentAt interface{}
+	if rl.Channel == ChannelInApp {
+		status, sentAt = StatusSent, now
+	}
+
+	return namedExec(q, `
+  INSERT INTO notifications (id, business_id, rule_id, event, channel, user_id, recipient, subject, body, dedup_key,
+    status, deliver_after, sent_at, created_at, updated_at)
+  VALUES (:id, :business_id, :rule_id, :event, :channel, :user_id, :recipient, :subject, :body, :dedup_key,
+    :status, :deliver_after, :sent_at, :now, :now)`, map[string]interface{}{
+		"id":            uuid.New().String(),
+		"business_id":   businessID,
+		"rule_id":       rl.ID,
+		"event":         event.Name,
+		"channel":       rl.Channel,
+		"user_id":       userID,
+		"recipient":     recipient,
+		"subject":       subject,
+		"body":          body,
+		"dedup_key":     dedupHash,
+		"status":        status,
+		"deliver_after": deliverAfter,
+		"sent_at":       sentAt,
+		"now":           now,
+	})
+}
+
+// preference returns the most specific preference of the user for the event & channel, nil when none
+func (r *Router) preference(q data.Queryer, userID string, event string, channel string) (*preference, error) {
+	pref := preference{}
+	err := q.Get(&pref, `
+  SELECT enabled,
+    COALESCE(TIME_FORMAT(quiet_start, '%H:%i'), '') AS quiet_start,
+    COALESCE(TIME_FORMAT(quiet_end, '%H:%i'), '') AS quiet_end
+  FROM notification_preferences
+  WHERE user_id = ? AND event IN (?, '*') AND channel IN (?, '*')
+  ORDER BY event = '*', channel = '*'
+  LIMIT 1`, userID, event, channel)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pref, nil
+}
+
+// address returns where the user gets the channel: the address of their preferences,
+// their email for the email channel or their id for in-app notifications
+func (r *Router) address(q data.Queryer, userID string, channel string) (string, error) {
+	if channel == ChannelInApp {
+		return userID, nil
+	}
+
+	address := ""
+	err := q.Get(&address, `
+  SELECT address FROM notification_preferences
+  WHERE user_id = ? AND channel = ? AND address <> ''
+  ORDER BY event = '*'
+  LIMIT 1`, userID, channel)
+	if err != nil && err != sql.ErrNoRows {
+		return "", err
+	}
+
+	if address == "" && channel == ChannelEmail {
+		err = q.Get(&address, `SELECT email FROM users WHERE id = ?`, userID)
+		if err != nil && err != sql.ErrNoRows {
+			return "", err
+		}
+	}
+
+	return address, nil
+}
+
+// quietHoursEnd returns now, or the end of the quiet hours (HH:MM) when now is inside them.
+// A start after the end spans midnight, e.g. 22:00 to 07:00.
+func quietHoursEnd(now time.Time, start string, end string) time.Time {
+	startAt, errStart := time.Parse("15:04", start)
+	endAt, errEnd := time.Parse("15:04", end)
+	if errStart != nil || errEnd != nil || start == end {
+		return now
+	}
+
+	minutes := now.Hour()*60 + now.Minute()
+	from := startAt.Hour()*60 + startAt.Minute()
+	to := endAt.Hour()*60 + endAt.Minute()
+
+	quiet := minutes >= from && minutes < to
+	if from > to {
+		quiet = minutes >= from || minutes < to
+	}
+	if !quiet {
+		return now
+	}
+
+	until := time.Date(now.Year(), now.Month(), now.Day(), endAt.Hour(), endAt.Minute(), 0, 0, now.Location())
+	if !until.After(now) {
+		until = until.AddDate(0, 0, 1)
+	}
+	return until
+}
+
+// render fills the template of the rule with the event, the fallback is used when the rule has none
+func render(text string, fallback string, event Event) (string, error) {
+	if text == "" {
+		return fallback, nil
+	}
+
+	values := map[string]interface{}{}
+	for k, v := range event.Data {
+		values[k] = v
+	}
+	values["Event"] = event.Name
+	values["UserID"] = event.UserID
+	values["Subject"] = event.Subject
+	values["Body"] = event.Body
+
+	t, err := template.New("notification").Funcs(template.FuncMap(templatehtml.Funcs())).Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, values); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Run delivers the due notifications until the context is done
+func (r *Router) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.DispatchDue(ctx); err != nil {
+			log.Println("notification router: ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue claims the due notifications and sends them, it returns the number of notifications tried
+func (r *Router) DispatchDue(ctx context.Context) (int, error) {
+	token := uuid.New().String()
+	now := library.UTCPlus7()
+
+	_, err := r.db.ExecContext(ctx, `
+  UPDATE notifications
+  SET status = ?, lock_token = ?, locked_at = ?
+  WHERE (status = ? AND deliver_after <= ?) OR (status = ? AND locked_at < ?)
+  ORDER BY deliver_after
+  LIMIT ?`,
+		StatusSending, token, now,
+		StatusPending, now, StatusSending, now.Add(-r.config.LockTimeout),
+		r.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	notifications := []notification{}
+	err = r.db.SelectContext(ctx, &notifications, `
+  SELECT id, channel, recipient, subject, COALESCE(body, '') AS body, attempts
+  FROM notifications
+  WHERE lock_token = ?`, token)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, n := range notifications {
+		if err := r.deliver(ctx, n); err != nil {
+			log.Printf("notification router: notification %s: %v\n", n.ID, err)
+		}
+	}
+
+	return len(notifications), nil
+}
+
+// deliver sends one claimed notification, a failure is retried later until MaxAttempts
+func (r *Router) deliver(ctx context.Context, n notification) error {
+	var sendErr error
+	channel, ok := r.channels[n.Channel]
+	if !ok {
+		sendErr = fmt.Errorf("channel %q is not registered", n.Channel)
+	} else {
+		sendErr = channel.Send(ctx, n.Recipient, n.Subject, n.Body)
+	}
+
+	attempts := n.Attempts + 1
+	now := library.UTCPlus7()
+
+	if sendErr == nil {
+		return namedExec(r.db, `
+  UPDATE notifications
+  SET status = :status, attempts = :attempts, last_error = NULL, sent_at = :now,
+    lock_token = NULL, locked_at = NULL, updated_at = :now
+  WHERE id = :id`, map[string]interface{}{
+			"id":       n.ID,
+			"status":   StatusSent,
+			"attempts": attempts,
+			"now":      now,
+		})
+	}
+
+	status := StatusPending
+	if attempts >= r.config.MaxAttempts {
+		status = StatusFailed
+	}
+
+	delay := r.config.RetryDelay << uint(attempts-1)
+	if delay > time.Hour || delay <= 0 {
+		delay = time.Hour
+	}
+
+	return namedExec(r.db, `
+  UPDATE notifications
+  SET status = :status, attempts = :attempts, last_error = :last_error, deliver_after = :deliver_after,
+    lock_token = NULL, locked_at = NULL, updated_at = :now
+  WHERE id = :id`, map[string]interface{}{
+		"id":            n.ID,
+		"status":        status,
+		"attempts":      attempts,
+		"last_error":    sendErr.Error(),
+		"deliver_after": now.Add(delay),
+		"now":           now,
+	})
+}
+
+func namedExec(q data.Queryer, query string, arg map[string]interface{}) error {
+	statement, err := q.PrepareNamed(query)
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	_, err = statement.Exec(arg)
+	return err
+}
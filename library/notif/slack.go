@@ -2,6 +2,8 @@ package notif
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
@@ -51,20 +53,54 @@ func (sn *SlackNotifier) Notify(message string) error {
 			https://slack.com/api/chat.postMessage
 	*/
 
-	payload := []byte(fmt.Sprintf(`{
-		"channel": "%s",
-		"text": "%s",
-	}`, sn.Channel, message))
+	return sn.post(context.Background(), sn.Channel, message)
+}
+
+// Send posts the notification to the slack channel of the recipient, the channel of the notifier when empty
+func (sn *SlackNotifier) Send(ctx context.Context, recipient string, subject string, body string) error {
+	if recipient == "" {
+		recipient = sn.Channel
+	}
+
+	text := body
+	if subject != "" {
+		text = fmt.Sprintf("*%s*\n%s", subject, body)
+	}
+	return sn.post(ctx, recipient, text)
+}
+
+func (sn *SlackNotifier) post(ctx context.Context, channel string, text string) error {
+	payload, err := json.Marshal(map[string]string{
+		"channel": channel,
+		"text":    text,
+	})
+	if err != nil {
+		return err
+	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/chat.postMessage", apiURL), bytes.NewBuffer(payload))
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/chat.postMessage", apiURL), bytes.NewBuffer(payload))
+	if err != nil {
+		return err
+	}
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", sn.Token))
 	req.Header.Set("Content-type", "application/json")
+
+	res, err := sn.HTTPClient.Do(req)
 	if err != nil {
 		return err
 	}
+	defer res.Body.Close()
 
-	if _, err := sn.HTTPClient.Do(req); err != nil {
-		return err
+	// slack answers 200 with ok false when the message is refused
+	result := struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return fmt.Errorf("slack: %s: %v", res.Status, err)
+	}
+	if !result.OK {
+		return fmt.Errorf("slack: %s", result.Error)
 	}
 	return nil
 }
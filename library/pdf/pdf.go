@@ -0,0 +1,75 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"time"
+)
+
+const (
+	PageSizeA4     = "A4"
+	PageSizeA5     = "A5"
+	PageSizeLetter = "Letter"
+
+	OrientationPortrait  = "Portrait"
+	OrientationLandscape = "Landscape"
+
+	// DefaultTimeout is the longest a render may take when the options have no timeout
+	DefaultTimeout = 30 * time.Second
+)
+
+// ErrTimeout declare specific error for a render that took longer than its timeout
+var ErrTimeout = fmt.Errorf("pdf: render timed out")
+
+// Options are the page setup of a rendered document
+type Options struct {
+	PageSize    string
+	Orientation string
+
+	// margins in millimeters, zero keeps the renderer default
+	MarginTop    int
+	MarginBottom int
+	MarginLeft   int
+	MarginRight  int
+
+	// HeaderHTML & FooterHTML are full html documents repeated on every page,
+	// HeaderText & FooterText are plain texts centered on every page, [page] and [topage] are replaced by the page numbers
+	HeaderHTML string
+	FooterHTML string
+	HeaderText string
+	FooterText string
+
+	Title   string
+	Timeout time.Duration
+}
+
+// Renderer converts an html document into a pdf document
+type Renderer interface {
+	Render(ctx context.Context, html []byte, opts Options) ([]byte, error)
+}
+
+// RenderTemplate executes the template with the data then renders the result into a pdf document
+func RenderTemplate(ctx context.Context, r Renderer, t *template.Template, data interface{}, opts Options) ([]byte, error) {
+	var html bytes.Buffer
+	if err := t.Execute(&html, data); err != nil {
+		return nil, fmt.Errorf("pdf: executing template %s: %v", t.Name(), err)
+	}
+
+	return r.Render(ctx, html.Bytes(), opts)
+}
+
+// withDefaults fills the options left empty
+func (o Options) withDefaults() Options {
+	if o.PageSize == "" {
+		o.PageSize = PageSizeA4
+	}
+	if o.Orientation == "" {
+		o.Orientation = OrientationPortrait
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+	return o
+}
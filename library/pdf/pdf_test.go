@@ -0,0 +1,74 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"strings"
+	"testing"
+)
+
+var invoiceTemplate = template.Must(template.New("invoice").Parse(
+	`<html><body><h1>Invoice {{.Number}}</h1><p>{{.CustomerName}}</p></body></html>`))
+
+type invoice struct {
+	Number       string
+	CustomerName string
+}
+
+func TestRenderTemplate(t *testing.T) {
+	r := NewStubRenderer()
+
+	data := invoice{Number: "INV/2026/001", CustomerName: "<b>Budi</b>"}
+	doc, err := RenderTemplate(context.Background(), r, invoiceTemplate, data, Options{Orientation: OrientationLandscape, Title: "Invoice"})
+	if err != nil {
+		t.Fatalf("RenderTemplate: %v", err)
+	}
+	if !bytes.HasPrefix(doc, []byte("%PDF-")) || !bytes.Equal(doc, StubPDF()) {
+		t.Errorf("RenderTemplate returned %q, want the stub pdf", doc)
+	}
+
+	calls := r.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("renderer got %d calls, want 1", len(calls))
+	}
+
+	want := `<html><body><h1>Invoice INV/2026/001</h1><p>&lt;b&gt;Budi&lt;/b&gt;</p></body></html>`
+	if calls[0].HTML != want {
+		t.Errorf("rendered html = %q, want %q", calls[0].HTML, want)
+	}
+
+	opts := calls[0].Options
+	if opts.PageSize != PageSizeA4 || opts.Orientation != OrientationLandscape || opts.Title != "Invoice" || opts.Timeout != DefaultTimeout {
+		t.Errorf("options = %+v, want A4 landscape with the default timeout", opts)
+	}
+}
+
+func TestRenderTemplateExecuteError(t *testing.T) {
+	r := NewStubRenderer()
+
+	tmpl := template.Must(template.New("broken").Parse(`{{.Missing.Field}}`))
+	_, err := RenderTemplate(context.Background(), r, tmpl, invoice{}, Options{})
+	if err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("RenderTemplate error = %v, want the failing template named", err)
+	}
+	if len(r.Calls()) != 0 {
+		t.Errorf("renderer got %d calls, want none for a template failing to execute", len(r.Calls()))
+	}
+}
+
+func TestStubRendererErrors(t *testing.T) {
+	r := NewStubRenderer()
+	r.Err = ErrTimeout
+
+	if _, err := RenderTemplate(context.Background(), r, invoiceTemplate, invoice{}, Options{}); !errors.Is(err, ErrTimeout) {
+		t.Errorf("RenderTemplate error = %v, want the error of the renderer", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewStubRenderer().Render(ctx, []byte("<html></html>"), Options{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Render error = %v, want the canceled context", err)
+	}
+}
@@ -0,0 +1,76 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// StubRenderer is a renderer for tests, it keeps every call and returns a small valid pdf
+// (or Err when it is set) without any external binary
+type StubRenderer struct {
+	Err error
+
+	mu    sync.Mutex
+	calls []StubCall
+}
+
+// StubCall is a render received by the stub
+type StubCall struct {
+	HTML    string
+	Options Options
+}
+
+// NewStubRenderer creates a stub renderer
+func NewStubRenderer() *StubRenderer {
+	return &StubRenderer{}
+}
+
+// Render records the call then returns the stub pdf
+func (r *StubRenderer) Render(ctx context.Context, html []byte, opts Options) ([]byte, error) {
+	r.mu.Lock()
+	r.calls = append(r.calls, StubCall{HTML: string(html), Options: opts.withDefaults()})
+	r.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	return StubPDF(), nil
+}
+
+// Calls returns the renders received so far
+func (r *StubRenderer) Calls() []StubCall {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]StubCall{}, r.calls...)
+}
+
+// StubPDF returns a blank one page pdf document
+func StubPDF() []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] >>",
+	}
+
+	doc := "%PDF-1.4\n"
+	offsets := []int{}
+	for i, obj := range objects {
+		offsets = append(offsets, len(doc))
+		doc += fmt.Sprintf("%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := len(doc)
+	doc += fmt.Sprintf("xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		doc += fmt.Sprintf("%010d 00000 n \n", offset)
+	}
+	doc += fmt.Sprintf("trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return []byte(doc)
+}
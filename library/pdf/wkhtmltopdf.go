@@ -0,0 +1,110 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// WkhtmltopdfRenderer renders through the local wkhtmltopdf binary
+type WkhtmltopdfRenderer struct {
+	path string
+}
+
+// NewWkhtmltopdfRenderer creates a renderer using the binary at path, "wkhtmltopdf" from the PATH when empty
+func NewWkhtmltopdfRenderer(path string) *WkhtmltopdfRenderer {
+	if path == "" {
+		path = "wkhtmltopdf"
+	}
+	return &WkhtmltopdfRenderer{path: path}
+}
+
+// Render pipes the html to wkhtmltopdf and returns the pdf written on its output.
+// The process is killed when the timeout of the options or the context ends first.
+func (r *WkhtmltopdfRenderer) Render(ctx context.Context, html []byte, opts Options) ([]byte, error) {
+	opts = opts.withDefaults()
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	args := []string{"--quiet", "--encoding", "utf-8", "--page-size", opts.PageSize, "--orientation", opts.Orientation}
+
+	margins := []struct {
+		flag  string
+		value int
+	}{
+		{"--margin-top", opts.MarginTop},
+		{"--margin-bottom", opts.MarginBottom},
+		{"--margin-left", opts.MarginLeft},
+		{"--margin-right", opts.MarginRight},
+	}
+	for _, m := range margins {
+		if m.value > 0 {
+			args = append(args, m.flag, strconv.Itoa(m.value)+"mm")
+		}
+	}
+
+	if opts.Title != "" {
+		args = append(args, "--title", opts.Title)
+	}
+
+	// header & footer html have to be files (or urls) for wkhtmltopdf
+	if opts.HeaderHTML != "" {
+		file, err := tempHTML("header", opts.HeaderHTML)
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(file)
+		args = append(args, "--header-html", file)
+	} else if opts.HeaderText != "" {
+		args = append(args, "--header-center", opts.HeaderText)
+	}
+
+	if opts.FooterHTML != "" {
+		file, err := tempHTML("footer", opts.FooterHTML)
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(file)
+		args = append(args, "--footer-html", file)
+	} else if opts.FooterText != "" {
+		args = append(args, "--footer-center", opts.FooterText)
+	}
+
+	// read the page from stdin & write the pdf to stdout
+	args = append(args, "-", "-")
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.path, args...)
+	cmd.Stdin = bytes.NewReader(html)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, ErrTimeout
+	}
+	if err != nil {
+		return nil, fmt.Errorf("pdf: wkhtmltopdf: %v: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+func tempHTML(name string, html string) (string, error) {
+	f, err := os.CreateTemp("", "pdf-"+name+"-*.html")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(html); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+
+	return f.Name(), nil
+}
@@ -1,44 +1,281 @@
 package templatehtml
 
 import (
-	"io/ioutil"
-	"log"
-	"os"
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"luxe-beb-go/html"
+	"luxe-beb-go/library/helpers"
+)
+
+const (
+	layoutsDir  = "layouts"
+	partialsDir = "partials"
+
+	DefaultTemplateName = "DefaultTemplate.html"
+	InvoiceTemplateName = "Invoice.html"
+
+	dateFormat     = "02 January 2006"
+	dateTimeFormat = "02 January 2006 15:04"
 )
 
+// ErrTemplateNotFound declare specific error for a page missing from the registry
+var ErrTemplateNotFound = fmt.Errorf("template not found")
+
 var (
-	preload []string
+	defaultRegistry     *Registry
+	defaultRegistryErr  error
+	defaultRegistryOnce sync.Once
+
+	jakarta = loadJakarta()
 )
 
-func TemplateDefault() string {
-	file, err := os.Open("html/DefaultTemplate.html")
+// Registry holds the pages parsed once with the layouts, partials & helper funcs.
+// A page is named by its path, e.g. "Invoice.html", and it can use the layouts and partials
+// by their path as well, e.g. {{template "layouts/email.html" .}} then {{define "content"}}...{{end}}.
+type Registry struct {
+	pages map[string]*template.Template
+}
+
+// Default returns the registry of the html templates embedded in the binary
+func Default() (*Registry, error) {
+	defaultRegistryOnce.Do(func() {
+		defaultRegistry, defaultRegistryErr = NewRegistry(html.Files)
+	})
+
+	return defaultRegistry, defaultRegistryErr
+}
+
+// NewRegistry parses every page of fsys, the files under layouts/ and partials/ are shared by all pages
+func NewRegistry(fsys fs.FS) (*Registry, error) {
+	base := template.New("").Funcs(Funcs())
+
+	for _, dir := range []string{layoutsDir, partialsDir} {
+		files, err := fs.Glob(fsys, dir+"/*.html")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			if err := parseFile(base, fsys, file); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	pages, err := fs.Glob(fsys, "*.html")
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Registry{pages: map[string]*template.Template{}}
+	for _, page := range pages {
+		t, err := base.Clone()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := parseFile(t, fsys, page); err != nil {
+			return nil, err
+		}
+
+		r.pages[page] = t.Lookup(page)
+	}
+
+	return r, nil
+}
+
+func parseFile(t *template.Template, fsys fs.FS, file string) error {
+	content, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return err
+	}
+
+	if _, err := t.New(path.Clean(file)).Parse(string(content)); err != nil {
+		return fmt.Errorf("parsing template %s: %v", file, err)
+	}
+
+	return nil
+}
+
+// Lookup returns the page template, e.g. to render it into a pdf
+func (r *Registry) Lookup(name string) (*template.Template, error) {
+	t, ok := r.pages[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+
+	return t, nil
+}
+
+// Names returns the names of the pages
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.pages))
+	for name := range r.pages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Execute writes the page filled with the data
+func (r *Registry) Execute(w io.Writer, name string, data interface{}) error {
+	t, err := r.Lookup(name)
+	if err != nil {
+		return err
+	}
+
+	return t.Execute(w, data)
+}
+
+// Render returns the page filled with the data
+func (r *Registry) Render(name string, data interface{}) (string, error) {
+	var b bytes.Buffer
+	if err := r.Execute(&b, name, data); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// Funcs returns the helper funcs available in the templates:
+//
+//	rupiah    formats a number as money, e.g. {{rupiah .Total}} gives "Rp. 150,000.00"
+//	date      formats a time in Asia/Jakarta, e.g. {{date .Date}} or {{date .Date "02/01/2006"}}
+//	datetime  formats a time with hour & minute in Asia/Jakarta
+//	multiply  multiplies two numbers, e.g. {{multiply .Price .Qty}}
+//	upper     uppercases a text
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"rupiah": func(value interface{}) (string, error) {
+			n, err := toInt(value)
+			if err != nil {
+				return "", err
+			}
+			return helpers.ConvertRupiah(n, true), nil
+		},
+		"date": func(t time.Time, layout ...string) string {
+			return formatTime(t, dateFormat, layout)
+		},
+		"datetime": func(t time.Time, layout ...string) string {
+			return formatTime(t, dateTimeFormat, layout)
+		},
+		"multiply": func(a interface{}, b interface{}) (int, error) {
+			x, err := toInt(a)
+			if err != nil {
+				return 0, err
+			}
+			y, err := toInt(b)
+			if err != nil {
+				return 0, err
+			}
+			return x * y, nil
+		},
+		"upper": strings.ToUpper,
+	}
+}
+
+func formatTime(t time.Time, defaultLayout string, layout []string) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	if len(layout) > 0 && layout[0] != "" {
+		defaultLayout = layout[0]
+	}
+	return t.In(jakarta).Format(defaultLayout)
+}
+
+func toInt(value interface{}) (int, error) {
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int8:
+		return int(v), nil
+	case int16:
+		return int(v), nil
+	case int32:
+		return int(v), nil
+	case int64:
+		return int(v), nil
+	case uint:
+		return int(v), nil
+	case uint8:
+		return int(v), nil
+	case uint16:
+		return int(v), nil
+	case uint32:
+		return int(v), nil
+	case uint64:
+		return int(v), nil
+	case float32:
+		return int(v), nil
+	case float64:
+		return int(v), nil
+	}
+
+	return 0, fmt.Errorf("%v (%T) is not a number", value, value)
+}
+
+// loadJakarta loads Asia/Jakarta, a fixed UTC+7 zone is used when the system has no tz database
+func loadJakarta() *time.Location {
+	loc, err := time.LoadLocation("Asia/Jakarta")
 	if err != nil {
-		log.Fatal(err)
+		return time.FixedZone("WIB", 7*60*60)
 	}
-	defer file.Close()
+	return loc
+}
+
+// TemplateDefault returns the default email template
+func TemplateDefault() (string, error) {
+	return templateFile(DefaultTemplateName)
+}
 
-	str, err := ioutil.ReadAll(file)
-	return string(str)
+// TemplateInvoice returns the invoice template, it has to be filled with RenderInvoice to show any data
+func TemplateInvoice() (string, error) {
+	return templateFile(InvoiceTemplateName)
 }
 
-func TemplateInvoice() string {
-	file, err := os.Open("html/BillingTemplateNonBS.html")
+func templateFile(name string) (string, error) {
+	content, err := fs.ReadFile(html.Files, name)
 	if err != nil {
-		log.Fatal(err)
+		return "", fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
 	}
-	defer file.Close()
 
-	str, err := ioutil.ReadAll(file)
-	return string(str)
+	return string(content), nil
+}
+
+// InvoiceItem is a line of an invoice
+type InvoiceItem struct {
+	Name  string
+	Qty   int
+	Price int
+}
+
+// InvoiceData is the data filled into the invoice template
+type InvoiceData struct {
+	Number       string
+	Date         time.Time
+	CustomerName string
+	Items        []InvoiceItem
+	Total        int
 }
 
-func ReservationInvoiceTemplate() string {
-	file, err := os.Open("html/ReservationInvoice.html")
+// RenderInvoice fills the invoice template with the data
+func RenderInvoice(data InvoiceData) (string, error) {
+	r, err := Default()
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
-	defer file.Close()
 
-	str, err := ioutil.ReadAll(file)
-	return string(str)
+	return r.Render(InvoiceTemplateName, data)
 }
@@ -10,6 +10,7 @@ type FindAllParams struct {
 	BusinessID string
 	OutletID   string
 	DataFinder string
+	Businesses []string
 	Outlets    []string
 }
 
@@ -0,0 +1,217 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+
+	"luxe-beb-go/library"
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/templatehtml"
+
+	"github.com/gin-gonic/gin"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+var (
+	shared     *Validator
+	sharedOnce sync.Once
+
+	uniqueStorages   = map[string]data.GenericStorage{}
+	uniqueStoragesMu sync.RWMutex
+
+	phoneRegex       = regexp.MustCompile(`^(?:\+62|62|0)(?:8[1-9][0-9]{6,10}|[2-7][0-9]{6,9})$`)
+	digitsRegex      = regexp.MustCompile(`^[0-9]+$`)
+	uniqueParamRegex = regexp.MustCompile(`^[a-zA-Z0-9_]+\.[a-zA-Z0-9_]+$`)
+	numberSeparators = strings.NewReplacer(" ", "", "-", "", ".", "")
+	clockRegex       = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+)
+
+// Shared returns the Validator used by every usecase, it is built once with
+// the json tag name function and the custom rules of the domain fields:
+//
+//	phone_id      Indonesian phone number, e.g. 0812-3456-7890 or +6281234567890
+//	npwp          NPWP of 15 digits (99.999.999.9-999.999) or 16 digits
+//	bank_account  bank account number of 6 to 20 digits
+//	unique_in     value is not used yet in a table column, e.g. unique_in=users.email,
+//	              the storage of the table must be registered with RegisterUniqueStorage
+//	required_if   field is required when another field has a value, e.g. required_if=TypeID 2
+//	regexp        value is a valid regular expression
+//	pattern_field value matches the regular expression held by another field, e.g. pattern_field=AccountNumberPattern
+//	clock         time of the day as HH:MM in 24 hours, e.g. 22:30
+//	text_template value is a valid text/template, e.g. "Halo {{.Name}}"
+func Shared() *Validator {
+	sharedOnce.Do(func() {
+		v := validator.New()
+		v.RegisterTagNameFunc(func(fld reflect.StructField) string {
+			name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+
+		v.RegisterValidation("email", isEmail)
+		v.RegisterValidation("phone_id", isPhoneID)
+		v.RegisterValidation("npwp", isNPWP)
+		v.RegisterValidation("bank_account", isBankAccount)
+		v.RegisterValidation("required_if", isRequiredIf)
+		v.RegisterValidation("regexp", isRegexp)
+		v.RegisterValidation("pattern_field", isPatternField)
+		v.RegisterValidation("clock", isClock)
+		v.RegisterValidation("text_template", isTextTemplate)
+		v.RegisterValidationCtx("unique_in", isUniqueIn)
+
+		shared = NewValidator(v, nil)
+	})
+
+	return shared
+}
+
+// RegisterUniqueStorage registers the storage of a table so it can be queried by the unique_in rule
+func RegisterUniqueStorage(tableName string, storage data.GenericStorage) {
+	uniqueStoragesMu.Lock()
+	defer uniqueStoragesMu.Unlock()
+
+	uniqueStorages[tableName] = storage
+}
+
+func isEmail(fl validator.FieldLevel) bool {
+	return library.IsEmailValid(fl.Field().String())
+}
+
+func isPhoneID(fl validator.FieldLevel) bool {
+	return phoneRegex.MatchString(numberSeparators.Replace(fl.Field().String()))
+}
+
+func isNPWP(fl validator.FieldLevel) bool {
+	npwp := numberSeparators.Replace(fl.Field().String())
+	return digitsRegex.MatchString(npwp) && (len(npwp) == 15 || len(npwp) == 16)
+}
+
+func isBankAccount(fl validator.FieldLevel) bool {
+	account := numberSeparators.Replace(fl.Field().String())
+	return digitsRegex.MatchString(account) && len(account) >= 6 && len(account) <= 20
+}
+
+// isRequiredIf checks the field is not empty when the other field (param "Field value") equals the value,
+// the value is refused when the param is not "Field value"
+func isRequiredIf(fl validator.FieldLevel) bool {
+	params := strings.Fields(fl.Param())
+	if len(params) != 2 {
+		log.Printf("invalid required_if param %q on %s", fl.Param(), fl.StructFieldName())
+		return false
+	}
+
+	other := fl.Parent()
+	if other.Kind() == reflect.Ptr {
+		other = other.Elem()
+	}
+
+	otherField := other.FieldByName(params[0])
+	if !otherField.IsValid() || fmt.Sprintf("%v", otherField.Interface()) != params[1] {
+		return true
+	}
+
+	return hasValue(fl.Field())
+}
+
+func isClock(fl validator.FieldLevel) bool {
+	return clockRegex.MatchString(fl.Field().String())
+}
+
+// isTextTemplate checks the field parses as a text/template with the helpers of templatehtml
+func isTextTemplate(fl validator.FieldLevel) bool {
+	_, err := template.New("").Funcs(template.FuncMap(templatehtml.Funcs())).Parse(fl.Field().String())
+	return err == nil
+}
+
+func isRegexp(fl validator.FieldLevel) bool {
+	_, err := regexp.Compile(fl.Field().String())
+	return err == nil
+}
+
+// isPatternField checks the field matches the regular expression of the other field (param "Field"),
+// an empty pattern accepts any value. The value is refused when the param is not a string field of the struct.
+func isPatternField(fl validator.FieldLevel) bool {
+	parent := fl.Parent()
+	if parent.Kind() == reflect.Ptr {
+		parent = parent.Elem()
+	}
+
+	patternField := parent.FieldByName(fl.Param())
+	if !patternField.IsValid() || patternField.Kind() != reflect.String {
+		log.Printf("invalid pattern_field param %q on %s", fl.Param(), fl.StructFieldName())
+		return false
+	}
+
+	pattern := patternField.String()
+	if pattern == "" {
+		return true
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false
+	}
+
+	return re.MatchString(fl.Field().String())
+}
+
+// isUniqueIn checks there is no other row in the table (param "table.column") holding the value,
+// the row of the validated struct itself (its ID field) is excluded so an update keeps its own value.
+// An empty value or a validation without request context is not checked. The value is refused
+// when the param is not "table.column" or no storage is registered for the table, it can not be checked.
+func isUniqueIn(ctx context.Context, fl validator.FieldLevel) bool {
+	if !uniqueParamRegex.MatchString(fl.Param()) {
+		log.Printf("invalid unique_in param %q on %s", fl.Param(), fl.StructFieldName())
+		return false
+	}
+
+	c, ok := ctx.(*gin.Context)
+	if !ok || !hasValue(fl.Field()) {
+		return true
+	}
+
+	params := strings.SplitN(fl.Param(), ".", 2)
+
+	uniqueStoragesMu.RLock()
+	storage, ok := uniqueStorages[params[0]]
+	uniqueStoragesMu.RUnlock()
+	if !ok {
+		log.Printf("no storage registered for the unique_in table %q, see RegisterUniqueStorage", params[0])
+		return false
+	}
+
+	id := ""
+	parent := fl.Parent()
+	if parent.Kind() == reflect.Ptr {
+		parent = parent.Elem()
+	}
+	if parent.Kind() == reflect.Struct {
+		if idField := parent.FieldByName("ID"); idField.IsValid() {
+			id = fmt.Sprintf("%v", idField.Interface())
+		}
+	}
+
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(1) FROM %s WHERE `%s` = :value AND `id` <> :id", params[0], params[1])
+	err := storage.SelectFirstWithQuery(c, &count, query, map[string]interface{}{
+		"value": fl.Field().Interface(),
+		"id":    id,
+	})
+	if err != nil {
+		return false
+	}
+
+	return count == 0
+}
+
+func hasValue(field reflect.Value) bool {
+	return field.IsValid() && !field.IsZero()
+}
@@ -0,0 +1,76 @@
+package validator
+
+import (
+	"testing"
+
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+type requiredIfAccount struct {
+	TypeID    int
+	BankID    string `validate:"required_if=TypeID 2"`
+	Malformed string `validate:"required_if=TypeID"`
+}
+
+type patternAccount struct {
+	AccountNumber string `validate:"pattern_field=Pattern"`
+	Pattern       string
+	Unknown       string `validate:"pattern_field=Missing"`
+	NotText       string `validate:"pattern_field=Size"`
+	Size          int
+}
+
+func TestRequiredIf(t *testing.T) {
+	err := Shared().Struct(requiredIfAccount{TypeID: 2, Malformed: "x"})
+	if err == nil || !hasFieldError(err, "BankID") {
+		t.Errorf("Struct() error = %v, want BankID required", err)
+	}
+
+	err = Shared().Struct(requiredIfAccount{TypeID: 1, Malformed: "x"})
+	if hasFieldError(err, "BankID") {
+		t.Errorf("Struct() error = %v, want BankID not required", err)
+	}
+}
+
+func TestRequiredIfBadParam(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("Struct() panicked on a bad required_if param: %v", r)
+		}
+	}()
+
+	err := Shared().Struct(requiredIfAccount{TypeID: 1, Malformed: "x"})
+	if !hasFieldError(err, "Malformed") {
+		t.Errorf("Struct() error = %v, want the field with a bad required_if param refused", err)
+	}
+}
+
+func TestPatternFieldBadParam(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("Struct() panicked on a bad pattern_field param: %v", r)
+		}
+	}()
+
+	err := Shared().Struct(patternAccount{AccountNumber: "123", Pattern: `^\d+$`, Unknown: "x", NotText: "x"})
+	if hasFieldError(err, "AccountNumber") {
+		t.Errorf("Struct() error = %v, want AccountNumber matching its pattern", err)
+	}
+	if !hasFieldError(err, "Unknown") || !hasFieldError(err, "NotText") {
+		t.Errorf("Struct() error = %v, want the fields with a bad pattern_field param refused", err)
+	}
+}
+
+// hasFieldError tells whether the validation of the field failed
+func hasFieldError(err error, field string) bool {
+	errs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return false
+	}
+	for _, fe := range errs {
+		if fe.StructField() == field {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
@@ -70,6 +71,8 @@ type Data struct {
 // ValidatorParamsInterface tolong diisi
 type ValidatorParamsInterface interface {
 	Struct(s interface{}) error
+	StructCtx(ctx context.Context, s interface{}) error
+	StructExceptCtx(ctx context.Context, s interface{}, fields ...string) error
 	RegisterTagNameFunc(fn validator.TagNameFunc)
 }
 
@@ -458,8 +461,11 @@ type Validator struct {
 	validatorAccess ValidatorAccessInterface
 }
 
-// ValidateAccess validate access from redis
+// ValidateAccess validate access from redis, it is forbidden when the validator has no access validator
 func (v *Validator) ValidateAccess(params *ValidateAccessParams) error {
+	if v.validatorAccess == nil {
+		return ErrForbidden
+	}
 	return v.validatorAccess.ValidateAccess(params)
 }
 
@@ -473,6 +479,16 @@ func (v *Validator) Struct(s interface{}) error {
 	return v.validatorParams.Struct(s)
 }
 
+// StructCtx validates a structs exposed fields with the context given to the rules, e.g. the request for unique_in
+func (v *Validator) StructCtx(ctx context.Context, s interface{}) error {
+	return v.validatorParams.StructCtx(ctx, s)
+}
+
+// StructExceptCtx validates a structs exposed fields except the given ones with the context given to the rules
+func (v *Validator) StructExceptCtx(ctx context.Context, s interface{}, fields ...string) error {
+	return v.validatorParams.StructExceptCtx(ctx, s, fields...)
+}
+
 // NewValidator generate validator object
 func NewValidator(
 	validatorParams ValidatorParamsInterface,
@@ -0,0 +1,49 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CountryCode is the calling code added to national numbers
+const CountryCode = "62"
+
+// ErrInvalidPhone declare specific error for a number that can not be turned into E.164
+var ErrInvalidPhone = fmt.Errorf("invalid phone number")
+
+// NormalizePhone turns an Indonesian phone number into E.164, e.g. "0812-3456 789",
+// "62812 3456789" and "+62 (812) 3456789" all become "+628123456789".
+// A number starting with + keeps its own country code.
+func NormalizePhone(phone string) (string, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')', '\t':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(phone))
+
+	international := strings.HasPrefix(cleaned, "+")
+	digits := strings.TrimPrefix(cleaned, "+")
+	if digits == "" || strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPhone, phone)
+	}
+
+	switch {
+	case international:
+	case strings.HasPrefix(digits, "00"):
+		digits = digits[2:]
+	case strings.HasPrefix(digits, CountryCode):
+	case strings.HasPrefix(digits, "0"):
+		digits = CountryCode + digits[1:]
+	case strings.HasPrefix(digits, "8"):
+		digits = CountryCode + digits
+	}
+
+	// E.164 allows up to 15 digits, an Indonesian number has at least 9 after the country code
+	if len(digits) < 10 || len(digits) > 15 || digits[0] == '0' {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPhone, phone)
+	}
+
+	return "+" + digits, nil
+}
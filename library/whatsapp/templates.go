@@ -0,0 +1,60 @@
+package whatsapp
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"luxe-beb-go/library/templatehtml"
+)
+
+// template names of the messages
+const (
+	InvoiceTemplateName  = "invoice.txt"
+	ReminderTemplateName = "reminder.txt"
+)
+
+//go:embed templates/*.txt
+var templateFiles embed.FS
+
+var (
+	templatesOnce sync.Once
+	templates     *template.Template
+	templatesErr  error
+)
+
+// ReminderData is the data filled into the payment reminder template
+type ReminderData struct {
+	Number       string
+	CustomerName string
+	Total        int
+	DueDate      time.Time
+}
+
+// Render fills the message template with the data, the helpers of templatehtml are available
+func Render(name string, data interface{}) (string, error) {
+	templatesOnce.Do(func() {
+		templates, templatesErr = template.New("whatsapp").
+			Funcs(template.FuncMap(templatehtml.Funcs())).
+			ParseFS(templateFiles, "templates/*.txt")
+	})
+	if templatesErr != nil {
+		return "", templatesErr
+	}
+
+	t := templates.Lookup(name)
+	if t == nil {
+		return "", fmt.Errorf("%w: %s", templatehtml.ErrTemplateNotFound, name)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	// whatsapp shows the text as it is, the template files may have windows line endings
+	return strings.TrimSpace(strings.ReplaceAll(buf.String(), "\r\n", "\n")), nil
+}
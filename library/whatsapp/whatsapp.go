@@ -0,0 +1,196 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"luxe-beb-go/configs"
+	"luxe-beb-go/library/client"
+	"luxe-beb-go/library/templatehtml"
+
+	"github.com/google/uuid"
+)
+
+const defaultHTTPTimeout = 30 * time.Second
+
+// Config represent the config needed when creating a new whatsapp client
+type Config struct {
+	// APIURL is the messages endpoint of the gateway, e.g. https://graph.facebook.com/v19.0/<phone id>
+	APIURL string
+	Token  string
+	// Recipients are the numbers notified by Notify
+	Recipients []string
+	// MaxRetries is the number of times a message is sent again when the gateway is unavailable,
+	// every attempt of a message carries the same idempotency key
+	MaxRetries int
+	HTTPClient *http.Client
+}
+
+// Document is a file sent as a whatsapp document, by public link or by uploaded media id
+type Document struct {
+	Link     string `json:"link,omitempty"`
+	MediaID  string `json:"id,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	Caption  string `json:"caption,omitempty"`
+}
+
+// Result is the answer of the gateway for a sent message
+type Result struct {
+	Contacts []struct {
+		Input string `json:"input"`
+		WaID  string `json:"wa_id"`
+	} `json:"contacts"`
+	Messages []struct {
+		ID string `json:"id"`
+	} `json:"messages"`
+}
+
+// MessageID returns the id given by the gateway to the message
+func (r *Result) MessageID() string {
+	if r == nil || len(r.Messages) == 0 {
+		return ""
+	}
+	return r.Messages[0].ID
+}
+
+type textBody struct {
+	PreviewURL bool   `json:"preview_url"`
+	Body       string `json:"body"`
+}
+
+type messageRequest struct {
+	MessagingProduct string    `json:"messaging_product"`
+	RecipientType    string    `json:"recipient_type"`
+	To               string    `json:"to"`
+	Type             string    `json:"type"`
+	Text             *textBody `json:"text,omitempty"`
+	Document         *Document `json:"document,omitempty"`
+}
+
+// WhatsappNotifier sends whatsapp messages through the SEND_WHATSAPP_API gateway
+type WhatsappNotifier struct {
+	client     *client.HTTPClient
+	Recipients []string
+}
+
+// NewWhatsappNotifier creates a new whatsapp notifier
+func NewWhatsappNotifier(config Config) *WhatsappNotifier {
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+
+	httpClient := client.NewHTTPClient(client.HTTPClient{
+		APIURL:            strings.TrimRight(config.APIURL, "/"),
+		HTTPClient:        config.HTTPClient,
+		MaxNetworkRetries: config.MaxRetries,
+		ClientName:        "whatsapp",
+	})
+	bearer := client.Bearer
+	bearer.Token = config.Token
+	httpClient.AddAuthentication(nil, bearer)
+
+	return &WhatsappNotifier{client: httpClient, Recipients: config.Recipients}
+}
+
+// NewWhatsappNotifierFromConfig creates a whatsapp notifier with the gateway of the configuration
+func NewWhatsappNotifierFromConfig(config *configs.Config, recipients ...string) *WhatsappNotifier {
+	return NewWhatsappNotifier(Config{
+		APIURL:     config.SendWhatsappAPI,
+		Token:      config.SendWhatsappToken,
+		Recipients: recipients,
+		MaxRetries: 3,
+	})
+}
+
+// Notify sends the message as text to every recipient of the notifier
+func (wn *WhatsappNotifier) Notify(message string) error {
+	var failed []string
+	for _, to := range wn.Recipients {
+		if _, err := wn.SendText(context.Background(), to, message); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", to, err))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("whatsapp notify failed for %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// Send sends the notification as text to the phone number, the subject is written in bold on top
+func (wn *WhatsappNotifier) Send(ctx context.Context, recipient string, subject string, body string) error {
+	text := body
+	if subject != "" {
+		text = fmt.Sprintf("*%s*\n\n%s", subject, body)
+	}
+
+	_, err := wn.SendText(ctx, recipient, text)
+	return err
+}
+
+// SendText sends a text message to the phone number
+func (wn *WhatsappNotifier) SendText(ctx context.Context, to string, body string) (*Result, error) {
+	return wn.send(ctx, to, messageRequest{Type: "text", Text: &textBody{Body: body}})
+}
+
+// SendDocument sends a document to the phone number, a caption is shown under the file
+func (wn *WhatsappNotifier) SendDocument(ctx context.Context, to string, document Document) (*Result, error) {
+	if document.Link == "" && document.MediaID == "" {
+		return nil, fmt.Errorf("whatsapp document needs a link or a media id")
+	}
+	return wn.send(ctx, to, messageRequest{Type: "document", Document: &document})
+}
+
+// SendTemplate sends the message template filled with the data as text
+func (wn *WhatsappNotifier) SendTemplate(ctx context.Context, to string, name string, data interface{}) (*Result, error) {
+	body, err := Render(name, data)
+	if err != nil {
+		return nil, err
+	}
+	return wn.SendText(ctx, to, body)
+}
+
+// SendDocumentTemplate sends the document with the message template filled with the data as caption
+func (wn *WhatsappNotifier) SendDocumentTemplate(ctx context.Context, to string, document Document, name string, data interface{}) (*Result, error) {
+	caption, err := Render(name, data)
+	if err != nil {
+		return nil, err
+	}
+	document.Caption = caption
+	return wn.SendDocument(ctx, to, document)
+}
+
+// SendInvoice sends the invoice pdf at the link with the invoice message as caption
+func (wn *WhatsappNotifier) SendInvoice(ctx context.Context, to string, link string, invoice templatehtml.InvoiceData) (*Result, error) {
+	document := Document{Link: link, Filename: fmt.Sprintf("Invoice %s.pdf", strings.ReplaceAll(invoice.Number, "/", "-"))}
+	return wn.SendDocumentTemplate(ctx, to, document, InvoiceTemplateName, invoice)
+}
+
+// SendReminder sends the payment reminder of an invoice as text
+func (wn *WhatsappNotifier) SendReminder(ctx context.Context, to string, reminder ReminderData) (*Result, error) {
+	return wn.SendTemplate(ctx, to, ReminderTemplateName, reminder)
+}
+
+func (wn *WhatsappNotifier) send(ctx context.Context, to string, message messageRequest) (*Result, error) {
+	phone, err := NormalizePhone(to)
+	if err != nil {
+		return nil, err
+	}
+
+	message.MessagingProduct = "whatsapp"
+	message.RecipientType = "individual"
+	// the gateway takes the E.164 number without the plus sign
+	message.To = strings.TrimPrefix(phone, "+")
+
+	// a message is sent again only with an idempotency key, so the gateway does not deliver it twice
+	header := http.Header{client.IdempotencyKeyHeader: {uuid.New().String()}}
+	result, err := client.Call[Result](ctx, wn.client, client.Request{Method: client.POST, Path: "messages", Header: header, Body: message})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
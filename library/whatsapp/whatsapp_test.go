@@ -0,0 +1,171 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gateway stands in for the whatsapp gateway, it keeps the messages it got
+type gateway struct {
+	mu       sync.Mutex
+	messages []messageRequest
+	keys     []string
+	// unavailable answers 503 to the first calls
+	unavailable int
+}
+
+func (g *gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if r.Method != http.MethodPost || r.URL.Path != "/messages" || r.Header.Get("Authorization") != "Bearer wa-token" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	g.keys = append(g.keys, r.Header.Get("Idempotency-Key"))
+	if g.unavailable > 0 {
+		g.unavailable--
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	var message messageRequest
+	if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	g.messages = append(g.messages, message)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"contacts":[{"input":"` + message.To + `","wa_id":"` + message.To + `"}],"messages":[{"id":"wamid.1"}]}`))
+}
+
+func newTestNotifier(t *testing.T, g *gateway, recipients ...string) *WhatsappNotifier {
+	server := httptest.NewServer(g)
+	t.Cleanup(server.Close)
+
+	return NewWhatsappNotifier(Config{APIURL: server.URL + "/", Token: "wa-token", Recipients: recipients, MaxRetries: 2, HTTPClient: server.Client()})
+}
+
+func TestSendText(t *testing.T) {
+	g := &gateway{}
+	wn := newTestNotifier(t, g)
+
+	result, err := wn.SendText(context.Background(), "0812-3456-7890", "Halo")
+	if err != nil {
+		t.Fatalf("SendText: %v", err)
+	}
+	if result.MessageID() != "wamid.1" {
+		t.Errorf("message id = %q, want wamid.1", result.MessageID())
+	}
+
+	if len(g.messages) != 1 {
+		t.Fatalf("gateway got %d messages, want 1", len(g.messages))
+	}
+	message := g.messages[0]
+	if message.To != "6281234567890" || message.Type != "text" || message.Text == nil || message.Text.Body != "Halo" {
+		t.Errorf("gateway got %+v, want the text to 6281234567890", message)
+	}
+	if g.keys[0] == "" {
+		t.Error("the message was sent without an idempotency key")
+	}
+}
+
+func TestSendRetriesWithTheSameKey(t *testing.T) {
+	g := &gateway{unavailable: 2}
+	wn := newTestNotifier(t, g)
+
+	if err := wn.Send(context.Background(), "+6281234567890", "Invoice", "Segera dibayar"); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if len(g.keys) != 3 {
+		t.Fatalf("gateway was called %d times, want 3", len(g.keys))
+	}
+	for _, key := range g.keys {
+		if key != g.keys[0] {
+			t.Errorf("idempotency keys = %v, want the same key on every attempt", g.keys)
+			break
+		}
+	}
+	if len(g.messages) != 1 || g.messages[0].Text.Body != "*Invoice*\n\nSegera dibayar" {
+		t.Errorf("gateway got %+v, want the subject in bold on top of the body", g.messages)
+	}
+}
+
+func TestSendGivesUpAfterMaxRetries(t *testing.T) {
+	g := &gateway{unavailable: 5}
+	wn := newTestNotifier(t, g)
+
+	if _, err := wn.SendText(context.Background(), "081234567890", "Halo"); err == nil {
+		t.Fatal("SendText succeeded while the gateway is unavailable")
+	}
+	if len(g.keys) != 3 {
+		t.Errorf("gateway was called %d times, want the first attempt and 2 retries", len(g.keys))
+	}
+}
+
+func TestSendEndsWithContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	wn := NewWhatsappNotifier(Config{APIURL: server.URL, Token: "wa-token", HTTPClient: server.Client()})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := wn.Send(ctx, "081234567890", "", "Halo")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Send error = %v, want the deadline of the context", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Send took %s, want it to end with the context", elapsed)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	g := &gateway{}
+	wn := newTestNotifier(t, g, "081234567890", "not a phone", "+6289876543210")
+
+	err := wn.Notify("Server down")
+	if err == nil || !strings.Contains(err.Error(), "not a phone") {
+		t.Errorf("Notify error = %v, want the invalid recipient reported", err)
+	}
+	if len(g.messages) != 2 {
+		t.Errorf("gateway got %d messages, want one per valid recipient", len(g.messages))
+	}
+}
+
+func TestSendReminder(t *testing.T) {
+	g := &gateway{}
+	wn := newTestNotifier(t, g)
+
+	reminder := ReminderData{Number: "INV/2026/001", CustomerName: "Budi", Total: 150000, DueDate: time.Date(2026, 10, 30, 0, 0, 0, 0, time.UTC)}
+	if _, err := wn.SendReminder(context.Background(), "081234567890", reminder); err != nil {
+		t.Fatalf("SendReminder: %v", err)
+	}
+
+	body := g.messages[0].Text.Body
+	for _, want := range []string{"Halo Budi,", "*INV/2026/001*"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("reminder %q does not contain %q", body, want)
+		}
+	}
+}
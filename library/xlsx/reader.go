@@ -0,0 +1,196 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ErrNoSheet declare specific error for a workbook without worksheet
+var ErrNoSheet = fmt.Errorf("xlsx: workbook has no worksheet")
+
+type workbookXML struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type relationshipsXML struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type sharedStringsXML struct {
+	Items []richTextXML `xml:"si"`
+}
+
+type richTextXML struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t richTextXML) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+
+	var b strings.Builder
+	for _, r := range t.Runs {
+		b.WriteString(r.Text)
+	}
+	return b.String()
+}
+
+type worksheetXML struct {
+	Rows []struct {
+		Index int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string      `xml:"r,attr"`
+			Type   string      `xml:"t,attr"`
+			Value  string      `xml:"v"`
+			Inline richTextXML `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ReadRows reads the cells of the first worksheet of the workbook as text, row by row.
+// Empty rows in between are kept as empty rows so the row numbers match the sheet.
+func ReadRows(r io.ReaderAt, size int64) ([][]string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	sharedStrings := sharedStringsXML{}
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeFile(f, &sharedStrings); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet := worksheetXML{}
+	if err := decodeFile(files[sheetPath], &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := [][]string{}
+	for i, row := range sheet.Rows {
+		index := row.Index
+		if index == 0 {
+			index = i + 1
+		}
+		for len(rows) < index-1 {
+			rows = append(rows, []string{})
+		}
+
+		values := []string{}
+		for j, cell := range row.Cells {
+			col := j
+			if cell.Ref != "" {
+				col = columnIndex(cell.Ref)
+			}
+			for len(values) < col {
+				values = append(values, "")
+			}
+
+			value := cell.Value
+			switch cell.Type {
+			case "s":
+				n, err := strconv.Atoi(cell.Value)
+				if err != nil || n < 0 || n >= len(sharedStrings.Items) {
+					return nil, fmt.Errorf("xlsx: invalid shared string %q in cell %s", cell.Value, cell.Ref)
+				}
+				value = sharedStrings.Items[n].String()
+			case "inlineStr":
+				value = cell.Inline.String()
+			case "b":
+				value = strconv.FormatBool(cell.Value == "1")
+			}
+			values = append(values, value)
+		}
+
+		rows = append(rows, values)
+	}
+
+	return rows, nil
+}
+
+// firstSheetPath resolves the file of the first sheet of the workbook
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	workbook := workbookXML{}
+	relationships := relationshipsXML{}
+
+	wf, ok := files["xl/workbook.xml"]
+	rf, okRels := files["xl/_rels/workbook.xml.rels"]
+	if ok && okRels {
+		if err := decodeFile(wf, &workbook); err != nil {
+			return "", err
+		}
+		if err := decodeFile(rf, &relationships); err != nil {
+			return "", err
+		}
+
+		if len(workbook.Sheets) > 0 {
+			for _, rel := range relationships.Relationships {
+				if rel.ID != workbook.Sheets[0].RID {
+					continue
+				}
+
+				target := strings.TrimPrefix(rel.Target, "/")
+				if !strings.HasPrefix(target, "xl/") {
+					target = path.Join("xl", target)
+				}
+				if _, ok := files[target]; ok {
+					return target, nil
+				}
+			}
+		}
+	}
+
+	if _, ok := files["xl/worksheets/sheet1.xml"]; ok {
+		return "xl/worksheets/sheet1.xml", nil
+	}
+
+	return "", ErrNoSheet
+}
+
+func decodeFile(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return xml.NewDecoder(rc).Decode(v)
+}
+
+// columnIndex converts the letters of a cell reference into a zero based column, e.g. "C12" into 2
+func columnIndex(ref string) int {
+	col := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		col = col*26 + int(ch-'A'+1)
+	}
+	return col - 1
+}
@@ -0,0 +1,214 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Style of a cell, it refers to the cell formats written in styles.xml
+type Style int
+
+const (
+	StyleDefault Style = iota
+	StyleHeader
+	StyleNumber
+	StyleDecimal
+	StyleRupiah
+	StyleDate
+)
+
+// Cell is a typed cell value. Strings are written as text, bools as booleans,
+// numbers and json.Number as numbers and time.Time as dates
+type Cell struct {
+	Value interface{}
+	Style Style
+}
+
+// Writer streams a single sheet workbook, the rows are written to the underlying writer as they come
+type Writer struct {
+	zw     *zip.Writer
+	sheet  *bufio.Writer
+	row    int
+	closed bool
+}
+
+const contentTypesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/></Types>`
+
+const rootRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+
+const workbookRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`
+
+// stylesXML holds the cell formats in the order of the Style constants
+const stylesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><numFmts count="2"><numFmt numFmtId="164" formatCode="&quot;Rp. &quot;#,##0.00"/><numFmt numFmtId="165" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts><fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts><fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills><borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders><cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs><cellXfs count="6"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/><xf numFmtId="3" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/><xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/><xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/><xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs></styleSheet>`
+
+// excelEpoch is the day zero of the 1900 date system used for date cells
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// NewWriter starts a workbook with a single sheet named sheetName
+func NewWriter(w io.Writer, sheetName string) (*Writer, error) {
+	zw := zip.NewWriter(w)
+
+	var name strings.Builder
+	xml.EscapeText(&name, []byte(sheetName))
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/workbook.xml", fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`, name.String())},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML},
+		{"xl/styles.xml", stylesXML},
+	}
+
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(fw, f.content); err != nil {
+			return nil, err
+		}
+	}
+
+	fw, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := bufio.NewWriter(fw)
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	return &Writer{zw: zw, sheet: sheet}, nil
+}
+
+// WriteHeader writes a row of bold text cells
+func (w *Writer) WriteHeader(headers []string) error {
+	cells := make([]Cell, len(headers))
+	for i, h := range headers {
+		cells[i] = Cell{Value: h, Style: StyleHeader}
+	}
+	return w.WriteRow(cells)
+}
+
+// WriteRow writes the next row of the sheet
+func (w *Writer) WriteRow(cells []Cell) error {
+	if w.closed {
+		return fmt.Errorf("xlsx: write to a closed writer")
+	}
+
+	w.row++
+	fmt.Fprintf(w.sheet, `<row r="%d">`, w.row)
+	for i, cell := range cells {
+		ref := ColumnName(i) + strconv.Itoa(w.row)
+		if err := w.writeCell(ref, cell); err != nil {
+			return err
+		}
+	}
+	_, err := w.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (w *Writer) writeCell(ref string, cell Cell) error {
+	style := ""
+	if cell.Style != StyleDefault {
+		style = fmt.Sprintf(` s="%d"`, cell.Style)
+	}
+
+	switch v := cell.Value.(type) {
+	case nil:
+		return nil
+	case bool:
+		b := 0
+		if v {
+			b = 1
+		}
+		fmt.Fprintf(w.sheet, `<c r="%s" t="b"%s><v>%d</v></c>`, ref, style, b)
+	case json.Number:
+		if _, err := v.Float64(); err != nil {
+			return w.writeText(ref, style, v.String())
+		}
+		fmt.Fprintf(w.sheet, `<c r="%s"%s><v>%s</v></c>`, ref, style, v.String())
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		fmt.Fprintf(w.sheet, `<c r="%s"%s><v>%d</v></c>`, ref, style, v)
+	case float32, float64:
+		f := toFloat64(v)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return w.writeText(ref, style, fmt.Sprintf("%v", f))
+		}
+		fmt.Fprintf(w.sheet, `<c r="%s"%s><v>%s</v></c>`, ref, style, strconv.FormatFloat(f, 'f', -1, 64))
+	case time.Time:
+		if cell.Style == StyleDefault {
+			style = fmt.Sprintf(` s="%d"`, StyleDate)
+		}
+		// the cell keeps the wall clock of the time, excel dates have no time zone
+		wall := time.Date(v.Year(), v.Month(), v.Day(), v.Hour(), v.Minute(), v.Second(), v.Nanosecond(), time.UTC)
+		days := wall.Sub(excelEpoch).Hours() / 24
+		fmt.Fprintf(w.sheet, `<c r="%s"%s><v>%s</v></c>`, ref, style, strconv.FormatFloat(days, 'f', -1, 64))
+	case string:
+		return w.writeText(ref, style, v)
+	default:
+		return w.writeText(ref, style, fmt.Sprintf("%v", v))
+	}
+
+	return nil
+}
+
+func (w *Writer) writeText(ref string, style string, text string) error {
+	fmt.Fprintf(w.sheet, `<c r="%s" t="inlineStr"%s><is><t xml:space="preserve">`, ref, style)
+	if err := xml.EscapeText(w.sheet, []byte(text)); err != nil {
+		return err
+	}
+	_, err := w.sheet.WriteString(`</t></is></c>`)
+	return err
+}
+
+// Close ends the sheet and the workbook, it does not close the underlying writer
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	w.sheet.WriteString(`</sheetData></worksheet>`)
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+
+	return w.zw.Close()
+}
+
+// ColumnName converts a zero based column into its letters, e.g. 2 into "C" and 27 into "AB"
+func ColumnName(col int) string {
+	name := ""
+	for col >= 0 {
+		name = string(rune('A'+col%26)) + name
+		col = col/26 - 1
+	}
+	return name
+}
+
+func toFloat64(v interface{}) float64 {
+	switch f := v.(type) {
+	case float32:
+		return float64(f)
+	case float64:
+		return f
+	}
+	return 0
+}
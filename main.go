@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
 	"os"
@@ -8,9 +9,13 @@ import (
 
 	"luxe-beb-go/configs"
 	"luxe-beb-go/databases"
+	"luxe-beb-go/library/client"
 	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/mail"
 	"luxe-beb-go/library/notif"
+	"luxe-beb-go/library/whatsapp"
 	"luxe-beb-go/src/routes"
+	clientrequestlogrepository "luxe-beb-go/src/services/clientrequestlog/repository"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
@@ -54,13 +59,30 @@ func main() {
 
 	databases.MigrateUp()
 
+	// every http client logs its requests there, it is registered before the first client is created
+	client.RegisterRequestLogStorage(clientrequestlogrepository.NewClientRequestLogRepository(
+		data.NewMySQLStorage(db, "client_request_logs", client.ClientRequestLog{}, data.MysqlConfig{BusinessColumn: "businessId"}),
+	))
+
 	slackNotifier := notif.NewSlackNotifier(notif.SlackNotifierConfig{
 		Token:   config.SlackToken,
 		Channel: config.SlackAlertChannel,
 	})
 
 	if config.ActiveWorker == 1 {
-		// worker here
+		mailer, err := mail.NewFromConfig(config)
+		if err != nil {
+			log.Fatalln("failed to create mailer: ", err)
+		}
+		go mail.NewDispatcher(db, mailer, mail.DispatcherConfig{}).Run(context.Background())
+
+		router := notif.NewRouter(db, notif.RouterConfig{}).
+			Register(notif.ChannelSlack, slackNotifier).
+			Register(notif.ChannelEmail, mail.NotificationChannel{Mailer: mailer, From: mail.DefaultSender(config)}).
+			Register(notif.ChannelWhatsapp, whatsapp.NewWhatsappNotifierFromConfig(config))
+		go router.Run(context.Background())
+
+		go client.NewAcknowledgeSweeper(db, client.AcknowledgeSweeperConfig{}).Run(context.Background())
 	}
 
 	routes.RegisterRoutes(db, config, dataManager, slackNotifier)
@@ -10,6 +10,7 @@ import (
 
 	"luxe-beb-go/configs"
 	"luxe-beb-go/library"
+	"luxe-beb-go/library/appcontext"
 	"luxe-beb-go/library/types"
 	"luxe-beb-go/models"
 
@@ -92,6 +93,7 @@ func Auth(c *gin.Context) {
 	c.Set("BusinessID", claimJWT["BusinessID"])
 	c.Set("SupervisorUserID", claimJWT["SupervisorUserID"])
 	c.Set("UserID", claimJWT["ID"])
+	c.Set("UserName", claimJWT["Username"])
 	c.Set("Email", claimJWT["Email"])
 	c.Set("Type", claimJWT["Type"])
 
@@ -152,6 +154,7 @@ func Auth(c *gin.Context) {
 
 	method := c.Request.Method
 	hasAccess := false
+	isSuperAdmin := false
 	for rows.Next() {
 		var (
 			id           int64
@@ -175,6 +178,14 @@ func Auth(c *gin.Context) {
 		}
 		permissionObjArr = append(permissionObjArr, data)
 
+		if data.Action == models.PERMISSION_ACTION_SUPER_ADMIN {
+			isSuperAdmin = true
+		}
+
+		if hasAccess {
+			continue
+		}
+
 		checkRoute := true
 		arrRoutes := strings.Split(data.Route, "/")
 
@@ -195,7 +206,6 @@ func Auth(c *gin.Context) {
 
 		if checkRoute && data.Type == method {
 			hasAccess = true
-			break
 		}
 	}
 
@@ -208,6 +218,56 @@ func Auth(c *gin.Context) {
 		c.Abort()
 		return
 	}
+
+	// batas akses business & outlet
+	accesses := []struct {
+		Kind string `db:"kind"`
+		ID   string `db:"id"`
+	}{}
+	err = db.Select(&accesses, `
+	SELECT 'business' AS kind, business_id AS id FROM user_businesses WHERE user_id = ?
+	UNION ALL
+	SELECT 'outlet' AS kind, outlet_id AS id FROM user_outlets WHERE user_id = ?
+	`, claimJWT["ID"], claimJWT["ID"])
+	if err != nil {
+		log.Printf("failed to get the business & outlet access of user %v: %v\n", claimJWT["ID"], err)
+		response := types.Result{Status: "Warning", StatusCode: http.StatusInternalServerError, Message: "Failed to get the business & outlet access"}
+		result := gin.H{
+			"result": response,
+		}
+		c.JSON(http.StatusInternalServerError, result)
+		c.Abort()
+		return
+	}
+
+	businessIDs := []string{}
+	outletIDs := []string{}
+	for _, access := range accesses {
+		if access.Kind == "business" {
+			businessIDs = append(businessIDs, access.ID)
+		} else {
+			outletIDs = append(outletIDs, access.ID)
+		}
+	}
+
+	// the business picked on login only stays current while the user still holds it
+	if businessID := appcontext.BusinessID(c); businessID != "" && !isSuperAdmin && !hasID(businessIDs, businessID) {
+		c.Set("BusinessID", "")
+	}
+
+	c.Set("BusinessIDs", businessIDs)
+	c.Set("OutletIDs", outletIDs)
+	c.Set("IsSuperAdmin", isSuperAdmin)
+}
+
+// hasID tells whether the id is in the list of ids the user may access
+func hasID(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
 }
 
 func AuthPOS(c *gin.Context) {
@@ -290,6 +350,13 @@ func AuthPOS(c *gin.Context) {
 	c.Set("BusinessShiftID", claimJWT["BusinessShiftID"])
 	c.Set("IsCaptain", claimJWT["IsCaptain"])
 	c.Set("IsDisabledChangeBusinessPOS", claimJWT["IsDisabledChangeBusinessPOS"])
+	businessIDs := []string{}
+	if businessID := appcontext.BusinessID(c); businessID != "" {
+		businessIDs = append(businessIDs, businessID)
+	}
+	c.Set("BusinessIDs", businessIDs)
+	c.Set("OutletIDs", []string{})
+	c.Set("IsSuperAdmin", false)
 
 	if errRedis := redisClient.Set(
 		tokenString,
@@ -0,0 +1,231 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"luxe-beb-go/configs"
+	"luxe-beb-go/library/appcontext"
+	"luxe-beb-go/library/data"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis"
+)
+
+// DefaultResponseCacheTTL is how long a cached response is kept when no ttl is given
+const DefaultResponseCacheTTL = 5 * time.Minute
+
+const (
+	responseCachePrefix        = "response-cache:"
+	responseCacheVersionPrefix = "response-cache-version:"
+)
+
+var (
+	responseCacheOnce  sync.Once
+	responseCacheRedis *redis.Client
+)
+
+// cachedResponse is a response kept in redis
+type cachedResponse struct {
+	Status       int    `json:"status"`
+	ContentType  string `json:"contentType"`
+	Body         []byte `json:"body"`
+	ETag         string `json:"etag"`
+	LastModified string `json:"lastModified"`
+}
+
+// ResponseCache caches the 200 responses of a GET route in redis, keyed by the route, its query & the tenant of the caller.
+// The responses carry an ETag & Last-Modified and a matching If-None-Match or If-Modified-Since is answered with 304.
+// The cache of the route is dropped as soon as one of the tables is written through a MySQLStorage,
+// it must be put after the authentication so the tenant is known
+func ResponseCache(ttl time.Duration, tables ...string) gin.HandlerFunc {
+	if ttl <= 0 {
+		ttl = DefaultResponseCacheTTL
+	}
+
+	// connected at the route registration so the writes invalidate the cache from the start
+	redisClient := responseCacheClient()
+
+	return func(c *gin.Context) {
+		if redisClient == nil || (c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead) {
+			c.Next()
+			return
+		}
+
+		key, err := responseCacheKey(redisClient, c, tables)
+		if err != nil {
+			log.Printf("failed to build the response cache key of %s: %v", c.FullPath(), err)
+			c.Next()
+			return
+		}
+
+		if !strings.Contains(c.GetHeader("Cache-Control"), "no-cache") {
+			val, err := redisClient.Get(key).Result()
+			if err != nil && err != redis.Nil {
+				log.Printf("failed to get the response cache of %s: %v", c.FullPath(), err)
+			}
+
+			cached := cachedResponse{}
+			if err == nil && json.Unmarshal([]byte(val), &cached) == nil {
+				c.Header("X-Cache", "HIT")
+				writeCachedResponse(c, cached)
+				c.Abort()
+				return
+			}
+		}
+
+		writer := &bufferedWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}, status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		if writer.status != http.StatusOK {
+			c.Writer.WriteHeader(writer.status)
+			c.Writer.Write(writer.body.Bytes())
+			return
+		}
+
+		sum := sha1.Sum(writer.body.Bytes())
+		cached := cachedResponse{
+			Status:       writer.status,
+			ContentType:  c.Writer.Header().Get("Content-Type"),
+			Body:         writer.body.Bytes(),
+			ETag:         fmt.Sprintf(`"%s"`, hex.EncodeToString(sum[:])),
+			LastModified: time.Now().UTC().Format(http.TimeFormat),
+		}
+
+		val, err := json.Marshal(cached)
+		if err == nil {
+			err = redisClient.Set(key, val, ttl).Err()
+		}
+		if err != nil {
+			log.Printf("failed to store the response cache of %s: %v", c.FullPath(), err)
+		}
+
+		c.Header("X-Cache", "MISS")
+		writeCachedResponse(c, cached)
+	}
+}
+
+// writeCachedResponse answers with the response, or with 304 when the caller already holds it
+func writeCachedResponse(c *gin.Context, cached cachedResponse) {
+	c.Header("ETag", cached.ETag)
+	c.Header("Last-Modified", cached.LastModified)
+
+	if isNotModified(c.Request, cached) {
+		c.Writer.WriteHeader(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+
+	if cached.ContentType != "" {
+		c.Header("Content-Type", cached.ContentType)
+	}
+	c.Writer.WriteHeader(cached.Status)
+	if c.Request.Method == http.MethodHead {
+		c.Writer.WriteHeaderNow()
+		return
+	}
+	c.Writer.Write(cached.Body)
+}
+
+func isNotModified(req *http.Request, cached cachedResponse) bool {
+	if ifNoneMatch := req.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, etag := range strings.Split(ifNoneMatch, ",") {
+			etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+			if etag == "*" || etag == cached.ETag {
+				return true
+			}
+		}
+		return false
+	}
+
+	ifModifiedSince, err := http.ParseTime(req.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	lastModified, err := http.ParseTime(cached.LastModified)
+	if err != nil {
+		return false
+	}
+	return !lastModified.After(ifModifiedSince)
+}
+
+// responseCacheKey is made of the route, the query, the tenant & the write versions of the tables,
+// a write bumps the version of its table so the keys built before it are never read again
+func responseCacheKey(redisClient *redis.Client, c *gin.Context, tables []string) (string, error) {
+	versions := []interface{}{}
+	if len(tables) > 0 {
+		keys := make([]string, len(tables))
+		for i, table := range tables {
+			keys[i] = responseCacheVersionPrefix + table
+		}
+
+		var err error
+		versions, err = redisClient.MGet(keys...).Result()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	key := fmt.Sprintf("%s|%s|%s|%s|%v", c.Request.Method, c.FullPath(), c.Request.URL.Query().Encode(), responseCacheTenant(c), versions)
+	sum := sha1.Sum([]byte(key))
+
+	return responseCachePrefix + hex.EncodeToString(sum[:]), nil
+}
+
+// responseCacheTenant tells apart the callers which may see different data
+func responseCacheTenant(c *gin.Context) string {
+	if appcontext.IsSuperAdmin(c) {
+		return "super-admin"
+	}
+
+	businessIDs := append([]string{}, appcontext.BusinessIDs(c)...)
+	outletIDs := append([]string{}, appcontext.OutletIDs(c)...)
+	sort.Strings(businessIDs)
+	sort.Strings(outletIDs)
+
+	return fmt.Sprintf("%s:%s:%s", appcontext.BusinessID(c), strings.Join(businessIDs, ","), strings.Join(outletIDs, ","))
+}
+
+// responseCacheClient connects to redis once and invalidates the cache on the table writes,
+// it is nil when the configuration can not be read
+func responseCacheClient() *redis.Client {
+	responseCacheOnce.Do(func() {
+		config, err := configs.GetConfiguration()
+		if err != nil {
+			log.Printf("failed to get configuration, the responses are not cached: %v", err)
+			return
+		}
+
+		responseCacheRedis = redis.NewClient(&redis.Options{
+			Addr:     config.RedisAddr,
+			Password: config.RedisPassword,
+			DB:       config.RedisDB,
+		})
+
+		data.OnTableWrite(InvalidateResponseCache)
+	})
+
+	return responseCacheRedis
+}
+
+// InvalidateResponseCache drops the cached responses of the routes reading the table
+func InvalidateResponseCache(table string) {
+	if responseCacheRedis == nil {
+		return
+	}
+
+	if err := responseCacheRedis.Incr(responseCacheVersionPrefix + table).Err(); err != nil {
+		log.Printf("failed to invalidate the response cache of %s: %v", table, err)
+	}
+}
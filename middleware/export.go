@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"luxe-beb-go/library"
+	"luxe-beb-go/library/export"
+	"luxe-beb-go/library/http/response"
+	"luxe-beb-go/library/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ExportXLSX = "xlsx"
+	ExportCSV  = "csv"
+)
+
+// bufferedWriter keeps the response of the handler so it can be turned into a file
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body   *bytes.Buffer
+	status int
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if code > 0 {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.body.Len() > 0
+}
+
+// Export turns the response of a list route into a file when the query has export=xlsx or export=csv.
+// The route runs with the same filters but without Page & Size so every matching row is exported,
+// the Data of its result becomes the rows of the file. Other responses are sent back untouched.
+func Export(c *gin.Context) {
+	// the query is read from the url, c.Query would cache it before Page & Size are removed
+	query := c.Request.URL.Query()
+	format := strings.ToLower(query.Get("export"))
+	if c.Request.Method != http.MethodGet || format == "" {
+		c.Next()
+		return
+	}
+
+	if format != ExportXLSX && format != ExportCSV {
+		response := types.Result{Status: "Warning", StatusCode: http.StatusBadRequest, Message: fmt.Sprintf("export harus salah satu dari [%s %s]", ExportXLSX, ExportCSV)}
+		result := gin.H{
+			"result": response,
+		}
+		c.JSON(http.StatusBadRequest, result)
+		c.Abort()
+		return
+	}
+
+	query.Del("export")
+	query.Del("Page")
+	query.Del("Size")
+	c.Request.URL.RawQuery = query.Encode()
+
+	writer := &bufferedWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}, status: http.StatusOK}
+	c.Writer = writer
+	c.Next()
+	c.Writer = writer.ResponseWriter
+
+	var body struct {
+		Result struct {
+			Data json.RawMessage `json:"Data"`
+		} `json:"result"`
+	}
+	err := json.Unmarshal(writer.body.Bytes(), &body)
+	if writer.status != http.StatusOK || err != nil || !bytes.HasPrefix(bytes.TrimSpace(body.Result.Data), []byte("[")) {
+		c.Writer.WriteHeader(writer.status)
+		c.Writer.Write(writer.body.Bytes())
+		return
+	}
+
+	name := path.Base(c.Request.URL.Path)
+	table, err := export.FromJSON(name, body.Result.Data)
+	if err != nil {
+		c.Writer.WriteHeader(writer.status)
+		c.Writer.Write(writer.body.Bytes())
+		return
+	}
+
+	filename := fmt.Sprintf("%s_%s", name, library.UTCPlus7().Format("20060102150405"))
+	if format == ExportCSV {
+		response.CSV(c.Writer, http.StatusOK, table, filename)
+		return
+	}
+	response.EXCEL(c.Writer, http.StatusOK, table, filename)
+}
@@ -5,21 +5,32 @@ import (
 )
 
 type BankBulk struct {
-	ID   string `json:"ID" db:"id"`
-	Name string `json:"Name" db:"name" validate:"required"`
+	ID                   string `json:"ID" db:"id"`
+	Name                 string `json:"Name" db:"name" validate:"required"`
+	AccountNumberPattern string `json:"AccountNumberPattern" db:"account_number_pattern" validate:"omitempty,max=255,regexp"`
 
 	StatusID   string `json:"StatusID" db:"status_id"`
 	StatusName string `json:"StatusName" db:"status_name"`
+	Version    int    `json:"Version" db:"version"`
 }
 
 type Bank struct {
-	ID   string `json:"ID" db:"id"`
-	Name string `json:"Name" db:"name" validate:"required"`
+	ID                   string `json:"ID" db:"id"`
+	Name                 string `json:"Name" db:"name" validate:"required"`
+	AccountNumberPattern string `json:"AccountNumberPattern" db:"account_number_pattern" validate:"omitempty,max=255,regexp"`
 
 	StatusID string `json:"StatusID" db:"status_id"`
 	Status   Status `json:"Status"`
+	Version  int    `json:"Version" db:"version"`
 }
 
 type FindAllBankParams struct {
 	FindAllParams types.FindAllParams
 }
+
+type BankRequest struct {
+	Name                 string `json:"Name" form:"Name" validate:"required"`
+	AccountNumberPattern string `json:"AccountNumberPattern" form:"AccountNumberPattern" validate:"omitempty,max=255,regexp"`
+	// Version is the version the bank was read at, it stands for the If-Match header on update
+	Version int `json:"Version" form:"Version" validate:"omitempty,min=1"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"luxe-beb-go/library/types"
+)
+
+// DEFAULT_CURRENCY is the currency of a bank account when none is given
+const DEFAULT_CURRENCY = "IDR"
+
+type BankAccountBulk struct {
+	ID            string `json:"ID" db:"id"`
+	BusinessID    string `json:"BusinessID" db:"business_id"`
+	BankID        string `json:"BankID" db:"bank_id"`
+	BankName      string `json:"BankName" db:"bank_name"`
+	AccountNumber string `json:"AccountNumber" db:"account_number"`
+	HolderName    string `json:"HolderName" db:"holder_name"`
+	Branch        string `json:"Branch" db:"branch"`
+	Currency      string `json:"Currency" db:"currency"`
+	IsDefault     bool   `json:"IsDefault" db:"is_default"`
+
+	StatusID   string `json:"StatusID" db:"status_id"`
+	StatusName string `json:"StatusName" db:"status_name"`
+}
+
+type BankAccount struct {
+	ID            string `json:"ID" db:"id"`
+	BusinessID    string `json:"BusinessID" db:"business_id" validate:"required"`
+	BankID        string `json:"BankID" db:"bank_id" validate:"required"`
+	AccountNumber string `json:"AccountNumber" db:"account_number" validate:"required,bank_account,pattern_field=AccountNumberPattern"`
+	HolderName    string `json:"HolderName" db:"holder_name" validate:"required,max=255"`
+	Branch        string `json:"Branch" db:"branch" validate:"max=255"`
+	Currency      string `json:"Currency" db:"currency" validate:"required,len=3"`
+	IsDefault     bool   `json:"IsDefault" db:"is_default"`
+
+	StatusID string         `json:"StatusID" db:"status_id"`
+	Status   Status         `json:"Status"`
+	Bank     IDNameTemplate `json:"Bank"`
+
+	// AccountNumberPattern is the account number format of the bank, only used for validation
+	AccountNumberPattern string `json:"-"`
+}
+
+type FindAllBankAccountParams struct {
+	FindAllParams types.FindAllParams
+	BankID        string
+}
+
+type BankAccountRequest struct {
+	// BusinessID is the business owning the account, the current business of the user when it is empty
+	BusinessID    string `json:"BusinessID" form:"BusinessID"`
+	AccountNumber string `json:"AccountNumber" form:"AccountNumber" validate:"required"`
+	HolderName    string `json:"HolderName" form:"HolderName" validate:"required"`
+	Branch        string `json:"Branch" form:"Branch"`
+	Currency      string `json:"Currency" form:"Currency" validate:"omitempty,len=3"`
+	IsDefault     bool   `json:"IsDefault" form:"IsDefault"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"luxe-beb-go/library/types"
+)
+
+type CardProviderBulk struct {
+	ID   string `json:"ID" db:"id"`
+	Name string `json:"Name" db:"name" validate:"required"`
+
+	StatusID   string `json:"StatusID" db:"status_id"`
+	StatusName string `json:"StatusName" db:"status_name"`
+}
+
+type CardProvider struct {
+	ID   string `json:"ID" db:"id"`
+	Name string `json:"Name" db:"name" validate:"required"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+	Status   Status `json:"Status"`
+
+	Banks []IDNameTemplate `json:"Banks" validate:"dive"`
+}
+
+// CardProviderBank links a card provider to a bank issuing its cards
+type CardProviderBank struct {
+	ID             string `json:"ID" db:"id"`
+	CardProviderID string `json:"CardProviderID" db:"card_provider_id"`
+	BankID         string `json:"BankID" db:"bank_id"`
+}
+
+type CardProviderBankBulk struct {
+	CardProviderID string `json:"CardProviderID" db:"card_provider_id"`
+	BankID         string `json:"BankID" db:"bank_id"`
+	BankName       string `json:"BankName" db:"bank_name"`
+}
+
+type FindAllCardProviderParams struct {
+	FindAllParams types.FindAllParams
+}
+
+type CardProviderRequest struct {
+	Name    string   `json:"Name" form:"Name" validate:"required"`
+	BankIDs []string `json:"BankIDs" form:"BankIDs" validate:"dive,required"`
+}
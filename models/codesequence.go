@@ -0,0 +1,53 @@
+package models
+
+import (
+	"luxe-beb-go/library/types"
+)
+
+type CodeSequence struct {
+	ID          string `json:"ID" db:"id"`
+	BusinessID  string `json:"BusinessID" db:"business_id"`
+	Prefix      string `json:"Prefix" db:"prefix" validate:"required,max=50"`
+	Name        string `json:"Name" db:"name" validate:"required"`
+	Year        int    `json:"Year" db:"year" validate:"required"`
+	Sequence    int    `json:"Sequence" db:"sequence" validate:"gte=0"`
+	Format      string `json:"Format" db:"format" validate:"required,contains={SEQ"`
+	ResetYearly bool   `json:"ResetYearly" db:"reset_yearly"`
+
+	// IsShared tells the sequence has no business, it is used by every business without a sequence of the prefix
+	IsShared bool   `json:"IsShared" db:"-"`
+	NextCode string `json:"NextCode"`
+}
+
+type FindAllCodeSequenceParams struct {
+	FindAllParams types.FindAllParams
+	Prefix        string
+	Year          string
+}
+
+type CodeSequenceRequest struct {
+	Prefix      string `json:"Prefix" form:"Prefix" validate:"required,max=50"`
+	Name        string `json:"Name" form:"Name" validate:"required"`
+	Format      string `json:"Format" form:"Format"`
+	ResetYearly *bool  `json:"ResetYearly" form:"ResetYearly"`
+	// IsShared creates the sequence without business, only the super admin creates shared sequences
+	IsShared bool `json:"IsShared" form:"IsShared"`
+}
+
+// CodeSequenceNextRequest reserves the next code of the prefix
+type CodeSequenceNextRequest struct {
+	Prefix string `json:"Prefix" form:"Prefix" validate:"required,max=50"`
+}
+
+// CodeSequenceCode is a code reserved from a sequence
+type CodeSequenceCode struct {
+	Prefix string `json:"Prefix"`
+	Code   string `json:"Code"`
+}
+
+type CodeSequenceUpdateRequest struct {
+	Name        string `json:"Name" form:"Name" validate:"required"`
+	Format      string `json:"Format" form:"Format"`
+	Sequence    *int   `json:"Sequence" form:"Sequence" validate:"omitempty,gte=0"`
+	ResetYearly *bool  `json:"ResetYearly" form:"ResetYearly"`
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"luxe-beb-go/library/types"
+)
+
+type EmailOutboxBulk struct {
+	ID            string `json:"ID" db:"id"`
+	FromEmail     string `json:"FromEmail" db:"from_email"`
+	Subject       string `json:"Subject" db:"subject"`
+	Recipients    string `json:"Recipients" db:"recipients"`
+	Status        string `json:"Status" db:"status"`
+	Attempts      int    `json:"Attempts" db:"attempts"`
+	MaxAttempts   int    `json:"MaxAttempts" db:"max_attempts"`
+	NextAttemptAt string `json:"NextAttemptAt" db:"next_attempt_at"`
+	Provider      string `json:"Provider" db:"provider"`
+	LastError     string `json:"LastError" db:"last_error"`
+	SentAt        string `json:"SentAt" db:"sent_at"`
+	CreatedAt     string `json:"CreatedAt" db:"created_at"`
+}
+
+type EmailOutbox struct {
+	ID                string `json:"ID" db:"id"`
+	FromEmail         string `json:"FromEmail" db:"from_email"`
+	FromName          string `json:"FromName" db:"from_name"`
+	ReplyTo           string `json:"ReplyTo" db:"reply_to"`
+	Subject           string `json:"Subject" db:"subject"`
+	Status            string `json:"Status" db:"status"`
+	Attempts          int    `json:"Attempts" db:"attempts"`
+	MaxAttempts       int    `json:"MaxAttempts" db:"max_attempts"`
+	NextAttemptAt     string `json:"NextAttemptAt" db:"next_attempt_at"`
+	Provider          string `json:"Provider" db:"provider"`
+	ProviderMessageID string `json:"ProviderMessageID" db:"provider_message_id"`
+	LastError         string `json:"LastError" db:"last_error"`
+	SentAt            string `json:"SentAt" db:"sent_at"`
+	CreatedAt         string `json:"CreatedAt" db:"created_at"`
+
+	Recipients  []*EmailOutboxRecipient  `json:"Recipients"`
+	Attachments []*EmailOutboxAttachment `json:"Attachments"`
+	AttemptLog  []*EmailOutboxAttempt    `json:"AttemptLog"`
+}
+
+type EmailOutboxRecipient struct {
+	ID                string `json:"ID" db:"id"`
+	Kind              string `json:"Kind" db:"kind"`
+	Email             string `json:"Email" db:"email"`
+	Name              string `json:"Name" db:"name"`
+	Status            string `json:"Status" db:"status"`
+	ProviderMessageID string `json:"ProviderMessageID" db:"provider_message_id"`
+	LastError         string `json:"LastError" db:"last_error"`
+	SentAt            string `json:"SentAt" db:"sent_at"`
+}
+
+type EmailOutboxAttachment struct {
+	ID          string `json:"ID" db:"id"`
+	Filename    string `json:"Filename" db:"filename"`
+	ContentType string `json:"ContentType" db:"content_type"`
+	ContentID   string `json:"ContentID" db:"content_id"`
+	Size        int    `json:"Size" db:"size"`
+}
+
+type EmailOutboxAttempt struct {
+	ID         string `json:"ID" db:"id"`
+	Attempt    int    `json:"Attempt" db:"attempt"`
+	Status     string `json:"Status" db:"status"`
+	Provider   string `json:"Provider" db:"provider"`
+	Response   string `json:"Response" db:"response"`
+	Error      string `json:"Error" db:"error"`
+	StartedAt  string `json:"StartedAt" db:"started_at"`
+	FinishedAt string `json:"FinishedAt" db:"finished_at"`
+}
+
+type FindAllEmailOutboxParams struct {
+	FindAllParams types.FindAllParams
+	Status        string
+	Email         string
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"luxe-beb-go/library/types"
+)
+
+type NotificationRuleBulk struct {
+	ID               string `json:"ID" db:"id"`
+	BusinessID       string `json:"BusinessID" db:"business_id"`
+	Name             string `json:"Name" db:"name"`
+	Event            string `json:"Event" db:"event"`
+	Channel          string `json:"Channel" db:"channel"`
+	Target           string `json:"Target" db:"target"`
+	SubjectTemplate  string `json:"SubjectTemplate" db:"subject_template"`
+	BodyTemplate     string `json:"BodyTemplate" db:"body_template"`
+	DedupSeconds     int    `json:"DedupSeconds" db:"dedup_seconds"`
+	IgnoreQuietHours bool   `json:"IgnoreQuietHours" db:"ignore_quiet_hours"`
+
+	StatusID   string `json:"StatusID" db:"status_id"`
+	StatusName string `json:"StatusName" db:"status_name"`
+}
+
+type NotificationRule struct {
+	ID               string `json:"ID" db:"id"`
+	BusinessID       string `json:"BusinessID" db:"business_id"`
+	Name             string `json:"Name" db:"name" validate:"required,max=255"`
+	Event            string `json:"Event" db:"event" validate:"required,max=100"`
+	Channel          string `json:"Channel" db:"channel" validate:"required,oneof=slack email whatsapp in_app"`
+	Target           string `json:"Target" db:"target" validate:"max=255"`
+	SubjectTemplate  string `json:"SubjectTemplate" db:"subject_template" validate:"text_template"`
+	BodyTemplate     string `json:"BodyTemplate" db:"body_template" validate:"text_template"`
+	DedupSeconds     int    `json:"DedupSeconds" db:"dedup_seconds" validate:"gte=0"`
+	IgnoreQuietHours bool   `json:"IgnoreQuietHours" db:"ignore_quiet_hours"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+	Status   Status `json:"Status"`
+}
+
+type FindAllNotificationRuleParams struct {
+	FindAllParams types.FindAllParams
+	Event         string
+	Channel       string
+}
+
+type NotificationRuleRequest struct {
+	Name             string `json:"Name" form:"Name" validate:"required,max=255"`
+	Event            string `json:"Event" form:"Event" validate:"required,max=100"`
+	Channel          string `json:"Channel" form:"Channel" validate:"required,oneof=slack email whatsapp in_app"`
+	Target           string `json:"Target" form:"Target" validate:"max=255"`
+	SubjectTemplate  string `json:"SubjectTemplate" form:"SubjectTemplate"`
+	BodyTemplate     string `json:"BodyTemplate" form:"BodyTemplate"`
+	DedupSeconds     int    `json:"DedupSeconds" form:"DedupSeconds" validate:"gte=0"`
+	IgnoreQuietHours bool   `json:"IgnoreQuietHours" form:"IgnoreQuietHours"`
+}
+
+type Notification struct {
+	ID          string `json:"ID" db:"id"`
+	Event       string `json:"Event" db:"event"`
+	Channel     string `json:"Channel" db:"channel"`
+	Recipient   string `json:"Recipient" db:"recipient"`
+	Subject     string `json:"Subject" db:"subject"`
+	Body        string `json:"Body" db:"body"`
+	RepeatCount int    `json:"RepeatCount" db:"repeat_count"`
+	Status      string `json:"Status" db:"status"`
+	SentAt      string `json:"SentAt" db:"sent_at"`
+	ReadAt      string `json:"ReadAt" db:"read_at"`
+	CreatedAt   string `json:"CreatedAt" db:"created_at"`
+}
+
+type FindAllNotificationParams struct {
+	FindAllParams types.FindAllParams
+	UserID        string
+	Unread        bool
+}
+
+type NotificationPreference struct {
+	ID         string `json:"ID" db:"id"`
+	UserID     string `json:"-" db:"user_id"`
+	Event      string `json:"Event" db:"event"`
+	Channel    string `json:"Channel" db:"channel"`
+	Address    string `json:"Address" db:"address"`
+	Enabled    bool   `json:"Enabled" db:"enabled"`
+	QuietStart string `json:"QuietStart" db:"quiet_start"`
+	QuietEnd   string `json:"QuietEnd" db:"quiet_end"`
+}
+
+type NotificationPreferenceRequest struct {
+	Event      string `json:"Event" form:"Event" validate:"max=100"`
+	Channel    string `json:"Channel" form:"Channel" validate:"omitempty,oneof=* slack email whatsapp in_app"`
+	Address    string `json:"Address" form:"Address" validate:"max=255"`
+	Enabled    *bool  `json:"Enabled" form:"Enabled"`
+	QuietStart string `json:"QuietStart" form:"QuietStart" validate:"omitempty,clock"`
+	QuietEnd   string `json:"QuietEnd" form:"QuietEnd" validate:"required_with=QuietStart,omitempty,clock"`
+}
+
+type NotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceRequest `json:"Preferences" form:"Preferences" validate:"dive"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"luxe-beb-go/library/types"
+)
+
+type OutletBulk struct {
+	ID           string `json:"ID" db:"id"`
+	BusinessID   string `json:"BusinessID" db:"business_id" validate:"required"`
+	BusinessName string `json:"BusinessName" db:"business_name"`
+	Name         string `json:"Name" db:"name" validate:"required"`
+	Address      string `json:"Address" db:"address"`
+
+	StatusID   string `json:"StatusID" db:"status_id"`
+	StatusName string `json:"StatusName" db:"status_name"`
+}
+
+type Outlet struct {
+	ID         string         `json:"ID" db:"id"`
+	BusinessID string         `json:"BusinessID" db:"business_id" validate:"required"`
+	Business   IDNameTemplate `json:"Business"`
+	Name       string         `json:"Name" db:"name" validate:"required"`
+	Address    string         `json:"Address" db:"address"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+	Status   Status `json:"Status"`
+}
+
+type FindAllOutletParams struct {
+	FindAllParams types.FindAllParams
+}
+
+type OutletRequest struct {
+	BusinessID string `json:"BusinessID" form:"BusinessID" validate:"required"`
+	Name       string `json:"Name" form:"Name" validate:"required"`
+	Address    string `json:"Address" form:"Address"`
+}
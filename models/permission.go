@@ -0,0 +1,15 @@
+package models
+
+var (
+	// PERMISSION_ACTION_SUPER_ADMIN marks the permission that lifts the business/outlet scoping
+	PERMISSION_ACTION_SUPER_ADMIN = "SuperAdmin"
+)
+
+type Permission struct {
+	ID      uint   `json:"ID" db:"id"`
+	Package string `json:"Package" db:"package"`
+	Name    string `json:"Name" db:"name"`
+	Action  string `json:"Action" db:"action"`
+	Type    string `json:"Type" db:"type"`
+	Route   string `json:"Route" db:"route"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"luxe-beb-go/library/types"
+)
+
+type ReferenceBulk struct {
+	ID   string `json:"ID" db:"id"`
+	Name string `json:"Name" db:"name" validate:"required"`
+
+	StatusID   string `json:"StatusID" db:"status_id"`
+	StatusName string `json:"StatusName" db:"status_name"`
+}
+
+type Reference struct {
+	ID   string `json:"ID" db:"id"`
+	Name string `json:"Name" db:"name" validate:"required"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+	Status   Status `json:"Status"`
+}
+
+type FindAllReferenceParams struct {
+	FindAllParams types.FindAllParams
+}
+
+type ReferenceRequest struct {
+	Name string `json:"Name" form:"Name" validate:"required"`
+}
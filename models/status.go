@@ -11,3 +11,26 @@ type Status struct {
 	ID   string `db:"id" json:"ID"`
 	Name string `db:"name" json:"Name"`
 }
+
+type UpdateStatusRequest struct {
+	ID          []string `json:"ID" form:"ID" validate:"required,min=1,dive,required"`
+	NewStatusID string   `json:"NewStatusID" form:"NewStatusID" validate:"required"`
+}
+
+// BatchUpdateStatusRequest updates the status of many rows at once.
+// Without IsPartialSuccess nothing is updated when one of the ID fails
+type BatchUpdateStatusRequest struct {
+	ID               []string `json:"ID" validate:"required,min=1,max=500,unique,dive,required"`
+	NewStatusID      string   `json:"NewStatusID" validate:"required"`
+	IsPartialSuccess bool     `json:"IsPartialSuccess"`
+}
+
+// HasStatus tells whether the status id is one of the statuses
+func HasStatus(statuses []*Status, id string) bool {
+	for _, status := range statuses {
+		if status.ID == id {
+			return true
+		}
+	}
+	return false
+}
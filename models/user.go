@@ -13,24 +13,27 @@ type UserBulk struct {
 
 	StatusID   string `json:"StatusID" db:"status_id"`
 	StatusName string `json:"StatusName" db:"status_name"`
+	Version    int    `json:"Version" db:"version"`
 }
 
 type User struct {
 	ID       string `json:"ID" db:"id"`
 	Name     string `json:"Name" db:"name" validate:"required"`
-	Email    string `json:"Email" db:"email"`
-	Username string `json:"Username" db:"username"`
+	Email    string `json:"Email" db:"email" validate:"omitempty,email,unique_in=users.email"`
+	Username string `json:"Username" db:"username" validate:"omitempty,unique_in=users.username"`
 	Password string `json:"Password" db:"password" validate:"required"`
 
 	StatusID string `json:"StatusID" db:"status_id"`
 	Status   Status `json:"Status"`
+	Version  int    `json:"Version" db:"version"`
 }
 
 type UserLogin struct {
-	ID    string `json:"ID" db:"id"`
-	Name  string `json:"Name" db:"name" validate:"required"`
-	Token string `json:"Token"`
-	Email string `json:"Email" db:"email" validate:"required"`
+	ID         string `json:"ID" db:"id"`
+	Name       string `json:"Name" db:"name" validate:"required"`
+	Token      string `json:"Token"`
+	Email      string `json:"Email" db:"email" validate:"required"`
+	BusinessID string `json:"BusinessID"`
 
 	StatusID string `json:"StatusID" db:"status_id"`
 	Status   Status `json:"Status"`
@@ -43,3 +46,25 @@ type FindAllUserParams struct {
 	Username      string
 	Password      string
 }
+
+type UserRequest struct {
+	Name     string `json:"Name" form:"Name" validate:"required"`
+	Email    string `json:"Email" form:"Email" validate:"required,email"`
+	Username string `json:"Username" form:"Username" validate:"required"`
+	Password string `json:"Password" form:"Password" validate:"required"`
+}
+
+type UserUpdateRequest struct {
+	Name     string `json:"Name" form:"Name" validate:"required"`
+	Email    string `json:"Email" form:"Email" validate:"required,email"`
+	Username string `json:"Username" form:"Username" validate:"required"`
+	// Version is the version the user was read at, it stands for the If-Match header on update
+	Version int `json:"Version" form:"Version" validate:"omitempty,min=1"`
+}
+
+type LoginRequest struct {
+	Username string `json:"Username" form:"Username" validate:"required"`
+	Password string `json:"Password" form:"Password" validate:"required"`
+	// BusinessID is the business to work on, the first business of the user when it is empty
+	BusinessID string `json:"BusinessID" form:"BusinessID"`
+}
@@ -7,7 +7,7 @@ VALUES
 INSERT INTO
   code_sequences (prefix, sequence, name, year)
 VALUES
-  ("BAG", 0, "Bags", 2024),;
+  ("BAG", 0, "Bags", 2024);
 
 -- DAYS
 TRUNCATE days;
@@ -40,7 +40,7 @@ VALUES
   ('4', 'JCB', '2021-10-22 09:55:00', 0, '2021-10-22 09:55:00', 0),
   ('5', 'UnionPay', '2021-10-22 09:55:00', 0, '2021-10-22 09:55:00', 0);
 
-TRUNCATE card_types;
+TRUNCATE card_type;
 INSERT INTO
   card_type (id, name)
 VALUES
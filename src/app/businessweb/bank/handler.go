@@ -1,7 +1,7 @@
 package bank
 
 import (
-	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/jmoiron/sqlx"
@@ -16,7 +16,9 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/http/request"
 	"luxe-beb-go/library/http/response"
+	"luxe-beb-go/library/importer"
 	"luxe-beb-go/library/notif"
 	"luxe-beb-go/library/types"
 )
@@ -29,6 +31,7 @@ var (
 type BankHandler struct {
 	BankUsecase bank.Usecase
 	dataManager *data.Manager
+	importer    *importer.Importer
 	Result      gin.H
 	Status      int
 	notifier    *notif.SlackNotifier
@@ -42,20 +45,22 @@ func (h BankHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, slackNo
 
 	uBank := usecase.NewBankUsecase(db, &bankRepo)
 
-	base := &BankHandler{BankUsecase: uBank, dataManager: dataManager, notifier: slackNotifier}
+	base := &BankHandler{BankUsecase: uBank, dataManager: dataManager, importer: importer.New(dataManager), notifier: slackNotifier}
 
 	rs := v.Group("/banks")
 	{
-		rs.GET("", middleware.Auth, base.FindAll)
+		rs.GET("", middleware.Auth, middleware.ResponseCache(middleware.DefaultResponseCacheTTL, "banks", "status"), base.FindAll)
 		rs.GET("/:id", middleware.Auth, base.Find)
 		rs.POST("", middleware.Auth, base.Create)
+		rs.POST("/import", middleware.Auth, base.Import)
 		rs.PUT("/:id", middleware.Auth, base.Update)
 		rs.PUT("/status", middleware.Auth, base.UpdateStatus)
+		rs.PUT("/status/batch", middleware.Auth, base.BatchUpdateStatus)
 	}
 
 	status := v.Group("/statuses")
 	{
-		status.GET("/banks", middleware.AuthCheckIP, base.FindStatus)
+		status.GET("/banks", middleware.AuthCheckIP, middleware.ResponseCache(middleware.DefaultResponseCacheTTL, "status"), base.FindStatus)
 	}
 }
 
@@ -104,6 +109,7 @@ func (h *BankHandler) Find(c *gin.Context) {
 		return
 	}
 
+	response.ETag(c, result.Version)
 	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Bank Berhasil Ditampilkan", Data: result}
 	h.Result = gin.H{
 		"result": dataresponse,
@@ -114,10 +120,17 @@ func (h *BankHandler) Find(c *gin.Context) {
 
 func (h *BankHandler) Create(c *gin.Context) {
 	var err *types.Error
-	var obj models.Bank
+	var req models.BankRequest
 	var data *models.Bank
 
-	obj.Name = c.PostForm("Name")
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".BankHandler->Create()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	obj := models.Bank{Name: req.Name, AccountNumberPattern: req.AccountNumberPattern}
 
 	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
 		data, err = h.BankUsecase.Create(c, obj)
@@ -144,12 +157,26 @@ func (h *BankHandler) Create(c *gin.Context) {
 
 func (h *BankHandler) Update(c *gin.Context) {
 	var err *types.Error
-	var obj models.Bank
+	var req models.BankRequest
 	var data *models.Bank
 
 	id := c.Param("id")
 
-	obj.Name = c.PostForm("Name")
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".BankHandler->Update()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	version, errVersion := request.IfMatch(c, req.Version)
+	if errVersion != nil {
+		errVersion.Path = ".BankHandler->Update()" + errVersion.Path
+		response.Error(c, h.notifier, errVersion.Message, errVersion.StatusCode, *errVersion)
+		return
+	}
+
+	obj := models.Bank{Name: req.Name, AccountNumberPattern: req.AccountNumberPattern, Version: version}
 
 	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
 		data, err = h.BankUsecase.Update(c, id, obj)
@@ -161,10 +188,18 @@ func (h *BankHandler) Update(c *gin.Context) {
 
 	if errTransaction != nil {
 		errTransaction.Path = ".BankHandler->Update()" + errTransaction.Path
+		if errTransaction.StatusCode == http.StatusPreconditionFailed {
+			current, errFind := h.BankUsecase.Find(c, id)
+			if errFind == nil {
+				response.PreconditionFailed(c, "Data Bank telah diubah oleh pengguna lain, muat ulang data sebelum memperbarui", current.Version, current)
+				return
+			}
+		}
 		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
 		return
 	}
 
+	response.ETag(c, data.Version)
 	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Bank Berhasil Diperbarui", Data: data}
 	h.Result = gin.H{
 		"result": dataresponse,
@@ -190,26 +225,19 @@ func (h *BankHandler) FindStatus(c *gin.Context) {
 
 func (h *BankHandler) UpdateStatus(c *gin.Context) {
 	var err *types.Error
+	var req models.UpdateStatusRequest
 	var data *models.Bank
 
-	var ids []*models.IDNameTemplate
-
-	newStatusID := c.PostForm("NewStatusID")
-
-	errJson := json.Unmarshal([]byte(c.PostForm("ID")), &ids)
-	if errJson != nil {
-		err = &types.Error{
-			Path:  ".BankHandler->UpdateStatus()",
-			Error: errJson,
-			Type:  "convert-error",
-		}
-		response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".BankHandler->UpdateStatus()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
 		return
 	}
 
 	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
-		for _, id := range ids {
-			data, err = h.BankUsecase.UpdateStatus(c, id.ID, newStatusID)
+		for _, id := range req.ID {
+			data, err = h.BankUsecase.UpdateStatus(c, id, req.NewStatusID)
 			if err != nil {
 				return err
 			}
@@ -231,3 +259,108 @@ func (h *BankHandler) UpdateStatus(c *gin.Context) {
 
 	c.JSON(http.StatusOK, h.Result)
 }
+
+// BatchUpdateStatus updates the status of every ID and answers with the result of each of them
+func (h *BankHandler) BatchUpdateStatus(c *gin.Context) {
+	var req models.BatchUpdateStatusRequest
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".BankHandler->BatchUpdateStatus()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	statuses, err := h.BankUsecase.FindStatus(c)
+	if err != nil {
+		err.Path = ".BankHandler->BatchUpdateStatus()" + err.Path
+		response.Error(c, h.notifier, err.Message, http.StatusInternalServerError, *err)
+		return
+	}
+	if !models.HasStatus(statuses, req.NewStatusID) {
+		errStatus := types.Error{
+			Path:       ".BankHandler->BatchUpdateStatus()",
+			Message:    fmt.Sprintf("NewStatusID %s tidak ditemukan", req.NewStatusID),
+			Error:      data.ErrInvalidStatus,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+		response.Error(c, h.notifier, errStatus.Message, errStatus.StatusCode, errStatus)
+		return
+	}
+
+	results, isSuccess := h.dataManager.RunBatch(c, req.ID, req.IsPartialSuccess, func(tctx *gin.Context, id string) (interface{}, *types.Error) {
+		return h.BankUsecase.UpdateStatus(tctx, id, req.NewStatusID)
+	})
+
+	status, statusCode, message := "Sukses", http.StatusOK, "Status Bank Berhasil Diperbarui"
+	if !isSuccess {
+		status, statusCode, message = "Warning", http.StatusUnprocessableEntity, "Status Bank Gagal Diperbarui"
+		if req.IsPartialSuccess {
+			statusCode, message = http.StatusMultiStatus, "Sebagian Status Bank Gagal Diperbarui"
+		}
+	}
+
+	dataresponse := types.Result{Status: status, StatusCode: statusCode, Message: message, Data: results}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(statusCode, h.Result)
+}
+
+func (h *BankHandler) Import(c *gin.Context) {
+	options, errOptions := importer.OptionsFromRequest(c)
+	if errOptions != nil {
+		errOptions.Path = ".BankHandler->Import()" + errOptions.Path
+		response.Error(c, h.notifier, errOptions.Message, errOptions.StatusCode, *errOptions)
+		return
+	}
+
+	rows, errRead := importer.ReadRequest(c, "File")
+	if errRead != nil {
+		errRead.Path = ".BankHandler->Import()" + errRead.Path
+		response.Error(c, h.notifier, errRead.Message, errRead.StatusCode, *errRead)
+		return
+	}
+
+	report, errImport := h.importer.Run(c, rows, options, func(tctx *gin.Context, row importer.Row) (string, *types.Error) {
+		var req models.BankRequest
+		if err := importer.DecodeRequest(tctx, row, &req); err != nil {
+			return "", err
+		}
+
+		data, err := h.BankUsecase.Create(tctx, models.Bank{Name: req.Name, AccountNumberPattern: req.AccountNumberPattern})
+		if err != nil {
+			return "", err
+		}
+
+		return data.ID, nil
+	})
+	if errImport != nil {
+		errImport.Path = ".BankHandler->Import()" + errImport.Path
+		response.Error(c, h.notifier, errImport.Message, http.StatusInternalServerError, *errImport)
+		return
+	}
+
+	if report.Failed > 0 && options.Mode == importer.ModeAtomic && !options.DryRun {
+		dataresponse := types.Result{Status: "Warning", StatusCode: http.StatusUnprocessableEntity, Message: "Import Data Bank Gagal, Tidak Ada Data Yang Disimpan", Data: report}
+		h.Result = gin.H{
+			"result": dataresponse,
+		}
+		c.JSON(http.StatusUnprocessableEntity, h.Result)
+		return
+	}
+
+	message := "Import Data Bank Berhasil"
+	if options.DryRun {
+		message = "Pratinjau Import Data Bank Berhasil"
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: message, Data: report}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
@@ -0,0 +1,255 @@
+package bank
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+	"luxe-beb-go/src/services/bank"
+)
+
+// fakeDriver only begins, commits and rolls back transactions, it counts them so a test can tell how the batch ran
+type fakeDriver struct {
+	mu        sync.Mutex
+	begins    int
+	commits   int
+	rollbacks int
+}
+
+type fakeConn struct{ driver *fakeDriver }
+
+type fakeTx struct{ driver *fakeDriver }
+
+var (
+	testDriver     = &fakeDriver{}
+	testDriverOnce sync.Once
+)
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) { return &fakeConn{driver: d}, nil }
+
+func (d *fakeDriver) reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.begins, d.commits, d.rollbacks = 0, 0, 0
+}
+
+func (d *fakeDriver) counts() (int, int, int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.begins, d.commits, d.rollbacks
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("fake driver does not run queries")
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	c.driver.begins++
+	return &fakeTx{driver: c.driver}, nil
+}
+
+func (t *fakeTx) Commit() error {
+	t.driver.mu.Lock()
+	defer t.driver.mu.Unlock()
+	t.driver.commits++
+	return nil
+}
+
+func (t *fakeTx) Rollback() error {
+	t.driver.mu.Lock()
+	defer t.driver.mu.Unlock()
+	t.driver.rollbacks++
+	return nil
+}
+
+// fakeBankUsecase updates the status of any bank except the ids in notFound
+type fakeBankUsecase struct {
+	bank.Usecase
+	notFound map[string]bool
+	updated  []string
+}
+
+func (u *fakeBankUsecase) FindStatus(c *gin.Context) ([]*models.Status, *types.Error) {
+	return []*models.Status{{ID: "1", Name: "Active"}, {ID: "2", Name: "Inactive"}}, nil
+}
+
+func (u *fakeBankUsecase) UpdateStatus(c *gin.Context, id string, newStatusID string) (*models.Bank, *types.Error) {
+	if _, ok := data.TxFromContext(c); !ok {
+		return nil, &types.Error{Message: "no transaction", Error: errors.New("no transaction"), StatusCode: http.StatusInternalServerError}
+	}
+	if u.notFound[id] {
+		return nil, &types.Error{Message: "Bank tidak ditemukan", Error: data.ErrNotFound, StatusCode: http.StatusNotFound}
+	}
+
+	u.updated = append(u.updated, id)
+	return &models.Bank{ID: id, StatusID: newStatusID}, nil
+}
+
+type batchResponse struct {
+	Result struct {
+		StatusCode int
+		Data       []data.BatchResult
+	} `json:"result"`
+}
+
+func newTestBankHandler(t *testing.T, u bank.Usecase) *BankHandler {
+	testDriverOnce.Do(func() {
+		sql.Register("fakebatch", testDriver)
+	})
+	testDriver.reset()
+
+	db, err := sqlx.Open("fakebatch", "")
+	if err != nil {
+		t.Fatalf("open fake database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return &BankHandler{BankUsecase: u, dataManager: data.NewManager(db)}
+}
+
+func sendBatch(h *BankHandler, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PUT("/banks/status/batch", h.BatchUpdateStatus)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/banks/status/batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	return w
+}
+
+func decodeBatch(t *testing.T, w *httptest.ResponseRecorder) batchResponse {
+	var res batchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("decode response %q: %v", w.Body.String(), err)
+	}
+	return res
+}
+
+func TestBatchUpdateStatus(t *testing.T) {
+	u := &fakeBankUsecase{}
+	h := newTestBankHandler(t, u)
+
+	w := sendBatch(h, `{"ID":["1","2","3"],"NewStatusID":"2"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	res := decodeBatch(t, w)
+	if len(res.Result.Data) != 3 {
+		t.Fatalf("got %d results, want 3", len(res.Result.Data))
+	}
+	for _, r := range res.Result.Data {
+		if !r.IsSuccess || r.StatusCode != http.StatusOK {
+			t.Errorf("result of %s = %+v, want success", r.ID, r)
+		}
+	}
+	if begins, commits, _ := testDriver.counts(); begins != 1 || commits != 1 {
+		t.Errorf("begins, commits = %d, %d, want a single committed transaction", begins, commits)
+	}
+}
+
+func TestBatchUpdateStatusRollsBackEveryID(t *testing.T) {
+	u := &fakeBankUsecase{notFound: map[string]bool{"2": true}}
+	h := newTestBankHandler(t, u)
+
+	w := sendBatch(h, `{"ID":["1","2","3"],"NewStatusID":"2"}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusUnprocessableEntity, w.Body.String())
+	}
+
+	want := map[string]int{"1": http.StatusFailedDependency, "2": http.StatusNotFound, "3": http.StatusFailedDependency}
+	for _, r := range decodeBatch(t, w).Result.Data {
+		if r.IsSuccess || r.StatusCode != want[r.ID] {
+			t.Errorf("result of %s = %+v, want status %d", r.ID, r, want[r.ID])
+		}
+	}
+	if begins, commits, rollbacks := testDriver.counts(); begins != 1 || commits != 0 || rollbacks != 1 {
+		t.Errorf("begins, commits, rollbacks = %d, %d, %d, want a single rolled back transaction", begins, commits, rollbacks)
+	}
+}
+
+func TestBatchUpdateStatusPartialSuccess(t *testing.T) {
+	u := &fakeBankUsecase{notFound: map[string]bool{"2": true}}
+	h := newTestBankHandler(t, u)
+
+	w := sendBatch(h, `{"ID":["1","2","3"],"NewStatusID":"2","IsPartialSuccess":true}`)
+	if w.Code != http.StatusMultiStatus {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusMultiStatus, w.Body.String())
+	}
+
+	want := map[string]bool{"1": true, "2": false, "3": true}
+	for _, r := range decodeBatch(t, w).Result.Data {
+		if r.IsSuccess != want[r.ID] {
+			t.Errorf("result of %s = %+v, want success %v", r.ID, r, want[r.ID])
+		}
+	}
+	if begins, commits, rollbacks := testDriver.counts(); begins != 3 || commits != 2 || rollbacks != 1 {
+		t.Errorf("begins, commits, rollbacks = %d, %d, %d, want a transaction per ID", begins, commits, rollbacks)
+	}
+}
+
+func TestBatchUpdateStatusPartialSuccessInsideTransaction(t *testing.T) {
+	u := &fakeBankUsecase{notFound: map[string]bool{"2": true}}
+	h := newTestBankHandler(t, u)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		_, isSuccess := h.dataManager.RunBatch(tctx, []string{"1", "2", "3"}, true, func(bctx *gin.Context, id string) (interface{}, *types.Error) {
+			return h.BankUsecase.UpdateStatus(bctx, id, "2")
+		})
+		if isSuccess {
+			t.Error("batch succeeded, want the ID 2 to fail")
+		}
+		return nil
+	})
+	if errTransaction != nil {
+		t.Fatalf("outer transaction: %v", errTransaction.Error)
+	}
+
+	// the outer transaction and one per ID, the failed ID does not roll back the other ones
+	if begins, commits, rollbacks := testDriver.counts(); begins != 4 || commits != 3 || rollbacks != 1 {
+		t.Errorf("begins, commits, rollbacks = %d, %d, %d, want a new transaction per ID", begins, commits, rollbacks)
+	}
+}
+
+func TestBatchUpdateStatusRefusesRequest(t *testing.T) {
+	cases := map[string]string{
+		"duplicate ID":      `{"ID":["1","1"],"NewStatusID":"2"}`,
+		"empty ID":          `{"ID":[],"NewStatusID":"2"}`,
+		"unknown status ID": `{"ID":["1"],"NewStatusID":"9"}`,
+	}
+
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			u := &fakeBankUsecase{}
+			h := newTestBankHandler(t, u)
+
+			w := sendBatch(h, body)
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusUnprocessableEntity, w.Body.String())
+			}
+			if len(u.updated) != 0 {
+				t.Errorf("updated %v, want nothing updated", u.updated)
+			}
+		})
+	}
+}
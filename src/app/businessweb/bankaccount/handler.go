@@ -0,0 +1,256 @@
+package bankaccount
+
+import (
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+
+	"luxe-beb-go/library/helpers"
+	"luxe-beb-go/middleware"
+	"luxe-beb-go/models"
+	"luxe-beb-go/src/services/bankaccount"
+	"luxe-beb-go/src/services/bankaccount/repository"
+	"luxe-beb-go/src/services/bankaccount/usecase"
+
+	"github.com/gin-gonic/gin"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/http/request"
+	"luxe-beb-go/library/http/response"
+	"luxe-beb-go/library/notif"
+	"luxe-beb-go/library/types"
+)
+
+type BankAccountHandler struct {
+	BankAccountUsecase bankaccount.Usecase
+	dataManager        *data.Manager
+	Result             gin.H
+	Status             int
+	notifier           *notif.SlackNotifier
+}
+
+func (h BankAccountHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, slackNotifier *notif.SlackNotifier, router *gin.Engine, v *gin.RouterGroup) {
+	bankAccountRepo := repository.NewBankAccountRepository(
+		data.NewMySQLStorage(db, "bank_accounts", models.BankAccount{}, data.MysqlConfig{BusinessColumn: "business_id"}),
+		data.NewMySQLStorage(db, "banks", models.Bank{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	uBankAccount := usecase.NewBankAccountUsecase(db, &bankAccountRepo)
+
+	base := &BankAccountHandler{BankAccountUsecase: uBankAccount, dataManager: dataManager, notifier: slackNotifier}
+
+	rs := v.Group("/banks/:id/accounts")
+	{
+		rs.GET("", middleware.Auth, base.FindAll)
+		rs.GET("/:accountID", middleware.Auth, base.Find)
+		rs.POST("", middleware.Auth, base.Create)
+		rs.PUT("/:accountID", middleware.Auth, base.Update)
+		rs.PUT("/status", middleware.Auth, base.UpdateStatus)
+	}
+
+	status := v.Group("/statuses")
+	{
+		status.GET("/bank-accounts", middleware.AuthCheckIP, base.FindStatus)
+	}
+}
+
+func (h *BankAccountHandler) FindAll(c *gin.Context) {
+	var params models.FindAllBankAccountParams
+	page, size := helpers.FilterFindAll(c)
+	filterFindAllParams := helpers.FilterFindAllParam(c)
+	params.FindAllParams = filterFindAllParams
+	params.BankID = c.Param("id")
+	datas, err := h.BankAccountUsecase.FindAll(c, params)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	params.FindAllParams.Page = -1
+	params.FindAllParams.Size = -1
+	length, err := h.BankAccountUsecase.Count(c, params)
+	if err != nil {
+		err.Path = ".BankAccountHandler->FindAll()" + err.Path
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	dataresponse := types.ResultAll{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Rekening Bank Berhasil Ditampilkan", TotalData: length, Page: page, Size: size, Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(h.Status, h.Result)
+}
+
+func (h *BankAccountHandler) Find(c *gin.Context) {
+	bankID := c.Param("id")
+	id := c.Param("accountID")
+
+	result, err := h.BankAccountUsecase.Find(c, bankID, id)
+	if err != nil {
+		err.Path = ".BankAccountHandler->Find()" + err.Path
+		if err.Error == data.ErrNotFound {
+			response.Error(c, h.notifier, "Bank account not found", http.StatusUnprocessableEntity, *err)
+			return
+		}
+		response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Rekening Bank Berhasil Ditampilkan", Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *BankAccountHandler) Create(c *gin.Context) {
+	var err *types.Error
+	var req models.BankAccountRequest
+	var data *models.BankAccount
+
+	bankID := c.Param("id")
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".BankAccountHandler->Create()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	obj := models.BankAccount{
+		BusinessID:    req.BusinessID,
+		AccountNumber: req.AccountNumber,
+		HolderName:    req.HolderName,
+		Branch:        req.Branch,
+		Currency:      req.Currency,
+		IsDefault:     req.IsDefault,
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.BankAccountUsecase.Create(tctx, bankID, obj)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".BankAccountHandler->Create()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Rekening Bank Berhasil Ditambahkan", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *BankAccountHandler) Update(c *gin.Context) {
+	var err *types.Error
+	var req models.BankAccountRequest
+	var data *models.BankAccount
+
+	bankID := c.Param("id")
+	id := c.Param("accountID")
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".BankAccountHandler->Update()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	obj := models.BankAccount{
+		AccountNumber: req.AccountNumber,
+		HolderName:    req.HolderName,
+		Branch:        req.Branch,
+		Currency:      req.Currency,
+		IsDefault:     req.IsDefault,
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.BankAccountUsecase.Update(tctx, bankID, id, obj)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".BankAccountHandler->Update()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Rekening Bank Berhasil Diperbarui", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *BankAccountHandler) FindStatus(c *gin.Context) {
+	datas, err := h.BankAccountUsecase.FindStatus(c)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Rekening Bank Status Berhasil Ditampilkan", Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *BankAccountHandler) UpdateStatus(c *gin.Context) {
+	var err *types.Error
+	var req models.UpdateStatusRequest
+	var data *models.BankAccount
+
+	bankID := c.Param("id")
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".BankAccountHandler->UpdateStatus()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		for _, id := range req.ID {
+			data, err = h.BankAccountUsecase.UpdateStatus(tctx, bankID, id, req.NewStatusID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".BankAccountHandler->UpdateStatus()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Status Rekening Bank Berhasil Diperbarui", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
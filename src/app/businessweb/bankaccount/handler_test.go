@@ -0,0 +1,146 @@
+package bankaccount
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+	"luxe-beb-go/src/services/bankaccount"
+	"luxe-beb-go/src/services/bankaccount/usecase"
+)
+
+const businessA = "0b9f6f3e-7c1a-4d7e-9d4b-3b1f1e5a2c01"
+
+// fakeDriver only begins, commits and rolls back transactions
+type fakeDriver struct{}
+
+type fakeConn struct{}
+
+var testDriverOnce sync.Once
+
+func (d fakeDriver) Open(name string) (driver.Conn, error) { return fakeConn{}, nil }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("fake driver does not run queries")
+}
+
+func (c fakeConn) Close() error { return nil }
+
+func (c fakeConn) Begin() (driver.Tx, error) { return c, nil }
+
+func (c fakeConn) Commit() error { return nil }
+
+func (c fakeConn) Rollback() error { return nil }
+
+// fakeBankAccountRepository keeps the created accounts, the bank "bca" is the only bank
+type fakeBankAccountRepository struct {
+	bankaccount.Repository
+	created []models.BankAccount
+}
+
+func (r *fakeBankAccountRepository) FindBank(c *gin.Context, bankID string) (*models.Bank, *types.Error) {
+	if bankID != "bca" {
+		return nil, &types.Error{Message: "Bank Not Found", Error: data.ErrNotFound, StatusCode: http.StatusNotFound}
+	}
+	return &models.Bank{ID: "bca", Name: "BCA"}, nil
+}
+
+func (r *fakeBankAccountRepository) Create(c *gin.Context, obj *models.BankAccount) (*models.BankAccount, *types.Error) {
+	if _, ok := data.TxFromContext(c); !ok {
+		return nil, &types.Error{Message: "no transaction", Error: errors.New("no transaction"), StatusCode: http.StatusInternalServerError}
+	}
+
+	r.created = append(r.created, *obj)
+	return obj, nil
+}
+
+type createResponse struct {
+	Result struct {
+		StatusCode int
+		Data       models.BankAccount
+	} `json:"result"`
+}
+
+func newTestBankAccountHandler(t *testing.T, repo bankaccount.Repository) *BankAccountHandler {
+	testDriverOnce.Do(func() {
+		sql.Register("fakebankaccount", fakeDriver{})
+	})
+
+	db, err := sqlx.Open("fakebankaccount", "")
+	if err != nil {
+		t.Fatalf("open fake database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return &BankAccountHandler{BankAccountUsecase: usecase.NewBankAccountUsecase(db, repo), dataManager: data.NewManager(db)}
+}
+
+// sendCreate posts the account as a user working on the business, with access to the business ids only
+func sendCreate(h *BankAccountHandler, businessID string, businessIDs []string, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/banks/:id/accounts", func(c *gin.Context) {
+		c.Set("UserID", "user-1")
+		c.Set("BusinessID", businessID)
+		c.Set("BusinessIDs", businessIDs)
+		c.Set("OutletIDs", []string{})
+		c.Set("IsSuperAdmin", false)
+	}, h.Create)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/banks/bca/accounts", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestCreateAsScopedUser(t *testing.T) {
+	repo := &fakeBankAccountRepository{}
+	h := newTestBankAccountHandler(t, repo)
+
+	w := sendCreate(h, businessA, []string{businessA}, `{"AccountNumber":"123-456-7890","HolderName":"Budi"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	if len(repo.created) != 1 {
+		t.Fatalf("created %d accounts, want 1", len(repo.created))
+	}
+	if repo.created[0].BusinessID != businessA {
+		t.Errorf("BusinessID = %q, want the current business %q", repo.created[0].BusinessID, businessA)
+	}
+
+	var res createResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("decode response %q: %v", w.Body.String(), err)
+	}
+	if res.Result.Data.BusinessID != businessA || res.Result.Data.AccountNumber != "1234567890" {
+		t.Errorf("created account = %+v, want business %q and account number 1234567890", res.Result.Data, businessA)
+	}
+}
+
+func TestCreateWithoutBusiness(t *testing.T) {
+	repo := &fakeBankAccountRepository{}
+	h := newTestBankAccountHandler(t, repo)
+
+	w := sendCreate(h, "", []string{}, `{"AccountNumber":"1234567890","HolderName":"Budi"}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusUnprocessableEntity, w.Body.String())
+	}
+	if len(repo.created) != 0 {
+		t.Errorf("created %v, want no account without business", repo.created)
+	}
+}
@@ -0,0 +1,240 @@
+package business
+
+import (
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+
+	"luxe-beb-go/library/helpers"
+	"luxe-beb-go/middleware"
+	"luxe-beb-go/models"
+	"luxe-beb-go/src/services/business"
+	"luxe-beb-go/src/services/business/repository"
+	"luxe-beb-go/src/services/business/usecase"
+
+	"github.com/gin-gonic/gin"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/http/request"
+	"luxe-beb-go/library/http/response"
+	"luxe-beb-go/library/notif"
+	"luxe-beb-go/library/types"
+)
+
+var (
+	strToDateFormat      = "2006-01-02"
+	strToTimestampFormat = "2006-01-02 15:04:05"
+)
+
+type BusinessHandler struct {
+	BusinessUsecase business.Usecase
+	dataManager     *data.Manager
+	Result          gin.H
+	Status          int
+	notifier        *notif.SlackNotifier
+}
+
+func (h BusinessHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, slackNotifier *notif.SlackNotifier, router *gin.Engine, v *gin.RouterGroup) {
+	businessRepo := repository.NewBusinessRepository(
+		data.NewMySQLStorage(db, "businesses", models.Business{}, data.MysqlConfig{BusinessColumn: "id"}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	uBusiness := usecase.NewBusinessUsecase(db, &businessRepo)
+
+	base := &BusinessHandler{BusinessUsecase: uBusiness, dataManager: dataManager, notifier: slackNotifier}
+
+	rs := v.Group("/businesses")
+	{
+		rs.GET("", middleware.Auth, base.FindAll)
+		rs.GET("/:id", middleware.Auth, base.Find)
+		rs.POST("", middleware.Auth, base.Create)
+		rs.PUT("/:id", middleware.Auth, base.Update)
+		rs.PUT("/status", middleware.Auth, base.UpdateStatus)
+	}
+
+	status := v.Group("/statuses")
+	{
+		status.GET("/businesses", middleware.AuthCheckIP, base.FindStatus)
+	}
+}
+
+func (h *BusinessHandler) FindAll(c *gin.Context) {
+	var params models.FindAllBusinessParams
+	page, size := helpers.FilterFindAll(c)
+	filterFindAllParams := helpers.FilterFindAllParam(c)
+	params.FindAllParams = filterFindAllParams
+	datas, err := h.BusinessUsecase.FindAll(c, params)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	params.FindAllParams.Page = -1
+	params.FindAllParams.Size = -1
+	length, err := h.BusinessUsecase.Count(c, params)
+	if err != nil {
+		err.Path = ".BusinessHandler->FindAll()" + err.Path
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	dataresponse := types.ResultAll{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Business Berhasil Ditampilkan", TotalData: length, Page: page, Size: size, Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(h.Status, h.Result)
+}
+
+func (h *BusinessHandler) Find(c *gin.Context) {
+	id := c.Param("id")
+
+	result, err := h.BusinessUsecase.Find(c, id)
+	if err != nil {
+		err.Path = ".BusinessHandler->Find()" + err.Path
+		if err.Error == data.ErrNotFound {
+			response.Error(c, h.notifier, "Business not found", http.StatusUnprocessableEntity, *err)
+			return
+		}
+		response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Business Berhasil Ditampilkan", Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *BusinessHandler) Create(c *gin.Context) {
+	var err *types.Error
+	var req models.BusinessRequest
+	var data *models.Business
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".BusinessHandler->Create()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	obj := models.Business{Name: req.Name}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.BusinessUsecase.Create(c, obj)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".BusinessHandler->Create()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Business Berhasil Ditambahkan", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *BusinessHandler) Update(c *gin.Context) {
+	var err *types.Error
+	var req models.BusinessRequest
+	var data *models.Business
+
+	id := c.Param("id")
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".BusinessHandler->Update()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	obj := models.Business{Name: req.Name}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.BusinessUsecase.Update(c, id, obj)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".BusinessHandler->Update()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Business Berhasil Diperbarui", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *BusinessHandler) FindStatus(c *gin.Context) {
+	datas, err := h.BusinessUsecase.FindStatus(c)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Business Status Berhasil Ditampilkan", Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *BusinessHandler) UpdateStatus(c *gin.Context) {
+	var err *types.Error
+	var req models.UpdateStatusRequest
+	var data *models.Business
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".BusinessHandler->UpdateStatus()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		for _, id := range req.ID {
+			data, err = h.BusinessUsecase.UpdateStatus(c, id, req.NewStatusID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".BusinessHandler->UpdateStatus()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Status Business Berhasil Diperbarui", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
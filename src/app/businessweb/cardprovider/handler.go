@@ -0,0 +1,247 @@
+package cardprovider
+
+import (
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+
+	"luxe-beb-go/library/helpers"
+	"luxe-beb-go/middleware"
+	"luxe-beb-go/models"
+	"luxe-beb-go/src/services/cardprovider"
+	"luxe-beb-go/src/services/cardprovider/repository"
+	"luxe-beb-go/src/services/cardprovider/usecase"
+
+	"github.com/gin-gonic/gin"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/http/request"
+	"luxe-beb-go/library/http/response"
+	"luxe-beb-go/library/notif"
+	"luxe-beb-go/library/types"
+)
+
+var (
+	strToDateFormat      = "2006-01-02"
+	strToTimestampFormat = "2006-01-02 15:04:05"
+)
+
+type CardProviderHandler struct {
+	CardProviderUsecase cardprovider.Usecase
+	dataManager         *data.Manager
+	Result              gin.H
+	Status              int
+	notifier            *notif.SlackNotifier
+}
+
+func (h CardProviderHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, slackNotifier *notif.SlackNotifier, router *gin.Engine, v *gin.RouterGroup) {
+	cardProviderRepo := repository.NewCardProviderRepository(
+		data.NewMySQLStorage(db, "card_providers", models.CardProvider{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "card_provider_banks", models.CardProviderBank{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	uCardProvider := usecase.NewCardProviderUsecase(db, &cardProviderRepo)
+
+	base := &CardProviderHandler{CardProviderUsecase: uCardProvider, dataManager: dataManager, notifier: slackNotifier}
+
+	rs := v.Group("/card-providers")
+	{
+		rs.GET("", middleware.Auth, base.FindAll)
+		rs.GET("/:id", middleware.Auth, base.Find)
+		rs.POST("", middleware.Auth, base.Create)
+		rs.PUT("/:id", middleware.Auth, base.Update)
+		rs.PUT("/status", middleware.Auth, base.UpdateStatus)
+	}
+
+	status := v.Group("/statuses")
+	{
+		status.GET("/card-providers", middleware.AuthCheckIP, base.FindStatus)
+	}
+}
+
+func (h *CardProviderHandler) FindAll(c *gin.Context) {
+	var params models.FindAllCardProviderParams
+	page, size := helpers.FilterFindAll(c)
+	filterFindAllParams := helpers.FilterFindAllParam(c)
+	params.FindAllParams = filterFindAllParams
+	datas, err := h.CardProviderUsecase.FindAll(c, params)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	params.FindAllParams.Page = -1
+	params.FindAllParams.Size = -1
+	length, err := h.CardProviderUsecase.Count(c, params)
+	if err != nil {
+		err.Path = ".CardProviderHandler->FindAll()" + err.Path
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	dataresponse := types.ResultAll{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Card Provider Berhasil Ditampilkan", TotalData: length, Page: page, Size: size, Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(h.Status, h.Result)
+}
+
+func (h *CardProviderHandler) Find(c *gin.Context) {
+	id := c.Param("id")
+
+	result, err := h.CardProviderUsecase.Find(c, id)
+	if err != nil {
+		err.Path = ".CardProviderHandler->Find()" + err.Path
+		if err.Error == data.ErrNotFound {
+			response.Error(c, h.notifier, "Card Provider not found", http.StatusUnprocessableEntity, *err)
+			return
+		}
+		response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Card Provider Berhasil Ditampilkan", Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *CardProviderHandler) Create(c *gin.Context) {
+	var err *types.Error
+	var req models.CardProviderRequest
+	var data *models.CardProvider
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".CardProviderHandler->Create()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	obj := models.CardProvider{Name: req.Name}
+	for _, bankID := range req.BankIDs {
+		obj.Banks = append(obj.Banks, models.IDNameTemplate{ID: bankID})
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.CardProviderUsecase.Create(c, obj)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".CardProviderHandler->Create()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Card Provider Berhasil Ditambahkan", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *CardProviderHandler) Update(c *gin.Context) {
+	var err *types.Error
+	var req models.CardProviderRequest
+	var data *models.CardProvider
+
+	id := c.Param("id")
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".CardProviderHandler->Update()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	obj := models.CardProvider{Name: req.Name}
+	for _, bankID := range req.BankIDs {
+		obj.Banks = append(obj.Banks, models.IDNameTemplate{ID: bankID})
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.CardProviderUsecase.Update(c, id, obj)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".CardProviderHandler->Update()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Card Provider Berhasil Diperbarui", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *CardProviderHandler) FindStatus(c *gin.Context) {
+	datas, err := h.CardProviderUsecase.FindStatus(c)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Card Provider Status Berhasil Ditampilkan", Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *CardProviderHandler) UpdateStatus(c *gin.Context) {
+	var err *types.Error
+	var req models.UpdateStatusRequest
+	var data *models.CardProvider
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".CardProviderHandler->UpdateStatus()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		for _, id := range req.ID {
+			data, err = h.CardProviderUsecase.UpdateStatus(c, id, req.NewStatusID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".CardProviderHandler->UpdateStatus()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Status Card Provider Berhasil Diperbarui", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
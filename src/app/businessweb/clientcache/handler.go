@@ -0,0 +1,176 @@
+package clientcache
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+
+	"luxe-beb-go/library/client"
+	"luxe-beb-go/library/helpers"
+	"luxe-beb-go/middleware"
+	"luxe-beb-go/src/services/clientcache/repository"
+	urltocacherepository "luxe-beb-go/src/services/urltocache/repository"
+
+	"github.com/gin-gonic/gin"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/http/response"
+	"luxe-beb-go/library/notif"
+	"luxe-beb-go/library/types"
+)
+
+type ClientCacheHandler struct {
+	ClientCacheService client.ClientCacheServiceInterface
+	dataManager        *data.Manager
+	Result             gin.H
+	Status             int
+	notifier           *notif.SlackNotifier
+}
+
+func (h ClientCacheHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, slackNotifier *notif.SlackNotifier, router *gin.Engine, v *gin.RouterGroup) {
+	clientCacheRepo := repository.NewClientCacheRepository(
+		data.NewMySQLStorage(db, "client_caches", client.ClientCache{}, data.MysqlConfig{}),
+	)
+	urlToCacheRepo := urltocacherepository.NewURLToCacheRepository(
+		data.NewMySQLStorage(db, "urls_to_cache", client.URLToCache{}, data.MysqlConfig{}),
+	)
+
+	clientCacheService := client.NewClientCacheService(&clientCacheRepo, client.NewURLToCacheService(&urlToCacheRepo))
+	client.RegisterClientCacheService(clientCacheService)
+
+	base := &ClientCacheHandler{ClientCacheService: clientCacheService, dataManager: dataManager, notifier: slackNotifier}
+
+	rs := v.Group("/client-caches")
+	{
+		rs.GET("", middleware.Auth, base.FindAll)
+		rs.GET("/:id", middleware.Auth, base.Find)
+		rs.DELETE("", middleware.Auth, base.Purge)
+		rs.DELETE("/:id", middleware.Auth, base.Delete)
+	}
+}
+
+func (h *ClientCacheHandler) FindAll(c *gin.Context) {
+	page, size := helpers.FilterFindAll(c)
+	filterFindAllParams := helpers.FilterFindAllParam(c)
+	params := client.FindAllClientCachesParams{
+		Search:     c.Query("Search"),
+		Page:       filterFindAllParams.Page,
+		Limit:      filterFindAllParams.Size,
+		Method:     c.Query("Method"),
+		ClientName: c.Query("ClientName"),
+		URL:        c.Query("URL"),
+	}
+	datas, err := h.ClientCacheService.ListClientCaches(c, &params)
+	if err != nil {
+		err.Path = ".ClientCacheHandler->FindAll()" + err.Path
+		response.Error(c, h.notifier, err.Message, http.StatusInternalServerError, *err)
+		return
+	}
+
+	length, err := h.ClientCacheService.CountClientCache(c, &params)
+	if err != nil {
+		err.Path = ".ClientCacheHandler->FindAll()" + err.Path
+		response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.ResultAll{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Cache Client Berhasil Ditampilkan", TotalData: length, Page: page, Size: size, Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(h.Status, h.Result)
+}
+
+func (h *ClientCacheHandler) Find(c *gin.Context) {
+	id, ok := h.paramID(c, ".ClientCacheHandler->Find()")
+	if !ok {
+		return
+	}
+
+	result, err := h.ClientCacheService.GetClientCache(c, id)
+	if err != nil {
+		err.Path = ".ClientCacheHandler->Find()" + err.Path
+		response.Error(c, h.notifier, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Cache Client Berhasil Ditampilkan", Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *ClientCacheHandler) Delete(c *gin.Context) {
+	id, ok := h.paramID(c, ".ClientCacheHandler->Delete()")
+	if !ok {
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		return h.ClientCacheService.DeleteClientCache(tctx, id)
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".ClientCacheHandler->Delete()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Cache Client Berhasil Dihapus"}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+// Purge deletes the caches matching Method, ClientName & URL (a prefix), every cache when none is given
+func (h *ClientCacheHandler) Purge(c *gin.Context) {
+	var err *types.Error
+	var count int
+
+	params := client.FindAllClientCachesParams{
+		Method:     c.Query("Method"),
+		ClientName: c.Query("ClientName"),
+		URL:        c.Query("URL"),
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		count, err = h.ClientCacheService.PurgeClientCaches(tctx, &params)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".ClientCacheHandler->Purge()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Cache Client Berhasil Dihapus", Data: gin.H{"Deleted": count}}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *ClientCacheHandler) paramID(c *gin.Context, path string) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, h.notifier, "Data Cache Client tidak ditemukan", http.StatusNotFound, types.Error{
+			Path:       path,
+			Message:    err.Error(),
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "validation-error",
+		})
+		return 0, false
+	}
+	return id, true
+}
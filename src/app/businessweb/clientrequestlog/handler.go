@@ -0,0 +1,110 @@
+package clientrequestlog
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+
+	"luxe-beb-go/library/client"
+	"luxe-beb-go/library/helpers"
+	"luxe-beb-go/middleware"
+	"luxe-beb-go/src/services/clientrequestlog"
+	"luxe-beb-go/src/services/clientrequestlog/repository"
+	"luxe-beb-go/src/services/clientrequestlog/usecase"
+
+	"github.com/gin-gonic/gin"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/http/response"
+	"luxe-beb-go/library/notif"
+	"luxe-beb-go/library/types"
+)
+
+type ClientRequestLogHandler struct {
+	ClientRequestLogUsecase clientrequestlog.Usecase
+	dataManager             *data.Manager
+	Result                  gin.H
+	Status                  int
+	notifier                *notif.SlackNotifier
+}
+
+func (h ClientRequestLogHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, slackNotifier *notif.SlackNotifier, router *gin.Engine, v *gin.RouterGroup) {
+	clientRequestLogRepo := repository.NewClientRequestLogRepository(
+		data.NewMySQLStorage(db, "client_request_logs", client.ClientRequestLog{}, data.MysqlConfig{BusinessColumn: "businessId"}),
+	)
+	uClientRequestLog := usecase.NewClientRequestLogUsecase(db, &clientRequestLogRepo)
+
+	base := &ClientRequestLogHandler{ClientRequestLogUsecase: uClientRequestLog, dataManager: dataManager, notifier: slackNotifier}
+
+	rs := v.Group("/client-request-logs")
+	{
+		rs.GET("", middleware.Auth, base.FindAll)
+		rs.GET("/:id", middleware.Auth, base.Find)
+	}
+}
+
+func (h *ClientRequestLogHandler) FindAll(c *gin.Context) {
+	page, size := helpers.FilterFindAll(c)
+	filterFindAllParams := helpers.FilterFindAllParam(c)
+	params := client.FindAllClientRequestLogs{
+		Search:        c.Query("Search"),
+		Page:          filterFindAllParams.Page,
+		Limit:         filterFindAllParams.Size,
+		ClientName:    c.Query("ClientName"),
+		Status:        c.Query("Status"),
+		CorrelationID: c.Query("CorrelationID"),
+	}
+	params.IsUnacknowledged = c.Query("Unacknowledged") == "true" || c.Query("Unacknowledged") == "1"
+	datas, err := h.ClientRequestLogUsecase.FindAll(c, params)
+	if err != nil {
+		err.Path = ".ClientRequestLogHandler->FindAll()" + err.Path
+		response.Error(c, h.notifier, err.Message, http.StatusInternalServerError, *err)
+		return
+	}
+
+	length, err := h.ClientRequestLogUsecase.Count(c, params)
+	if err != nil {
+		err.Path = ".ClientRequestLogHandler->FindAll()" + err.Path
+		response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.ResultAll{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Log Request Client Berhasil Ditampilkan", TotalData: length, Page: page, Size: size, Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(h.Status, h.Result)
+}
+
+func (h *ClientRequestLogHandler) Find(c *gin.Context) {
+	id, errConv := strconv.Atoi(c.Param("id"))
+	if errConv != nil {
+		response.Error(c, h.notifier, "Log request client tidak ditemukan", http.StatusNotFound, types.Error{
+			Path:       ".ClientRequestLogHandler->Find()",
+			Message:    errConv.Error(),
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "validation-error",
+		})
+		return
+	}
+
+	result, err := h.ClientRequestLogUsecase.Find(c, id)
+	if err != nil {
+		err.Path = ".ClientRequestLogHandler->Find()" + err.Path
+		if err.Error == data.ErrNotFound {
+			response.Error(c, h.notifier, "Log request client tidak ditemukan", http.StatusNotFound, *err)
+			return
+		}
+		response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Log Request Client Berhasil Ditampilkan", Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
@@ -0,0 +1,218 @@
+package codesequence
+
+import (
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+
+	"luxe-beb-go/library/helpers"
+	"luxe-beb-go/middleware"
+	"luxe-beb-go/models"
+	"luxe-beb-go/src/services/codesequence"
+	"luxe-beb-go/src/services/codesequence/repository"
+	"luxe-beb-go/src/services/codesequence/usecase"
+
+	"github.com/gin-gonic/gin"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/http/request"
+	"luxe-beb-go/library/http/response"
+	"luxe-beb-go/library/notif"
+	"luxe-beb-go/library/types"
+)
+
+type CodeSequenceHandler struct {
+	CodeSequenceUsecase codesequence.Usecase
+	dataManager         *data.Manager
+	Result              gin.H
+	Status              int
+	notifier            *notif.SlackNotifier
+}
+
+func (h CodeSequenceHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, slackNotifier *notif.SlackNotifier, router *gin.Engine, v *gin.RouterGroup) {
+	codeSequenceRepo := repository.NewCodeSequenceRepository(
+		data.NewMySQLStorage(db, "code_sequences", models.CodeSequence{}, data.MysqlConfig{BusinessColumn: "business_id", SharedBusiness: true}),
+	)
+
+	uCodeSequence := usecase.NewCodeSequenceUsecase(db, &codeSequenceRepo)
+
+	base := &CodeSequenceHandler{CodeSequenceUsecase: uCodeSequence, dataManager: dataManager, notifier: slackNotifier}
+
+	rs := v.Group("/code-sequences")
+	{
+		rs.GET("", middleware.Auth, base.FindAll)
+		rs.GET("/:id", middleware.Auth, base.Find)
+		rs.POST("", middleware.Auth, base.Create)
+		rs.POST("/next", middleware.Auth, base.Next)
+		rs.PUT("/:id", middleware.Auth, base.Update)
+	}
+}
+
+func (h *CodeSequenceHandler) FindAll(c *gin.Context) {
+	var params models.FindAllCodeSequenceParams
+	page, size := helpers.FilterFindAll(c)
+	filterFindAllParams := helpers.FilterFindAllParam(c)
+	params.FindAllParams = filterFindAllParams
+	params.Prefix = c.Query("Prefix")
+	params.Year = c.Query("Year")
+	datas, err := h.CodeSequenceUsecase.FindAll(c, params)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	params.FindAllParams.Page = -1
+	params.FindAllParams.Size = -1
+	length, err := h.CodeSequenceUsecase.Count(c, params)
+	if err != nil {
+		err.Path = ".CodeSequenceHandler->FindAll()" + err.Path
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	dataresponse := types.ResultAll{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Code Sequence Berhasil Ditampilkan", TotalData: length, Page: page, Size: size, Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(h.Status, h.Result)
+}
+
+func (h *CodeSequenceHandler) Find(c *gin.Context) {
+	id := c.Param("id")
+
+	result, err := h.CodeSequenceUsecase.Find(c, id)
+	if err != nil {
+		err.Path = ".CodeSequenceHandler->Find()" + err.Path
+		if err.Error == data.ErrNotFound {
+			response.Error(c, h.notifier, "Code Sequence not found", http.StatusUnprocessableEntity, *err)
+			return
+		}
+		response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Code Sequence Berhasil Ditampilkan", Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *CodeSequenceHandler) Create(c *gin.Context) {
+	var err *types.Error
+	var req models.CodeSequenceRequest
+	var data *models.CodeSequence
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".CodeSequenceHandler->Create()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	obj := models.CodeSequence{
+		Prefix:      req.Prefix,
+		Name:        req.Name,
+		Format:      req.Format,
+		ResetYearly: req.ResetYearly == nil || *req.ResetYearly,
+		IsShared:    req.IsShared,
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.CodeSequenceUsecase.Create(tctx, obj)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".CodeSequenceHandler->Create()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Code Sequence Berhasil Ditambahkan", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *CodeSequenceHandler) Update(c *gin.Context) {
+	var err *types.Error
+	var req models.CodeSequenceUpdateRequest
+	var data *models.CodeSequence
+
+	id := c.Param("id")
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".CodeSequenceHandler->Update()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.CodeSequenceUsecase.Update(tctx, id, req)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".CodeSequenceHandler->Update()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Code Sequence Berhasil Diperbarui", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+// Next reserves the next code of the prefix, the number is given back when the reservation fails
+func (h *CodeSequenceHandler) Next(c *gin.Context) {
+	var err *types.Error
+	var req models.CodeSequenceNextRequest
+	var data *models.CodeSequenceCode
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".CodeSequenceHandler->Next()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.CodeSequenceUsecase.Next(tctx, req.Prefix)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".CodeSequenceHandler->Next()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Code Berhasil Dibuat", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
@@ -0,0 +1,134 @@
+package emailoutbox
+
+import (
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+
+	"luxe-beb-go/library/helpers"
+	"luxe-beb-go/library/mail"
+	"luxe-beb-go/middleware"
+	"luxe-beb-go/models"
+	"luxe-beb-go/src/services/emailoutbox"
+	"luxe-beb-go/src/services/emailoutbox/repository"
+	"luxe-beb-go/src/services/emailoutbox/usecase"
+
+	"github.com/gin-gonic/gin"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/http/response"
+	"luxe-beb-go/library/notif"
+	"luxe-beb-go/library/types"
+)
+
+type EmailOutboxHandler struct {
+	EmailOutboxUsecase emailoutbox.Usecase
+	dataManager        *data.Manager
+	Result             gin.H
+	Status             int
+	notifier           *notif.SlackNotifier
+}
+
+func (h EmailOutboxHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, slackNotifier *notif.SlackNotifier, router *gin.Engine, v *gin.RouterGroup) {
+	emailOutboxRepo := repository.NewEmailOutboxRepository(
+		data.NewMySQLStorage(db, "email_outbox", models.EmailOutbox{}, data.MysqlConfig{BusinessColumn: "business_id"}),
+		mail.NewOutbox(db, 0),
+	)
+
+	uEmailOutbox := usecase.NewEmailOutboxUsecase(db, &emailOutboxRepo)
+
+	base := &EmailOutboxHandler{EmailOutboxUsecase: uEmailOutbox, dataManager: dataManager, notifier: slackNotifier}
+
+	rs := v.Group("/email-outbox")
+	{
+		rs.GET("", middleware.Auth, base.FindAll)
+		rs.GET("/:id", middleware.Auth, base.Find)
+		rs.POST("/:id/resend", middleware.Auth, base.Resend)
+	}
+}
+
+func (h *EmailOutboxHandler) FindAll(c *gin.Context) {
+	var params models.FindAllEmailOutboxParams
+	page, size := helpers.FilterFindAll(c)
+	filterFindAllParams := helpers.FilterFindAllParam(c)
+	if c.Query("SortName") == "" {
+		filterFindAllParams.SortBy = "email_outbox.created_at DESC"
+	}
+	params.FindAllParams = filterFindAllParams
+	params.Status = c.Query("Status")
+	params.Email = c.Query("Email")
+	datas, err := h.EmailOutboxUsecase.FindAll(c, params)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	params.FindAllParams.Page = -1
+	params.FindAllParams.Size = -1
+	length, err := h.EmailOutboxUsecase.Count(c, params)
+	if err != nil {
+		err.Path = ".EmailOutboxHandler->FindAll()" + err.Path
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	dataresponse := types.ResultAll{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Email Berhasil Ditampilkan", TotalData: length, Page: page, Size: size, Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(h.Status, h.Result)
+}
+
+func (h *EmailOutboxHandler) Find(c *gin.Context) {
+	id := c.Param("id")
+
+	result, err := h.EmailOutboxUsecase.Find(c, id)
+	if err != nil {
+		err.Path = ".EmailOutboxHandler->Find()" + err.Path
+		if err.Error == data.ErrNotFound {
+			response.Error(c, h.notifier, "Email not found", http.StatusUnprocessableEntity, *err)
+			return
+		}
+		response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Email Berhasil Ditampilkan", Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *EmailOutboxHandler) Resend(c *gin.Context) {
+	var err *types.Error
+	var data *models.EmailOutbox
+
+	id := c.Param("id")
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.EmailOutboxUsecase.Resend(tctx, id)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".EmailOutboxHandler->Resend()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Email Berhasil Dijadwalkan Ulang", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
@@ -0,0 +1,182 @@
+package notification
+
+import (
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+
+	"luxe-beb-go/library/helpers"
+	"luxe-beb-go/middleware"
+	"luxe-beb-go/models"
+	"luxe-beb-go/src/services/notification"
+	"luxe-beb-go/src/services/notification/repository"
+	"luxe-beb-go/src/services/notification/usecase"
+
+	"github.com/gin-gonic/gin"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/http/request"
+	"luxe-beb-go/library/http/response"
+	"luxe-beb-go/library/notif"
+	"luxe-beb-go/library/types"
+)
+
+type NotificationHandler struct {
+	NotificationUsecase notification.Usecase
+	dataManager         *data.Manager
+	Result              gin.H
+	Status              int
+	notifier            *notif.SlackNotifier
+}
+
+func (h NotificationHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, slackNotifier *notif.SlackNotifier, router *gin.Engine, v *gin.RouterGroup) {
+	notificationRepo := repository.NewNotificationRepository(
+		data.NewMySQLStorage(db, "notifications", models.Notification{}, data.MysqlConfig{BusinessColumn: "business_id"}),
+		data.NewMySQLStorage(db, "notification_preferences", models.NotificationPreference{}, data.MysqlConfig{}),
+	)
+
+	uNotification := usecase.NewNotificationUsecase(db, &notificationRepo)
+
+	base := &NotificationHandler{NotificationUsecase: uNotification, dataManager: dataManager, notifier: slackNotifier}
+
+	rs := v.Group("/notifications")
+	{
+		rs.GET("", middleware.Auth, base.FindAll)
+		rs.PUT("/read", middleware.Auth, base.MarkAllRead)
+		rs.PUT("/:id/read", middleware.Auth, base.MarkRead)
+		rs.GET("/preferences", middleware.Auth, base.FindPreferences)
+		rs.PUT("/preferences", middleware.Auth, base.UpdatePreferences)
+	}
+}
+
+func (h *NotificationHandler) FindAll(c *gin.Context) {
+	var params models.FindAllNotificationParams
+	page, size := helpers.FilterFindAll(c)
+	filterFindAllParams := helpers.FilterFindAllParam(c)
+	if c.Query("SortName") == "" {
+		filterFindAllParams.SortBy = "notifications.created_at DESC"
+	}
+	params.FindAllParams = filterFindAllParams
+	params.Unread = c.Query("Unread") == "true" || c.Query("Unread") == "1"
+	datas, err := h.NotificationUsecase.FindAll(c, params)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	params.FindAllParams.Page = -1
+	params.FindAllParams.Size = -1
+	length, err := h.NotificationUsecase.Count(c, params)
+	if err != nil {
+		err.Path = ".NotificationHandler->FindAll()" + err.Path
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	dataresponse := types.ResultAll{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Notifikasi Berhasil Ditampilkan", TotalData: length, Page: page, Size: size, Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(h.Status, h.Result)
+}
+
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	var err *types.Error
+	var data *models.Notification
+
+	id := c.Param("id")
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.NotificationUsecase.MarkRead(tctx, id)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".NotificationHandler->MarkRead()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Notifikasi Berhasil Ditandai Dibaca", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		return h.NotificationUsecase.MarkAllRead(tctx)
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".NotificationHandler->MarkAllRead()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Semua Notifikasi Berhasil Ditandai Dibaca"}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *NotificationHandler) FindPreferences(c *gin.Context) {
+	datas, err := h.NotificationUsecase.FindPreferences(c)
+	if err != nil {
+		err.Path = ".NotificationHandler->FindPreferences()" + err.Path
+		response.Error(c, h.notifier, err.Message, http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Preferensi Notifikasi Berhasil Ditampilkan", Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	var err *types.Error
+	var req models.NotificationPreferencesRequest
+	var datas []*models.NotificationPreference
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".NotificationHandler->UpdatePreferences()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		datas, err = h.NotificationUsecase.UpdatePreferences(tctx, req)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".NotificationHandler->UpdatePreferences()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Preferensi Notifikasi Berhasil Diperbarui", Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
@@ -0,0 +1,233 @@
+package notificationrule
+
+import (
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+
+	"luxe-beb-go/library/helpers"
+	"luxe-beb-go/middleware"
+	"luxe-beb-go/models"
+	"luxe-beb-go/src/services/notificationrule"
+	"luxe-beb-go/src/services/notificationrule/repository"
+	"luxe-beb-go/src/services/notificationrule/usecase"
+
+	"github.com/gin-gonic/gin"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/http/request"
+	"luxe-beb-go/library/http/response"
+	"luxe-beb-go/library/notif"
+	"luxe-beb-go/library/types"
+)
+
+type NotificationRuleHandler struct {
+	NotificationRuleUsecase notificationrule.Usecase
+	dataManager             *data.Manager
+	Result                  gin.H
+	Status                  int
+	notifier                *notif.SlackNotifier
+}
+
+func (h NotificationRuleHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, slackNotifier *notif.SlackNotifier, router *gin.Engine, v *gin.RouterGroup) {
+	notificationRuleRepo := repository.NewNotificationRuleRepository(
+		data.NewMySQLStorage(db, "notification_rules", models.NotificationRule{}, data.MysqlConfig{BusinessColumn: "business_id"}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	uNotificationRule := usecase.NewNotificationRuleUsecase(db, &notificationRuleRepo)
+
+	base := &NotificationRuleHandler{NotificationRuleUsecase: uNotificationRule, dataManager: dataManager, notifier: slackNotifier}
+
+	rs := v.Group("/notification-rules")
+	{
+		rs.GET("", middleware.Auth, base.FindAll)
+		rs.GET("/:id", middleware.Auth, base.Find)
+		rs.POST("", middleware.Auth, base.Create)
+		rs.PUT("/:id", middleware.Auth, base.Update)
+		rs.PUT("/status", middleware.Auth, base.UpdateStatus)
+	}
+
+	status := v.Group("/statuses")
+	{
+		status.GET("/notification-rules", middleware.AuthCheckIP, base.FindStatus)
+	}
+}
+
+func (h *NotificationRuleHandler) FindAll(c *gin.Context) {
+	var params models.FindAllNotificationRuleParams
+	page, size := helpers.FilterFindAll(c)
+	filterFindAllParams := helpers.FilterFindAllParam(c)
+	params.FindAllParams = filterFindAllParams
+	params.Event = c.Query("Event")
+	params.Channel = c.Query("Channel")
+	datas, err := h.NotificationRuleUsecase.FindAll(c, params)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	params.FindAllParams.Page = -1
+	params.FindAllParams.Size = -1
+	length, err := h.NotificationRuleUsecase.Count(c, params)
+	if err != nil {
+		err.Path = ".NotificationRuleHandler->FindAll()" + err.Path
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	dataresponse := types.ResultAll{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Aturan Notifikasi Berhasil Ditampilkan", TotalData: length, Page: page, Size: size, Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(h.Status, h.Result)
+}
+
+func (h *NotificationRuleHandler) Find(c *gin.Context) {
+	id := c.Param("id")
+
+	result, err := h.NotificationRuleUsecase.Find(c, id)
+	if err != nil {
+		err.Path = ".NotificationRuleHandler->Find()" + err.Path
+		if err.Error == data.ErrNotFound {
+			response.Error(c, h.notifier, "Notification rule not found", http.StatusUnprocessableEntity, *err)
+			return
+		}
+		response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Aturan Notifikasi Berhasil Ditampilkan", Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *NotificationRuleHandler) Create(c *gin.Context) {
+	var err *types.Error
+	var req models.NotificationRuleRequest
+	var data *models.NotificationRule
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".NotificationRuleHandler->Create()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.NotificationRuleUsecase.Create(tctx, req)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".NotificationRuleHandler->Create()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Aturan Notifikasi Berhasil Ditambahkan", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *NotificationRuleHandler) Update(c *gin.Context) {
+	var err *types.Error
+	var req models.NotificationRuleRequest
+	var data *models.NotificationRule
+
+	id := c.Param("id")
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".NotificationRuleHandler->Update()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.NotificationRuleUsecase.Update(tctx, id, req)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".NotificationRuleHandler->Update()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Aturan Notifikasi Berhasil Diperbarui", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *NotificationRuleHandler) FindStatus(c *gin.Context) {
+	datas, err := h.NotificationRuleUsecase.FindStatus(c)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Status Aturan Notifikasi Berhasil Ditampilkan", Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *NotificationRuleHandler) UpdateStatus(c *gin.Context) {
+	var err *types.Error
+	var req models.UpdateStatusRequest
+	var data *models.NotificationRule
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".NotificationRuleHandler->UpdateStatus()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		for _, id := range req.ID {
+			data, err = h.NotificationRuleUsecase.UpdateStatus(tctx, id, req.NewStatusID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".NotificationRuleHandler->UpdateStatus()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Status Aturan Notifikasi Berhasil Diperbarui", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
@@ -0,0 +1,248 @@
+package outlet
+
+import (
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+
+	"luxe-beb-go/library/helpers"
+	"luxe-beb-go/middleware"
+	"luxe-beb-go/models"
+	"luxe-beb-go/src/services/outlet"
+	"luxe-beb-go/src/services/outlet/repository"
+	"luxe-beb-go/src/services/outlet/usecase"
+
+	"github.com/gin-gonic/gin"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/http/request"
+	"luxe-beb-go/library/http/response"
+	"luxe-beb-go/library/notif"
+	"luxe-beb-go/library/types"
+)
+
+var (
+	strToDateFormat      = "2006-01-02"
+	strToTimestampFormat = "2006-01-02 15:04:05"
+)
+
+type OutletHandler struct {
+	OutletUsecase outlet.Usecase
+	dataManager   *data.Manager
+	Result        gin.H
+	Status        int
+	notifier      *notif.SlackNotifier
+}
+
+func (h OutletHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, slackNotifier *notif.SlackNotifier, router *gin.Engine, v *gin.RouterGroup) {
+	outletRepo := repository.NewOutletRepository(
+		data.NewMySQLStorage(db, "outlets", models.Outlet{}, data.MysqlConfig{BusinessColumn: "business_id", OutletColumn: "id"}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	uOutlet := usecase.NewOutletUsecase(db, &outletRepo)
+
+	base := &OutletHandler{OutletUsecase: uOutlet, dataManager: dataManager, notifier: slackNotifier}
+
+	rs := v.Group("/outlets")
+	{
+		rs.GET("", middleware.Auth, base.FindAll)
+		rs.GET("/:id", middleware.Auth, base.Find)
+		rs.POST("", middleware.Auth, base.Create)
+		rs.PUT("/:id", middleware.Auth, base.Update)
+		rs.PUT("/status", middleware.Auth, base.UpdateStatus)
+	}
+
+	status := v.Group("/statuses")
+	{
+		status.GET("/outlets", middleware.AuthCheckIP, base.FindStatus)
+	}
+}
+
+func (h *OutletHandler) FindAll(c *gin.Context) {
+	var params models.FindAllOutletParams
+	page, size := helpers.FilterFindAll(c)
+	filterFindAllParams := helpers.FilterFindAllParam(c)
+	params.FindAllParams = filterFindAllParams
+	datas, err := h.OutletUsecase.FindAll(c, params)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	params.FindAllParams.Page = -1
+	params.FindAllParams.Size = -1
+	length, err := h.OutletUsecase.Count(c, params)
+	if err != nil {
+		err.Path = ".OutletHandler->FindAll()" + err.Path
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	dataresponse := types.ResultAll{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Outlet Berhasil Ditampilkan", TotalData: length, Page: page, Size: size, Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(h.Status, h.Result)
+}
+
+func (h *OutletHandler) Find(c *gin.Context) {
+	id := c.Param("id")
+
+	result, err := h.OutletUsecase.Find(c, id)
+	if err != nil {
+		err.Path = ".OutletHandler->Find()" + err.Path
+		if err.Error == data.ErrNotFound {
+			response.Error(c, h.notifier, "Outlet not found", http.StatusUnprocessableEntity, *err)
+			return
+		}
+		response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Outlet Berhasil Ditampilkan", Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *OutletHandler) Create(c *gin.Context) {
+	var err *types.Error
+	var req models.OutletRequest
+	var data *models.Outlet
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".OutletHandler->Create()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	obj := models.Outlet{
+		BusinessID: req.BusinessID,
+		Name:       req.Name,
+		Address:    req.Address,
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.OutletUsecase.Create(c, obj)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".OutletHandler->Create()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Outlet Berhasil Ditambahkan", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *OutletHandler) Update(c *gin.Context) {
+	var err *types.Error
+	var req models.OutletRequest
+	var data *models.Outlet
+
+	id := c.Param("id")
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".OutletHandler->Update()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	obj := models.Outlet{
+		BusinessID: req.BusinessID,
+		Name:       req.Name,
+		Address:    req.Address,
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.OutletUsecase.Update(c, id, obj)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".OutletHandler->Update()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Outlet Berhasil Diperbarui", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *OutletHandler) FindStatus(c *gin.Context) {
+	datas, err := h.OutletUsecase.FindStatus(c)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data Outlet Status Berhasil Ditampilkan", Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *OutletHandler) UpdateStatus(c *gin.Context) {
+	var err *types.Error
+	var req models.UpdateStatusRequest
+	var data *models.Outlet
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".OutletHandler->UpdateStatus()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		for _, id := range req.ID {
+			data, err = h.OutletUsecase.UpdateStatus(c, id, req.NewStatusID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".OutletHandler->UpdateStatus()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Status Outlet Berhasil Diperbarui", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
@@ -0,0 +1,254 @@
+package reference
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+
+	"luxe-beb-go/library/helpers"
+	"luxe-beb-go/middleware"
+	"luxe-beb-go/models"
+	"luxe-beb-go/src/services/reference"
+	"luxe-beb-go/src/services/reference/repository"
+	"luxe-beb-go/src/services/reference/usecase"
+
+	"github.com/gin-gonic/gin"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/http/request"
+	"luxe-beb-go/library/http/response"
+	"luxe-beb-go/library/notif"
+	"luxe-beb-go/library/types"
+)
+
+var (
+	strToDateFormat      = "2006-01-02"
+	strToTimestampFormat = "2006-01-02 15:04:05"
+)
+
+// Definition describes a simple id/name/status lookup table exposed by the reference handler
+type Definition struct {
+	// Table is the database table, e.g. payment_type
+	Table string
+	// Path is the route of the resource, e.g. /payment-types
+	Path string
+	// Name is the display name used in the response messages, e.g. Payment Type
+	Name string
+}
+
+type ReferenceHandler struct {
+	ReferenceUsecase reference.Usecase
+	definition       Definition
+	dataManager      *data.Manager
+	Result           gin.H
+	Status           int
+	notifier         *notif.SlackNotifier
+}
+
+// RegisterAPI registers the FindAll/Find/Create/Update/UpdateStatus/FindStatus endpoints of the lookup table
+func (h ReferenceHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, slackNotifier *notif.SlackNotifier, router *gin.Engine, v *gin.RouterGroup, def Definition) {
+	referenceRepo := repository.NewReferenceRepository(
+		def.Table,
+		data.NewMySQLStorage(db, def.Table, models.Reference{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	uReference := usecase.NewReferenceUsecase(db, &referenceRepo)
+
+	base := &ReferenceHandler{ReferenceUsecase: uReference, definition: def, dataManager: dataManager, notifier: slackNotifier}
+
+	rs := v.Group(def.Path)
+	{
+		rs.GET("", middleware.Auth, base.FindAll)
+		rs.GET("/:id", middleware.Auth, base.Find)
+		rs.POST("", middleware.Auth, base.Create)
+		rs.PUT("/:id", middleware.Auth, base.Update)
+		rs.PUT("/status", middleware.Auth, base.UpdateStatus)
+	}
+
+	status := v.Group("/statuses")
+	{
+		status.GET(def.Path, middleware.AuthCheckIP, base.FindStatus)
+	}
+}
+
+func (h *ReferenceHandler) FindAll(c *gin.Context) {
+	var params models.FindAllReferenceParams
+	page, size := helpers.FilterFindAll(c)
+	filterFindAllParams := helpers.FilterFindAllParam(c)
+	params.FindAllParams = filterFindAllParams
+	datas, err := h.ReferenceUsecase.FindAll(c, params)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	params.FindAllParams.Page = -1
+	params.FindAllParams.Size = -1
+	length, err := h.ReferenceUsecase.Count(c, params)
+	if err != nil {
+		err.Path = ".ReferenceHandler->FindAll()" + err.Path
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	dataresponse := types.ResultAll{Status: "Sukses", StatusCode: http.StatusOK, Message: fmt.Sprintf("Data %s Berhasil Ditampilkan", h.definition.Name), TotalData: length, Page: page, Size: size, Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(h.Status, h.Result)
+}
+
+func (h *ReferenceHandler) Find(c *gin.Context) {
+	id := c.Param("id")
+
+	result, err := h.ReferenceUsecase.Find(c, id)
+	if err != nil {
+		err.Path = ".ReferenceHandler->Find()" + err.Path
+		if err.Error == data.ErrNotFound {
+			response.Error(c, h.notifier, fmt.Sprintf("%s not found", h.definition.Name), http.StatusUnprocessableEntity, *err)
+			return
+		}
+		response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: fmt.Sprintf("Data %s Berhasil Ditampilkan", h.definition.Name), Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *ReferenceHandler) Create(c *gin.Context) {
+	var err *types.Error
+	var req models.ReferenceRequest
+	var data *models.Reference
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".ReferenceHandler->Create()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	obj := models.Reference{Name: req.Name}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.ReferenceUsecase.Create(c, obj)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".ReferenceHandler->Create()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: fmt.Sprintf("Data %s Berhasil Ditambahkan", h.definition.Name), Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *ReferenceHandler) Update(c *gin.Context) {
+	var err *types.Error
+	var req models.ReferenceRequest
+	var data *models.Reference
+
+	id := c.Param("id")
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".ReferenceHandler->Update()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	obj := models.Reference{Name: req.Name}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.ReferenceUsecase.Update(c, id, obj)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".ReferenceHandler->Update()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: fmt.Sprintf("Data %s Berhasil Diperbarui", h.definition.Name), Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *ReferenceHandler) FindStatus(c *gin.Context) {
+	datas, err := h.ReferenceUsecase.FindStatus(c)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, h.notifier, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: fmt.Sprintf("Data %s Status Berhasil Ditampilkan", h.definition.Name), Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *ReferenceHandler) UpdateStatus(c *gin.Context) {
+	var err *types.Error
+	var req models.UpdateStatusRequest
+	var data *models.Reference
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".ReferenceHandler->UpdateStatus()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		for _, id := range req.ID {
+			data, err = h.ReferenceUsecase.UpdateStatus(c, id, req.NewStatusID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".ReferenceHandler->UpdateStatus()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: fmt.Sprintf("Status %s Berhasil Diperbarui", h.definition.Name), Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
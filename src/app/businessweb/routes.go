@@ -2,21 +2,70 @@ package businessweb
 
 import (
 	http_bank "luxe-beb-go/src/app/businessweb/bank"
+	http_bankaccount "luxe-beb-go/src/app/businessweb/bankaccount"
+	http_business "luxe-beb-go/src/app/businessweb/business"
+	http_cardprovider "luxe-beb-go/src/app/businessweb/cardprovider"
+	http_clientcache "luxe-beb-go/src/app/businessweb/clientcache"
+	http_clientrequestlog "luxe-beb-go/src/app/businessweb/clientrequestlog"
+	http_codesequence "luxe-beb-go/src/app/businessweb/codesequence"
+	http_emailoutbox "luxe-beb-go/src/app/businessweb/emailoutbox"
+	http_notification "luxe-beb-go/src/app/businessweb/notification"
+	http_notificationrule "luxe-beb-go/src/app/businessweb/notificationrule"
+	http_outlet "luxe-beb-go/src/app/businessweb/outlet"
+	http_reference "luxe-beb-go/src/app/businessweb/reference"
+	http_urltocache "luxe-beb-go/src/app/businessweb/urltocache"
+	http_user "luxe-beb-go/src/app/businessweb/user"
 
 	"luxe-beb-go/library/data"
 	"luxe-beb-go/library/notif"
+	"luxe-beb-go/middleware"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 )
 
 var (
-	bankHandler http_bank.BankHandler
+	bankHandler             http_bank.BankHandler
+	bankAccountHandler      http_bankaccount.BankAccountHandler
+	businessHandler         http_business.BusinessHandler
+	cardProviderHandler     http_cardprovider.CardProviderHandler
+	clientCacheHandler      http_clientcache.ClientCacheHandler
+	clientRequestLogHandler http_clientrequestlog.ClientRequestLogHandler
+	codeSequenceHandler     http_codesequence.CodeSequenceHandler
+	emailOutboxHandler      http_emailoutbox.EmailOutboxHandler
+	notificationHandler     http_notification.NotificationHandler
+	notificationRuleHandler http_notificationrule.NotificationRuleHandler
+	outletHandler           http_outlet.OutletHandler
+	urlToCacheHandler       http_urltocache.URLToCacheHandler
+	userHandler             http_user.UserHandler
+
+	// lookup tables (id, name, status_id) served by the generic reference handler
+	referenceDefinitions = []http_reference.Definition{
+		{Table: "payment_type", Path: "/payment-types", Name: "Payment Type"},
+		{Table: "card_type", Path: "/card-types", Name: "Card Type"},
+	}
 )
 
 func RegisterRoutes(db *sqlx.DB, dataManager *data.Manager, slackNotifier *notif.SlackNotifier, router *gin.Engine, v *gin.RouterGroup) {
-	v1 := v.Group("")
+	v1 := v.Group("", middleware.Export)
 	{
 		bankHandler.RegisterAPI(db, dataManager, slackNotifier, router, v1)
+		bankAccountHandler.RegisterAPI(db, dataManager, slackNotifier, router, v1)
+		businessHandler.RegisterAPI(db, dataManager, slackNotifier, router, v1)
+		cardProviderHandler.RegisterAPI(db, dataManager, slackNotifier, router, v1)
+		clientCacheHandler.RegisterAPI(db, dataManager, slackNotifier, router, v1)
+		clientRequestLogHandler.RegisterAPI(db, dataManager, slackNotifier, router, v1)
+		codeSequenceHandler.RegisterAPI(db, dataManager, slackNotifier, router, v1)
+		emailOutboxHandler.RegisterAPI(db, dataManager, slackNotifier, router, v1)
+		notificationHandler.RegisterAPI(db, dataManager, slackNotifier, router, v1)
+		notificationRuleHandler.RegisterAPI(db, dataManager, slackNotifier, router, v1)
+		outletHandler.RegisterAPI(db, dataManager, slackNotifier, router, v1)
+		urlToCacheHandler.RegisterAPI(db, dataManager, slackNotifier, router, v1)
+		userHandler.RegisterAPI(db, dataManager, slackNotifier, router, v1)
+
+		for _, def := range referenceDefinitions {
+			var referenceHandler http_reference.ReferenceHandler
+			referenceHandler.RegisterAPI(db, dataManager, slackNotifier, router, v1, def)
+		}
 	}
 }
@@ -0,0 +1,265 @@
+package urltocache
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+
+	"luxe-beb-go/library/client"
+	"luxe-beb-go/library/helpers"
+	"luxe-beb-go/middleware"
+	"luxe-beb-go/src/services/urltocache/repository"
+
+	"github.com/gin-gonic/gin"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/http/request"
+	"luxe-beb-go/library/http/response"
+	"luxe-beb-go/library/notif"
+	"luxe-beb-go/library/types"
+)
+
+type URLToCacheHandler struct {
+	URLToCacheService client.URLToCacheServiceInterface
+	dataManager       *data.Manager
+	Result            gin.H
+	Status            int
+	notifier          *notif.SlackNotifier
+}
+
+func (h URLToCacheHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, slackNotifier *notif.SlackNotifier, router *gin.Engine, v *gin.RouterGroup) {
+	urlToCacheRepo := repository.NewURLToCacheRepository(
+		data.NewMySQLStorage(db, "urls_to_cache", client.URLToCache{}, data.MysqlConfig{}),
+	)
+
+	base := &URLToCacheHandler{URLToCacheService: client.NewURLToCacheService(&urlToCacheRepo), dataManager: dataManager, notifier: slackNotifier}
+
+	rs := v.Group("/urls-to-cache")
+	{
+		rs.GET("", middleware.Auth, base.FindAll)
+		rs.GET("/:id", middleware.Auth, base.Find)
+		rs.POST("", middleware.Auth, base.Create)
+		rs.PUT("/:id", middleware.Auth, base.Update)
+		rs.PUT("/:id/block", middleware.Auth, base.Block)
+		rs.PUT("/:id/unblock", middleware.Auth, base.Unblock)
+		rs.DELETE("/:id", middleware.Auth, base.Delete)
+	}
+}
+
+func (h *URLToCacheHandler) FindAll(c *gin.Context) {
+	page, size := helpers.FilterFindAll(c)
+	filterFindAllParams := helpers.FilterFindAllParam(c)
+	params := client.FindAllURLToCachesParams{
+		Search:     c.Query("Search"),
+		Page:       filterFindAllParams.Page,
+		Limit:      filterFindAllParams.Size,
+		Method:     c.Query("Method"),
+		ClientName: c.Query("ClientName"),
+		IsBlocked:  c.Query("IsBlocked") == "true" || c.Query("IsBlocked") == "1",
+	}
+	datas, err := h.URLToCacheService.ListURLToCaches(c, &params)
+	if err != nil {
+		err.Path = ".URLToCacheHandler->FindAll()" + err.Path
+		response.Error(c, h.notifier, err.Message, http.StatusInternalServerError, *err)
+		return
+	}
+
+	length, err := h.URLToCacheService.CountURLToCache(c, &params)
+	if err != nil {
+		err.Path = ".URLToCacheHandler->FindAll()" + err.Path
+		response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.ResultAll{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data URL Cache Berhasil Ditampilkan", TotalData: length, Page: page, Size: size, Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(h.Status, h.Result)
+}
+
+func (h *URLToCacheHandler) Find(c *gin.Context) {
+	id, ok := h.paramID(c, ".URLToCacheHandler->Find()")
+	if !ok {
+		return
+	}
+
+	result, err := h.URLToCacheService.GetURLToCache(c, id)
+	if err != nil {
+		err.Path = ".URLToCacheHandler->Find()" + err.Path
+		response.Error(c, h.notifier, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data URL Cache Berhasil Ditampilkan", Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *URLToCacheHandler) Create(c *gin.Context) {
+	var err *types.Error
+	var req client.CreateURLToCacheParams
+	var data *client.URLToCache
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".URLToCacheHandler->Create()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.URLToCacheService.CreateURLToCache(tctx, &req)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".URLToCacheHandler->Create()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data URL Cache Berhasil Ditambahkan", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *URLToCacheHandler) Update(c *gin.Context) {
+	var err *types.Error
+	var req client.UpdateURLToCacheParams
+	var data *client.URLToCache
+
+	id, ok := h.paramID(c, ".URLToCacheHandler->Update()")
+	if !ok {
+		return
+	}
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".URLToCacheHandler->Update()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.URLToCacheService.UpdateURLToCache(tctx, id, &req)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".URLToCacheHandler->Update()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data URL Cache Berhasil Diperbarui", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *URLToCacheHandler) Block(c *gin.Context) {
+	h.setBlocked(c, true, ".URLToCacheHandler->Block()", "Data URL Cache Berhasil Diblokir")
+}
+
+func (h *URLToCacheHandler) Unblock(c *gin.Context) {
+	h.setBlocked(c, false, ".URLToCacheHandler->Unblock()", "Blokir URL Cache Berhasil Dibuka")
+}
+
+// setBlocked blocks or unblocks the rule, a blocked url is always called without the cache
+func (h *URLToCacheHandler) setBlocked(c *gin.Context, isBlocked bool, path string, message string) {
+	var err *types.Error
+	var data *client.URLToCache
+
+	id, ok := h.paramID(c, path)
+	if !ok {
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.URLToCacheService.GetURLToCache(tctx, id)
+		if err != nil {
+			return err
+		}
+
+		data, err = h.URLToCacheService.UpdateURLToCache(tctx, id, &client.UpdateURLToCacheParams{
+			BaseURL:      data.BaseURL,
+			Method:       data.Method,
+			ClientID:     data.ClientID,
+			ClientName:   data.ClientName,
+			BufferedTime: data.BufferedTime,
+			IsBlocked:    isBlocked,
+		})
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = path + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: message, Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *URLToCacheHandler) Delete(c *gin.Context) {
+	id, ok := h.paramID(c, ".URLToCacheHandler->Delete()")
+	if !ok {
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		return h.URLToCacheService.DeleteURLToCache(tctx, id)
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".URLToCacheHandler->Delete()" + errTransaction.Path
+		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data URL Cache Berhasil Dihapus"}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *URLToCacheHandler) paramID(c *gin.Context, path string) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, h.notifier, "Data URL Cache tidak ditemukan", http.StatusNotFound, types.Error{
+			Path:       path,
+			Message:    err.Error(),
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "validation-error",
+		})
+		return 0, false
+	}
+	return id, true
+}
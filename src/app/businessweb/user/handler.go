@@ -2,7 +2,6 @@ package user
 
 import (
 	"crypto/md5"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -19,9 +18,12 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/http/request"
 	"luxe-beb-go/library/http/response"
+	"luxe-beb-go/library/importer"
 	"luxe-beb-go/library/notif"
 	"luxe-beb-go/library/types"
+	"luxe-beb-go/library/validator"
 )
 
 var (
@@ -32,28 +34,37 @@ var (
 type UserHandler struct {
 	UserUsecase user.Usecase
 	dataManager *data.Manager
+	importer    *importer.Importer
 	Result      gin.H
 	Status      int
 	notifier    *notif.SlackNotifier
+	router      *notif.Router
 }
 
 func (h UserHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, slackNotifier *notif.SlackNotifier, router *gin.Engine, v *gin.RouterGroup) {
-	userRepo := repository.NewUserRepository(
-		data.NewMySQLStorage(db, "users", models.User{}, data.MysqlConfig{}),
-		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
-	)
+	userStorage := data.NewMySQLStorage(db, "users", models.User{}, data.MysqlConfig{BusinessTable: "user_businesses", BusinessKey: "user_id"})
+	statusStorage := data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{})
 
-	uUser := usecase.NewUserUsecase(db, &userRepo)
+	// login & the unique username/email check look at every user, whatever business they belong to
+	allUserStorage := data.NewMySQLStorage(db, "users", models.User{}, data.MysqlConfig{})
+	validator.RegisterUniqueStorage("users", allUserStorage)
 
-	base := &UserHandler{UserUsecase: uUser, dataManager: dataManager, notifier: slackNotifier}
+	userRepo := repository.NewUserRepository(userStorage, statusStorage)
+	loginRepo := repository.NewUserRepository(allUserStorage, statusStorage)
+
+	uUser := usecase.NewUserUsecase(db, &userRepo, &loginRepo)
+
+	base := &UserHandler{UserUsecase: uUser, dataManager: dataManager, importer: importer.New(dataManager), notifier: slackNotifier, router: notif.NewRouter(db, notif.RouterConfig{})}
 
 	rs := v.Group("/users")
 	{
 		rs.GET("", middleware.Auth, base.FindAll)
 		rs.GET("/:id", middleware.Auth, base.Find)
 		rs.POST("", middleware.Auth, base.Create)
+		rs.POST("/import", middleware.Auth, base.Import)
 		rs.PUT("/:id", middleware.Auth, base.Update)
 		rs.PUT("/status", middleware.Auth, base.UpdateStatus)
+		rs.PUT("/status/batch", middleware.Auth, base.BatchUpdateStatus)
 
 		rs.POST("auth/login", base.Login)
 
@@ -63,7 +74,7 @@ func (h UserHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, slackNo
 
 	status := v.Group("/statuses")
 	{
-		status.GET("/users", middleware.AuthCheckIP, base.FindStatus)
+		status.GET("/users", middleware.AuthCheckIP, middleware.ResponseCache(middleware.DefaultResponseCacheTTL, "status"), base.FindStatus)
 	}
 }
 
@@ -112,6 +123,7 @@ func (h *UserHandler) Find(c *gin.Context) {
 		return
 	}
 
+	response.ETag(c, result.Version)
 	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data User Berhasil Ditampilkan", Data: result}
 	h.Result = gin.H{
 		"result": dataresponse,
@@ -122,17 +134,44 @@ func (h *UserHandler) Find(c *gin.Context) {
 
 func (h *UserHandler) Create(c *gin.Context) {
 	var err *types.Error
-	var obj models.User
+	var req models.UserRequest
 	var data *models.User
 
-	obj.Name = c.PostForm("Name")
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".UserHandler->Create()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	obj := models.User{
+		Name:     req.Name,
+		Email:    req.Email,
+		Username: req.Username,
+		Password: req.Password,
+	}
 
 	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
-		data, err = h.UserUsecase.Create(c, obj)
+		data, err = h.UserUsecase.Create(tctx, obj)
 		if err != nil {
 			return err
 		}
 
+		errPublish := h.router.Publish(tctx, notif.Event{
+			Name:   notif.EventUserCreated,
+			UserID: data.ID,
+			Data:   map[string]interface{}{"Name": data.Name, "Username": data.Username},
+		})
+		if errPublish != nil {
+			return &types.Error{
+				Path:       ".UserHandler->Create()",
+				Message:    errPublish.Error(),
+				Error:      errPublish,
+				StatusCode: http.StatusInternalServerError,
+				Type:       "mysql-error",
+			}
+		}
+
 		return nil
 	})
 
@@ -152,15 +191,34 @@ func (h *UserHandler) Create(c *gin.Context) {
 
 func (h *UserHandler) Update(c *gin.Context) {
 	var err *types.Error
-	var obj models.User
+	var req models.UserUpdateRequest
 	var data *models.User
 
 	id := c.Param("id")
 
-	obj.Name = c.PostForm("Name")
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".UserHandler->Update()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	version, errVersion := request.IfMatch(c, req.Version)
+	if errVersion != nil {
+		errVersion.Path = ".UserHandler->Update()" + errVersion.Path
+		response.Error(c, h.notifier, errVersion.Message, errVersion.StatusCode, *errVersion)
+		return
+	}
+
+	obj := models.User{
+		Name:     req.Name,
+		Email:    req.Email,
+		Username: req.Username,
+		Version:  version,
+	}
 
 	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
-		data, err = h.UserUsecase.Update(c, id, obj)
+		data, err = h.UserUsecase.Update(tctx, id, obj)
 		if err != nil {
 			return err
 		}
@@ -169,10 +227,18 @@ func (h *UserHandler) Update(c *gin.Context) {
 
 	if errTransaction != nil {
 		errTransaction.Path = ".UserHandler->Update()" + errTransaction.Path
+		if errTransaction.StatusCode == http.StatusPreconditionFailed {
+			current, errFind := h.UserUsecase.Find(c, id)
+			if errFind == nil {
+				response.PreconditionFailed(c, "Data User telah diubah oleh pengguna lain, muat ulang data sebelum memperbarui", current.Version, current)
+				return
+			}
+		}
 		response.Error(c, h.notifier, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
 		return
 	}
 
+	response.ETag(c, data.Version)
 	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: "Data User Berhasil Diperbarui", Data: data}
 	h.Result = gin.H{
 		"result": dataresponse,
@@ -198,26 +264,19 @@ func (h *UserHandler) FindStatus(c *gin.Context) {
 
 func (h *UserHandler) UpdateStatus(c *gin.Context) {
 	var err *types.Error
+	var req models.UpdateStatusRequest
 	var data *models.User
 
-	var ids []*models.IDNameTemplate
-
-	newStatusID := c.PostForm("NewStatusID")
-
-	errJson := json.Unmarshal([]byte(c.PostForm("ID")), &ids)
-	if errJson != nil {
-		err = &types.Error{
-			Path:  ".UserHandler->UpdateStatus()",
-			Error: errJson,
-			Type:  "convert-error",
-		}
-		response.Error(c, h.notifier, "Internal Server Error", http.StatusInternalServerError, *err)
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".UserHandler->UpdateStatus()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
 		return
 	}
 
 	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
-		for _, id := range ids {
-			data, err = h.UserUsecase.UpdateStatus(c, id.ID, newStatusID)
+		for _, id := range req.ID {
+			data, err = h.UserUsecase.UpdateStatus(tctx, id, req.NewStatusID)
 			if err != nil {
 				return err
 			}
@@ -240,12 +299,70 @@ func (h *UserHandler) UpdateStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, h.Result)
 }
 
+// BatchUpdateStatus updates the status of every ID and answers with the result of each of them
+func (h *UserHandler) BatchUpdateStatus(c *gin.Context) {
+	var req models.BatchUpdateStatusRequest
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".UserHandler->BatchUpdateStatus()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
+	statuses, err := h.UserUsecase.FindStatus(c)
+	if err != nil {
+		err.Path = ".UserHandler->BatchUpdateStatus()" + err.Path
+		response.Error(c, h.notifier, err.Message, http.StatusInternalServerError, *err)
+		return
+	}
+	if !models.HasStatus(statuses, req.NewStatusID) {
+		errStatus := types.Error{
+			Path:       ".UserHandler->BatchUpdateStatus()",
+			Message:    fmt.Sprintf("NewStatusID %s tidak ditemukan", req.NewStatusID),
+			Error:      data.ErrInvalidStatus,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+		response.Error(c, h.notifier, errStatus.Message, errStatus.StatusCode, errStatus)
+		return
+	}
+
+	results, isSuccess := h.dataManager.RunBatch(c, req.ID, req.IsPartialSuccess, func(tctx *gin.Context, id string) (interface{}, *types.Error) {
+		return h.UserUsecase.UpdateStatus(tctx, id, req.NewStatusID)
+	})
+
+	status, statusCode, message := "Sukses", http.StatusOK, "Status User Berhasil Diperbarui"
+	if !isSuccess {
+		status, statusCode, message = "Warning", http.StatusUnprocessableEntity, "Status User Gagal Diperbarui"
+		if req.IsPartialSuccess {
+			statusCode, message = http.StatusMultiStatus, "Sebagian Status User Gagal Diperbarui"
+		}
+	}
+
+	dataresponse := types.Result{Status: status, StatusCode: statusCode, Message: message, Data: results}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(statusCode, h.Result)
+}
+
 // LOGIN
 func (h *UserHandler) Login(c *gin.Context) {
+	var req models.LoginRequest
+
+	errBind := request.Bind(c, &req)
+	if errBind != nil {
+		errBind.Path = ".UserHandler->Login()" + errBind.Path
+		response.Error(c, h.notifier, errBind.Message, errBind.StatusCode, *errBind)
+		return
+	}
+
 	hash := md5.New()
-	io.WriteString(hash, c.PostForm("Password"))
+	io.WriteString(hash, req.Password)
 
-	username := c.PostForm("Username")
+	username := req.Username
 	password := fmt.Sprintf("%x", hash.Sum(nil))
 
 	var params models.FindAllUserParams
@@ -255,7 +372,7 @@ func (h *UserHandler) Login(c *gin.Context) {
 	params.Password = password
 	params.FindAllParams.StatusID = "status_id = 1"
 
-	datas, err := h.UserUsecase.Login(c, params)
+	datas, err := h.UserUsecase.Login(c, params, req.BusinessID)
 	if err != nil {
 		c.JSON(401, response.ErrorResponse{
 			Code:    "LoginFailed",
@@ -278,3 +395,64 @@ func (h *UserHandler) Login(c *gin.Context) {
 }
 
 // // //
+
+func (h *UserHandler) Import(c *gin.Context) {
+	options, errOptions := importer.OptionsFromRequest(c)
+	if errOptions != nil {
+		errOptions.Path = ".UserHandler->Import()" + errOptions.Path
+		response.Error(c, h.notifier, errOptions.Message, errOptions.StatusCode, *errOptions)
+		return
+	}
+
+	rows, errRead := importer.ReadRequest(c, "File")
+	if errRead != nil {
+		errRead.Path = ".UserHandler->Import()" + errRead.Path
+		response.Error(c, h.notifier, errRead.Message, errRead.StatusCode, *errRead)
+		return
+	}
+
+	report, errImport := h.importer.Run(c, rows, options, func(tctx *gin.Context, row importer.Row) (string, *types.Error) {
+		var req models.UserRequest
+		if err := importer.DecodeRequest(tctx, row, &req); err != nil {
+			return "", err
+		}
+
+		data, err := h.UserUsecase.Create(tctx, models.User{
+			Name:     req.Name,
+			Email:    req.Email,
+			Username: req.Username,
+			Password: req.Password,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		return data.ID, nil
+	})
+	if errImport != nil {
+		errImport.Path = ".UserHandler->Import()" + errImport.Path
+		response.Error(c, h.notifier, errImport.Message, http.StatusInternalServerError, *errImport)
+		return
+	}
+
+	if report.Failed > 0 && options.Mode == importer.ModeAtomic && !options.DryRun {
+		dataresponse := types.Result{Status: "Warning", StatusCode: http.StatusUnprocessableEntity, Message: "Import Data User Gagal, Tidak Ada Data Yang Disimpan", Data: report}
+		h.Result = gin.H{
+			"result": dataresponse,
+		}
+		c.JSON(http.StatusUnprocessableEntity, h.Result)
+		return
+	}
+
+	message := "Import Data User Berhasil"
+	if options.DryRun {
+		message = "Pratinjau Import Data User Berhasil"
+	}
+
+	dataresponse := types.Result{Status: "Sukses", StatusCode: http.StatusOK, Message: message, Data: report}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
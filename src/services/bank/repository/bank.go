@@ -50,8 +50,8 @@ func (s BankRepository) FindAll(ctx *gin.Context, params models.FindAllBankParam
 
 	query := fmt.Sprintf(`
   SELECT
-    banks.id, banks.name,
-    banks.status_id, status.name status_name
+    banks.id, banks.name, banks.account_number_pattern,
+    banks.status_id, status.name status_name, banks.version
   FROM banks
   JOIN status ON banks.status_id = status.id
   WHERE %s
@@ -75,13 +75,15 @@ func (s BankRepository) FindAll(ctx *gin.Context, params models.FindAllBankParam
 
 	for _, v := range bulks {
 		obj := &models.Bank{
-			ID:       v.ID,
-			Name:     v.Name,
-			StatusID: v.StatusID,
+			ID:                   v.ID,
+			Name:                 v.Name,
+			AccountNumberPattern: v.AccountNumberPattern,
+			StatusID:             v.StatusID,
 			Status: models.Status{
 				ID:   v.StatusID,
 				Name: v.StatusName,
 			},
+			Version: v.Version,
 		}
 
 		data = append(data, obj)
@@ -97,8 +99,8 @@ func (s BankRepository) Find(ctx *gin.Context, id string) (*models.Bank, *types.
 	var err error
 
 	query := fmt.Sprintf(`
-  SELECT banks.id, banks.name,
-  banks.status_id, status.name status_name
+  SELECT banks.id, banks.name, banks.account_number_pattern,
+  banks.status_id, status.name status_name, banks.version
   FROM banks
   JOIN status ON banks.status_id = status.id
   WHERE banks.id = :id`)
@@ -120,13 +122,15 @@ func (s BankRepository) Find(ctx *gin.Context, id string) (*models.Bank, *types.
 	if len(bulks) > 0 {
 		v := bulks[0]
 		result = models.Bank{
-			ID:       v.ID,
-			Name:     v.Name,
-			StatusID: v.StatusID,
+			ID:                   v.ID,
+			Name:                 v.Name,
+			AccountNumberPattern: v.AccountNumberPattern,
+			StatusID:             v.StatusID,
 			Status: models.Status{
 				ID:   v.StatusID,
 				Name: v.StatusName,
 			},
+			Version: v.Version,
 		}
 	} else {
 		return nil, &types.Error{
@@ -144,7 +148,7 @@ func (s BankRepository) Find(ctx *gin.Context, id string) (*models.Bank, *types.
 // Create is a function to get by ID
 func (s BankRepository) Create(ctx *gin.Context, obj *models.Bank) (*models.Bank, *types.Error) {
 	data := models.Bank{}
-	result, err := s.repository.Insert(ctx, obj)
+	_, err := s.repository.Insert(ctx, obj)
 	if err != nil {
 		return nil, &types.Error{
 			Path:       ".BankStorage->Create()",
@@ -155,8 +159,7 @@ func (s BankRepository) Create(ctx *gin.Context, obj *models.Bank) (*models.Bank
 		}
 	}
 
-	lastID, _ := (*result).LastInsertId()
-	err = s.repository.FindByID(ctx, &data, lastID)
+	err = s.repository.FindByID(ctx, &data, obj.ID)
 	if err != nil {
 		return nil, &types.Error{
 			Path:       ".BankStorage->Create()",
@@ -171,18 +174,22 @@ func (s BankRepository) Create(ctx *gin.Context, obj *models.Bank) (*models.Bank
 
 // Update is a function to get by ID
 func (s BankRepository) Update(ctx *gin.Context, obj *models.Bank) (*models.Bank, *types.Error) {
-	data := models.Bank{}
 	err := s.repository.Update(ctx, obj)
 	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err == data.ErrVersionConflict {
+			statusCode = http.StatusPreconditionFailed
+		}
 		return nil, &types.Error{
 			Path:       ".BankStorage->Update()",
 			Message:    err.Error(),
 			Error:      err,
-			StatusCode: http.StatusInternalServerError,
+			StatusCode: statusCode,
 			Type:       "mysql-error",
 		}
 	}
 
+	data := models.Bank{}
 	err = s.repository.FindByID(ctx, &data, obj.ID)
 	if err != nil {
 		return nil, &types.Error{
@@ -216,18 +223,27 @@ func (s BankRepository) FindStatus(ctx *gin.Context) ([]*models.Status, *types.E
 
 // UpdateStatus is a function to get by ID
 func (s BankRepository) UpdateStatus(ctx *gin.Context, id string, statusID string) (*models.Bank, *types.Error) {
-	data := models.Bank{}
 	err := s.repository.UpdateStatus(ctx, id, statusID)
 	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err {
+		case data.ErrNotFound:
+			statusCode = http.StatusNotFound
+		case data.ErrInvalidStatus:
+			statusCode = http.StatusUnprocessableEntity
+		case data.ErrOutOfScope:
+			statusCode = http.StatusForbidden
+		}
 		return nil, &types.Error{
 			Path:       ".BankStorage->UpdateStatus()",
 			Message:    err.Error(),
 			Error:      err,
-			StatusCode: http.StatusInternalServerError,
+			StatusCode: statusCode,
 			Type:       "mysql-error",
 		}
 	}
 
+	data := models.Bank{}
 	err = s.repository.FindByID(ctx, &data, id)
 	if err != nil {
 		return nil, &types.Error{
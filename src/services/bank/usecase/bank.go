@@ -2,11 +2,10 @@ package usecase
 
 import (
 	"net/http"
-	"reflect"
-	"strings"
 	"time"
 
 	"luxe-beb-go/library/types"
+	"luxe-beb-go/library/validator"
 	"luxe-beb-go/src/services/bank"
 
 	"luxe-beb-go/models"
@@ -16,7 +15,6 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/jmoiron/sqlx"
-	validator "gopkg.in/go-playground/validator.v9"
 )
 
 type BankUsecase struct {
@@ -66,16 +64,7 @@ func (u *BankUsecase) Count(ctx *gin.Context, filterFindAllParams models.FindAll
 }
 
 func (u *BankUsecase) Create(ctx *gin.Context, obj models.Bank) (*models.Bank, *types.Error) {
-	validate := validator.New()
-	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
-		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
-		if name == "-" {
-			return ""
-		}
-		return name
-	})
-
-	errValidation := validate.Struct(obj)
+	errValidation := validator.Shared().StructCtx(ctx, obj)
 	if errValidation != nil {
 		return nil, &types.Error{
 			Path:       ".BankUsecase->Create()",
@@ -87,9 +76,10 @@ func (u *BankUsecase) Create(ctx *gin.Context, obj models.Bank) (*models.Bank, *
 	}
 
 	data := models.Bank{
-		ID:       uuid.New().String(),
-		Name:     obj.Name,
-		StatusID: models.DEFAULT_STATUS_ID,
+		ID:                   uuid.New().String(),
+		Name:                 obj.Name,
+		AccountNumberPattern: obj.AccountNumberPattern,
+		StatusID:             models.DEFAULT_STATUS_ID,
 	}
 
 	result, err := u.bankRepo.Create(ctx, &data)
@@ -102,16 +92,7 @@ func (u *BankUsecase) Create(ctx *gin.Context, obj models.Bank) (*models.Bank, *
 }
 
 func (u *BankUsecase) Update(ctx *gin.Context, id string, obj models.Bank) (*models.Bank, *types.Error) {
-	validate := validator.New()
-	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
-		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
-		if name == "-" {
-			return ""
-		}
-		return name
-	})
-
-	errValidation := validate.Struct(obj)
+	errValidation := validator.Shared().StructCtx(ctx, obj)
 	if errValidation != nil {
 		return nil, &types.Error{
 			Path:       ".BankUsecase->Update()",
@@ -129,6 +110,10 @@ func (u *BankUsecase) Update(ctx *gin.Context, id string, obj models.Bank) (*mod
 	}
 
 	data.Name = obj.Name
+	data.AccountNumberPattern = obj.AccountNumberPattern
+
+	// the row is only updated when it is still at the version the caller read
+	data.Version = obj.Version
 
 	result, err := u.bankRepo.Update(ctx, data)
 	if err != nil {
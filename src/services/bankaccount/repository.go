@@ -0,0 +1,22 @@
+package bankaccount
+
+import (
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Repository is the contract between Repository and usecase
+type Repository interface {
+	FindAll(*gin.Context, models.FindAllBankAccountParams) ([]*models.BankAccount, *types.Error)
+	Find(*gin.Context, string, string) (*models.BankAccount, *types.Error)
+	Create(*gin.Context, *models.BankAccount) (*models.BankAccount, *types.Error)
+	Update(*gin.Context, *models.BankAccount) (*models.BankAccount, *types.Error)
+	ClearDefault(*gin.Context, *models.BankAccount) *types.Error
+
+	FindBank(*gin.Context, string) (*models.Bank, *types.Error)
+
+	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	UpdateStatus(*gin.Context, string, string, string) (*models.BankAccount, *types.Error)
+}
@@ -0,0 +1,293 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BankAccountRepository initialize object from model BankAccount, to be used in database operation
+type BankAccountRepository struct {
+	repository       data.GenericStorage
+	bankRepository   data.GenericStorage
+	statusRepository data.GenericStorage
+}
+
+// NewBankAccountRepository initialize service that provide connection to Database
+func NewBankAccountRepository(repository data.GenericStorage, bankRepository data.GenericStorage, statusRepository data.GenericStorage) BankAccountRepository {
+	return BankAccountRepository{repository: repository, bankRepository: bankRepository, statusRepository: statusRepository}
+}
+
+// FindAll is a function to get all Data of a bank
+func (s BankAccountRepository) FindAll(ctx *gin.Context, params models.FindAllBankAccountParams) ([]*models.BankAccount, *types.Error) {
+	data := []*models.BankAccount{}
+	bulks := []*models.BankAccountBulk{}
+
+	var err error
+
+	where := `bank_accounts.bank_id = :bank_id`
+
+	if params.FindAllParams.DataFinder != "" {
+		where = fmt.Sprintf("%s AND %s", where, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where = fmt.Sprintf("%s AND bank_accounts.%s", where, params.FindAllParams.StatusID)
+	}
+
+	if params.FindAllParams.SortBy != "" {
+		where = fmt.Sprintf("%s ORDER BY %s", where, params.FindAllParams.SortBy)
+	}
+
+	if params.FindAllParams.Page > 0 && params.FindAllParams.Size > 0 {
+		where = fmt.Sprintf(`%s LIMIT :limit OFFSET :offset`, where)
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    bank_accounts.id, bank_accounts.business_id, bank_accounts.bank_id, banks.name bank_name,
+    bank_accounts.account_number, bank_accounts.holder_name, bank_accounts.branch,
+    bank_accounts.currency, bank_accounts.is_default,
+    bank_accounts.status_id, status.name status_name
+  FROM bank_accounts
+  JOIN banks ON bank_accounts.bank_id = banks.id
+  JOIN status ON bank_accounts.status_id = status.id
+  WHERE %s
+  `, where)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"bank_id": params.BankID,
+		"limit":   params.FindAllParams.Size,
+		"offset":  ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+	})
+
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".BankAccountStorage->FindAll()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		data = append(data, bulkToBankAccount(v))
+	}
+
+	return data, nil
+}
+
+// Find is a function to get by ID of an account of the bank
+func (s BankAccountRepository) Find(ctx *gin.Context, bankID string, id string) (*models.BankAccount, *types.Error) {
+	bulks := []*models.BankAccountBulk{}
+	var err error
+
+	query := `
+  SELECT
+    bank_accounts.id, bank_accounts.business_id, bank_accounts.bank_id, banks.name bank_name,
+    bank_accounts.account_number, bank_accounts.holder_name, bank_accounts.branch,
+    bank_accounts.currency, bank_accounts.is_default,
+    bank_accounts.status_id, status.name status_name
+  FROM bank_accounts
+  JOIN banks ON bank_accounts.bank_id = banks.id
+  JOIN status ON bank_accounts.status_id = status.id
+  WHERE bank_accounts.id = :id AND bank_accounts.bank_id = :bank_id`
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"id":      id,
+		"bank_id": bankID,
+	})
+
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".BankAccountStorage->Find()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(bulks) == 0 {
+		return nil, &types.Error{
+			Path:       ".BankAccountStorage->Find()",
+			Message:    "Data Not Found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return bulkToBankAccount(bulks[0]), nil
+}
+
+// Create is a function to insert an account then get it back by ID
+func (s BankAccountRepository) Create(ctx *gin.Context, obj *models.BankAccount) (*models.BankAccount, *types.Error) {
+	_, err := s.repository.Insert(ctx, obj)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err == data.ErrOutOfScope {
+			statusCode = http.StatusForbidden
+		}
+		return nil, &types.Error{
+			Path:       ".BankAccountStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: statusCode,
+			Type:       "mysql-error",
+		}
+	}
+
+	result, errFind := s.Find(ctx, obj.BankID, obj.ID)
+	if errFind != nil {
+		errFind.Path = ".BankAccountStorage->Create()" + errFind.Path
+		return nil, errFind
+	}
+
+	return result, nil
+}
+
+// Update is a function to update an account then get it back by ID
+func (s BankAccountRepository) Update(ctx *gin.Context, obj *models.BankAccount) (*models.BankAccount, *types.Error) {
+	err := s.repository.Update(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".BankAccountStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	result, errFind := s.Find(ctx, obj.BankID, obj.ID)
+	if errFind != nil {
+		errFind.Path = ".BankAccountStorage->Update()" + errFind.Path
+		return nil, errFind
+	}
+
+	return result, nil
+}
+
+// ClearDefault unsets the default flag of every account of the business except the given one,
+// so there is only one account receiving the transfers
+func (s BankAccountRepository) ClearDefault(ctx *gin.Context, obj *models.BankAccount) *types.Error {
+	err := s.repository.ExecQuery(ctx, `
+  UPDATE bank_accounts SET is_default = 0
+  WHERE is_default = 1 AND id <> :id AND business_id = :business_id`, map[string]interface{}{
+		"id":          obj.ID,
+		"business_id": obj.BusinessID,
+	})
+	if err != nil {
+		return &types.Error{
+			Path:       ".BankAccountStorage->ClearDefault()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
+
+// FindBank is a function to get the bank owning the accounts
+func (s BankAccountRepository) FindBank(ctx *gin.Context, bankID string) (*models.Bank, *types.Error) {
+	bank := models.Bank{}
+	err := s.bankRepository.FindByID(ctx, &bank, bankID)
+	if err != nil {
+		if err == data.ErrNotFound {
+			return nil, &types.Error{
+				Path:       ".BankAccountStorage->FindBank()",
+				Message:    "Bank Not Found",
+				Error:      err,
+				StatusCode: http.StatusNotFound,
+				Type:       "mysql-error",
+			}
+		}
+
+		return nil, &types.Error{
+			Path:       ".BankAccountStorage->FindBank()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &bank, nil
+}
+
+// FindStatus is a function to get all status
+func (s BankAccountRepository) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	statuses := []*models.Status{}
+
+	err := s.statusRepository.Where(ctx, &statuses, "1=1", map[string]interface{}{})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".BankAccountStorage->FindStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return statuses, nil
+}
+
+// UpdateStatus is a function to update the status of an account of the bank
+func (s BankAccountRepository) UpdateStatus(ctx *gin.Context, bankID string, id string, statusID string) (*models.BankAccount, *types.Error) {
+	_, errFind := s.Find(ctx, bankID, id)
+	if errFind != nil {
+		errFind.Path = ".BankAccountStorage->UpdateStatus()" + errFind.Path
+		return nil, errFind
+	}
+
+	err := s.repository.UpdateStatus(ctx, id, statusID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".BankAccountStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	result, errFind := s.Find(ctx, bankID, id)
+	if errFind != nil {
+		errFind.Path = ".BankAccountStorage->UpdateStatus()" + errFind.Path
+		return nil, errFind
+	}
+
+	return result, nil
+}
+
+func bulkToBankAccount(v *models.BankAccountBulk) *models.BankAccount {
+	return &models.BankAccount{
+		ID:            v.ID,
+		BusinessID:    v.BusinessID,
+		BankID:        v.BankID,
+		AccountNumber: v.AccountNumber,
+		HolderName:    v.HolderName,
+		Branch:        v.Branch,
+		Currency:      v.Currency,
+		IsDefault:     v.IsDefault,
+		StatusID:      v.StatusID,
+		Status: models.Status{
+			ID:   v.StatusID,
+			Name: v.StatusName,
+		},
+		Bank: models.IDNameTemplate{
+			ID:   v.BankID,
+			Name: v.BankName,
+		},
+	}
+}
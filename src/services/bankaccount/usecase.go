@@ -0,0 +1,20 @@
+package bankaccount
+
+import (
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Usecase is the contract between Repository and usecase
+type Usecase interface {
+	FindAll(*gin.Context, models.FindAllBankAccountParams) ([]*models.BankAccount, *types.Error)
+	Find(*gin.Context, string, string) (*models.BankAccount, *types.Error)
+	Count(*gin.Context, models.FindAllBankAccountParams) (int, *types.Error)
+	Create(*gin.Context, string, models.BankAccount) (*models.BankAccount, *types.Error)
+	Update(*gin.Context, string, string, models.BankAccount) (*models.BankAccount, *types.Error)
+
+	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	UpdateStatus(*gin.Context, string, string, string) (*models.BankAccount, *types.Error)
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"luxe-beb-go/library/appcontext"
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/library/validator"
+	"luxe-beb-go/src/services/bankaccount"
+
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var accountNumberSeparators = strings.NewReplacer(" ", "", "-", "", ".", "")
+
+type BankAccountUsecase struct {
+	bankAccountRepo bankaccount.Repository
+	contextTimeout  time.Duration
+	db              *sqlx.DB
+}
+
+func NewBankAccountUsecase(db *sqlx.DB, bankAccountRepo bankaccount.Repository) bankaccount.Usecase {
+	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+
+	return &BankAccountUsecase{
+		bankAccountRepo: bankAccountRepo,
+		contextTimeout:  timeoutContext,
+		db:              db,
+	}
+}
+
+func (u *BankAccountUsecase) FindAll(ctx *gin.Context, filterFindAllParams models.FindAllBankAccountParams) ([]*models.BankAccount, *types.Error) {
+	result, err := u.bankAccountRepo.FindAll(ctx, filterFindAllParams)
+	if err != nil {
+		err.Path = ".BankAccountUsecase->FindAll()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *BankAccountUsecase) Find(ctx *gin.Context, bankID string, id string) (*models.BankAccount, *types.Error) {
+	result, err := u.bankAccountRepo.Find(ctx, bankID, id)
+	if err != nil {
+		err.Path = ".BankAccountUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *BankAccountUsecase) Count(ctx *gin.Context, filterFindAllParams models.FindAllBankAccountParams) (int, *types.Error) {
+	result, err := u.bankAccountRepo.FindAll(ctx, filterFindAllParams)
+	if err != nil {
+		err.Path = ".BankAccountUsecase->Count()" + err.Path
+		return 0, err
+	}
+
+	return len(result), nil
+}
+
+func (u *BankAccountUsecase) Create(ctx *gin.Context, bankID string, obj models.BankAccount) (*models.BankAccount, *types.Error) {
+	bank, err := u.bankAccountRepo.FindBank(ctx, bankID)
+	if err != nil {
+		err.Path = ".BankAccountUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	// banks are shared by every business, the account belongs to the business it is created for
+	businessID := obj.BusinessID
+	if businessID == "" {
+		businessID = appcontext.BusinessID(ctx)
+	}
+
+	data := models.BankAccount{
+		ID:                   uuid.New().String(),
+		BusinessID:           businessID,
+		BankID:               bank.ID,
+		AccountNumber:        accountNumberSeparators.Replace(obj.AccountNumber),
+		HolderName:           obj.HolderName,
+		Branch:               obj.Branch,
+		Currency:             currency(obj.Currency),
+		IsDefault:            obj.IsDefault,
+		StatusID:             models.DEFAULT_STATUS_ID,
+		AccountNumberPattern: bank.AccountNumberPattern,
+	}
+
+	errValidation := validator.Shared().StructCtx(ctx, data)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".BankAccountUsecase->Create()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.bankAccountRepo.Create(ctx, &data)
+	if err != nil {
+		err.Path = ".BankAccountUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	if result.IsDefault {
+		err = u.bankAccountRepo.ClearDefault(ctx, result)
+		if err != nil {
+			err.Path = ".BankAccountUsecase->Create()" + err.Path
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (u *BankAccountUsecase) Update(ctx *gin.Context, bankID string, id string, obj models.BankAccount) (*models.BankAccount, *types.Error) {
+	bank, err := u.bankAccountRepo.FindBank(ctx, bankID)
+	if err != nil {
+		err.Path = ".BankAccountUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	data, err := u.bankAccountRepo.Find(ctx, bankID, id)
+	if err != nil {
+		err.Path = ".BankAccountUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	data.AccountNumber = accountNumberSeparators.Replace(obj.AccountNumber)
+	data.HolderName = obj.HolderName
+	data.Branch = obj.Branch
+	data.Currency = currency(obj.Currency)
+	data.IsDefault = obj.IsDefault
+	data.AccountNumberPattern = bank.AccountNumberPattern
+
+	errValidation := validator.Shared().StructCtx(ctx, data)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".BankAccountUsecase->Update()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.bankAccountRepo.Update(ctx, data)
+	if err != nil {
+		err.Path = ".BankAccountUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	if result.IsDefault {
+		err = u.bankAccountRepo.ClearDefault(ctx, result)
+		if err != nil {
+			err.Path = ".BankAccountUsecase->Update()" + err.Path
+			return nil, err
+		}
+	}
+
+	return result, err
+}
+
+func (u *BankAccountUsecase) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	result, err := u.bankAccountRepo.FindStatus(ctx)
+	if err != nil {
+		err.Path = ".BankAccountUsecase->FindStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *BankAccountUsecase) UpdateStatus(ctx *gin.Context, bankID string, id string, newStatusID string) (*models.BankAccount, *types.Error) {
+	result, err := u.bankAccountRepo.UpdateStatus(ctx, bankID, id, newStatusID)
+	if err != nil {
+		err.Path = ".BankAccountUsecase->UpdateStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, err
+}
+
+// currency uppercases the currency code, an empty one falls back to the default currency
+func currency(code string) string {
+	if code == "" {
+		return models.DEFAULT_CURRENCY
+	}
+	return strings.ToUpper(code)
+}
@@ -0,0 +1,19 @@
+package business
+
+import (
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Repository is the contract between Repository and usecase
+type Repository interface {
+	FindAll(*gin.Context, models.FindAllBusinessParams) ([]*models.Business, *types.Error)
+	Find(*gin.Context, string) (*models.Business, *types.Error)
+	Create(*gin.Context, *models.Business) (*models.Business, *types.Error)
+	Update(*gin.Context, *models.Business) (*models.Business, *types.Error)
+
+	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	UpdateStatus(*gin.Context, string, string) (*models.Business, *types.Error)
+}
@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BusinessRepository initialize object from model Business, to be used in database operation
+type BusinessRepository struct {
+	repository       data.GenericStorage
+	statusRepository data.GenericStorage
+}
+
+// NewBusinessRepository initialize service that provide connection to Database
+func NewBusinessRepository(repository data.GenericStorage, statusRepository data.GenericStorage) BusinessRepository {
+	//db := &models.DB{DB: configs.ActiveDB}
+	return BusinessRepository{repository: repository, statusRepository: statusRepository}
+}
+
+// FindAll is a function to get all Data
+func (s BusinessRepository) FindAll(ctx *gin.Context, params models.FindAllBusinessParams) ([]*models.Business, *types.Error) {
+	data := []*models.Business{}
+	bulks := []*models.BusinessBulk{}
+
+	var err error
+
+	where := `true`
+
+	if params.FindAllParams.DataFinder != "" {
+		where = fmt.Sprintf("%s AND %s", where, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where = fmt.Sprintf("%s AND businesses.%s", where, params.FindAllParams.StatusID)
+	}
+
+	if params.FindAllParams.SortBy != "" {
+		where = fmt.Sprintf("%s ORDER BY %s", where, params.FindAllParams.SortBy)
+	}
+
+	if params.FindAllParams.Page > 0 && params.FindAllParams.Size > 0 {
+		where = fmt.Sprintf(`%s LIMIT :limit OFFSET :offset`, where)
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    businesses.id, businesses.name,
+    businesses.status_id, status.name status_name
+  FROM businesses
+  JOIN status ON businesses.status_id = status.id
+  WHERE %s
+  `, where)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"limit":     params.FindAllParams.Size,
+		"offset":    ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+		"status_id": params.FindAllParams.StatusID,
+	})
+
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".BusinessStorage->FindAll()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		obj := &models.Business{
+			ID:       v.ID,
+			Name:     v.Name,
+			StatusID: v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+		}
+
+		data = append(data, obj)
+	}
+
+	return data, nil
+}
+
+// Find is a function to get by ID
+func (s BusinessRepository) Find(ctx *gin.Context, id string) (*models.Business, *types.Error) {
+	result := models.Business{}
+	bulks := []*models.BusinessBulk{}
+	var err error
+
+	query := fmt.Sprintf(`
+  SELECT businesses.id, businesses.name,
+  businesses.status_id, status.name status_name
+  FROM businesses
+  JOIN status ON businesses.status_id = status.id
+  WHERE businesses.id = :id`)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"id": id,
+	})
+
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".BusinessStorage->Find()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(bulks) > 0 {
+		v := bulks[0]
+		result = models.Business{
+			ID:       v.ID,
+			Name:     v.Name,
+			StatusID: v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+		}
+	} else {
+		return nil, &types.Error{
+			Path:       ".BusinessStorage->Find()",
+			Message:    "Data Not Found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &result, nil
+}
+
+// Create is a function to get by ID
+func (s BusinessRepository) Create(ctx *gin.Context, obj *models.Business) (*models.Business, *types.Error) {
+	data := models.Business{}
+	_, err := s.repository.Insert(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".BusinessStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".BusinessStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+// Update is a function to get by ID
+func (s BusinessRepository) Update(ctx *gin.Context, obj *models.Business) (*models.Business, *types.Error) {
+	data := models.Business{}
+	err := s.repository.Update(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".BusinessStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".BusinessStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+// FindStatus is a function to get by ID
+func (s BusinessRepository) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	businessStatus := []*models.Status{}
+
+	err := s.statusRepository.Where(ctx, &businessStatus, "1=1", map[string]interface{}{})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".BusinessStorage->FindStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return businessStatus, nil
+}
+
+// UpdateStatus is a function to get by ID
+func (s BusinessRepository) UpdateStatus(ctx *gin.Context, id string, statusID string) (*models.Business, *types.Error) {
+	data := models.Business{}
+	err := s.repository.UpdateStatus(ctx, id, statusID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".BusinessStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, id)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".BusinessStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &data, nil
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"net/http"
+	"time"
+
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/library/validator"
+	"luxe-beb-go/src/services/business"
+
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type BusinessUsecase struct {
+	businessRepo   business.Repository
+	contextTimeout time.Duration
+	db             *sqlx.DB
+}
+
+func NewBusinessUsecase(db *sqlx.DB, businessRepo business.Repository) business.Usecase {
+	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+
+	return &BusinessUsecase{
+		businessRepo:   businessRepo,
+		contextTimeout: timeoutContext,
+		db:             db,
+	}
+}
+
+func (u *BusinessUsecase) FindAll(ctx *gin.Context, filterFindAllParams models.FindAllBusinessParams) ([]*models.Business, *types.Error) {
+	result, err := u.businessRepo.FindAll(ctx, filterFindAllParams)
+	if err != nil {
+		err.Path = ".BusinessUsecase->FindAll()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *BusinessUsecase) Find(ctx *gin.Context, id string) (*models.Business, *types.Error) {
+	result, err := u.businessRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".BusinessUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *BusinessUsecase) Count(ctx *gin.Context, filterFindAllParams models.FindAllBusinessParams) (int, *types.Error) {
+	result, err := u.businessRepo.FindAll(ctx, filterFindAllParams)
+	if err != nil {
+		err.Path = ".BusinessUsecase->Count()" + err.Path
+		return 0, err
+	}
+
+	return len(result), nil
+}
+
+func (u *BusinessUsecase) Create(ctx *gin.Context, obj models.Business) (*models.Business, *types.Error) {
+	errValidation := validator.Shared().StructCtx(ctx, obj)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".BusinessUsecase->Create()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	data := models.Business{
+		ID:       uuid.New().String(),
+		Name:     obj.Name,
+		StatusID: models.DEFAULT_STATUS_ID,
+	}
+
+	result, err := u.businessRepo.Create(ctx, &data)
+	if err != nil {
+		err.Path = ".BusinessUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *BusinessUsecase) Update(ctx *gin.Context, id string, obj models.Business) (*models.Business, *types.Error) {
+	errValidation := validator.Shared().StructCtx(ctx, obj)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".BusinessUsecase->Update()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	data, err := u.businessRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".BusinessUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	data.Name = obj.Name
+
+	result, err := u.businessRepo.Update(ctx, data)
+	if err != nil {
+		err.Path = ".BusinessUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	return result, err
+}
+
+func (u *BusinessUsecase) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	result, err := u.businessRepo.FindStatus(ctx)
+	if err != nil {
+		err.Path = ".BusinessUsecase->FindStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *BusinessUsecase) UpdateStatus(ctx *gin.Context, id string, newStatusID string) (*models.Business, *types.Error) {
+	result, err := u.businessRepo.UpdateStatus(ctx, id, newStatusID)
+	if err != nil {
+		err.Path = ".BusinessUsecase->UpdateStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, err
+}
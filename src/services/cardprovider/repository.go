@@ -0,0 +1,22 @@
+package cardprovider
+
+import (
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Repository is the contract between Repository and usecase
+type Repository interface {
+	FindAll(*gin.Context, models.FindAllCardProviderParams) ([]*models.CardProvider, *types.Error)
+	Find(*gin.Context, string) (*models.CardProvider, *types.Error)
+	Create(*gin.Context, *models.CardProvider) (*models.CardProvider, *types.Error)
+	Update(*gin.Context, *models.CardProvider) (*models.CardProvider, *types.Error)
+
+	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	UpdateStatus(*gin.Context, string, string) (*models.CardProvider, *types.Error)
+
+	FindBanks(*gin.Context, []string) (map[string][]models.IDNameTemplate, *types.Error)
+	UpdateBanks(*gin.Context, string, []string) *types.Error
+}
@@ -0,0 +1,368 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CardProviderRepository initialize object from model CardProvider, to be used in database operation
+type CardProviderRepository struct {
+	repository                 data.GenericStorage
+	cardProviderBankRepository data.GenericStorage
+	statusRepository           data.GenericStorage
+}
+
+// NewCardProviderRepository initialize service that provide connection to Database
+func NewCardProviderRepository(repository data.GenericStorage, cardProviderBankRepository data.GenericStorage, statusRepository data.GenericStorage) CardProviderRepository {
+	return CardProviderRepository{repository: repository, cardProviderBankRepository: cardProviderBankRepository, statusRepository: statusRepository}
+}
+
+// FindAll is a function to get all Data
+func (s CardProviderRepository) FindAll(ctx *gin.Context, params models.FindAllCardProviderParams) ([]*models.CardProvider, *types.Error) {
+	data := []*models.CardProvider{}
+	bulks := []*models.CardProviderBulk{}
+
+	var err error
+
+	where := `true`
+
+	if params.FindAllParams.DataFinder != "" {
+		where = fmt.Sprintf("%s AND %s", where, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where = fmt.Sprintf("%s AND card_providers.%s", where, params.FindAllParams.StatusID)
+	}
+
+	if params.FindAllParams.SortBy != "" {
+		where = fmt.Sprintf("%s ORDER BY %s", where, params.FindAllParams.SortBy)
+	}
+
+	if params.FindAllParams.Page > 0 && params.FindAllParams.Size > 0 {
+		where = fmt.Sprintf(`%s LIMIT :limit OFFSET :offset`, where)
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    card_providers.id, card_providers.name,
+    card_providers.status_id, status.name status_name
+  FROM card_providers
+  JOIN status ON card_providers.status_id = status.id
+  WHERE %s
+  `, where)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"limit":     params.FindAllParams.Size,
+		"offset":    ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+		"status_id": params.FindAllParams.StatusID,
+	})
+
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".CardProviderStorage->FindAll()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		obj := &models.CardProvider{
+			ID:       v.ID,
+			Name:     v.Name,
+			StatusID: v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+		}
+
+		data = append(data, obj)
+	}
+
+	return data, nil
+}
+
+// Find is a function to get by ID
+func (s CardProviderRepository) Find(ctx *gin.Context, id string) (*models.CardProvider, *types.Error) {
+	result := models.CardProvider{}
+	bulks := []*models.CardProviderBulk{}
+	var err error
+
+	query := fmt.Sprintf(`
+  SELECT card_providers.id, card_providers.name,
+  card_providers.status_id, status.name status_name
+  FROM card_providers
+  JOIN status ON card_providers.status_id = status.id
+  WHERE card_providers.id = :id`)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"id": id,
+	})
+
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".CardProviderStorage->Find()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(bulks) > 0 {
+		v := bulks[0]
+		result = models.CardProvider{
+			ID:       v.ID,
+			Name:     v.Name,
+			StatusID: v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+		}
+	} else {
+		return nil, &types.Error{
+			Path:       ".CardProviderStorage->Find()",
+			Message:    "Data Not Found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &result, nil
+}
+
+// Create is a function to get by ID
+func (s CardProviderRepository) Create(ctx *gin.Context, obj *models.CardProvider) (*models.CardProvider, *types.Error) {
+	data := models.CardProvider{}
+	_, err := s.repository.Insert(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".CardProviderStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".CardProviderStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+// Update is a function to get by ID
+func (s CardProviderRepository) Update(ctx *gin.Context, obj *models.CardProvider) (*models.CardProvider, *types.Error) {
+	data := models.CardProvider{}
+	err := s.repository.Update(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".CardProviderStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".CardProviderStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+// FindStatus is a function to get by ID
+func (s CardProviderRepository) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	businessStatus := []*models.Status{}
+
+	err := s.statusRepository.Where(ctx, &businessStatus, "1=1", map[string]interface{}{})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".CardProviderStorage->FindStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return businessStatus, nil
+}
+
+// UpdateStatus is a function to get by ID
+func (s CardProviderRepository) UpdateStatus(ctx *gin.Context, id string, statusID string) (*models.CardProvider, *types.Error) {
+	data := models.CardProvider{}
+	err := s.repository.UpdateStatus(ctx, id, statusID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".CardProviderStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, id)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".CardProviderStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &data, nil
+}
+
+// FindBanks is a function to get the banks linked to each card provider
+func (s CardProviderRepository) FindBanks(ctx *gin.Context, cardProviderIDs []string) (map[string][]models.IDNameTemplate, *types.Error) {
+	result := map[string][]models.IDNameTemplate{}
+	if len(cardProviderIDs) == 0 {
+		return result, nil
+	}
+
+	bulks := []*models.CardProviderBankBulk{}
+
+	query := `
+  SELECT card_provider_banks.card_provider_id, card_provider_banks.bank_id,
+  banks.name bank_name
+  FROM card_provider_banks
+  JOIN banks ON card_provider_banks.bank_id = banks.id
+  WHERE card_provider_banks.card_provider_id IN (:card_provider_ids)
+  ORDER BY banks.name`
+
+	err := s.cardProviderBankRepository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"card_provider_ids": cardProviderIDs,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".CardProviderStorage->FindBanks()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		result[v.CardProviderID] = append(result[v.CardProviderID], models.IDNameTemplate{
+			ID:   v.BankID,
+			Name: v.BankName,
+		})
+	}
+
+	return result, nil
+}
+
+// UpdateBanks is a function to replace the banks linked to a card provider
+func (s CardProviderRepository) UpdateBanks(ctx *gin.Context, cardProviderID string, bankIDs []string) *types.Error {
+	// a bank given twice is linked once
+	bankIDs = uniqueIDs(bankIDs)
+
+	if len(bankIDs) > 0 {
+		var count int
+		err := s.cardProviderBankRepository.SelectFirstWithQuery(ctx, &count, `SELECT COUNT(DISTINCT id) FROM banks WHERE id IN (:bank_ids)`, map[string]interface{}{
+			"bank_ids": bankIDs,
+		})
+		if err != nil {
+			return &types.Error{
+				Path:       ".CardProviderStorage->UpdateBanks()",
+				Message:    err.Error(),
+				Error:      err,
+				StatusCode: http.StatusInternalServerError,
+				Type:       "mysql-error",
+			}
+		}
+
+		if count != len(bankIDs) {
+			return &types.Error{
+				Path:       ".CardProviderStorage->UpdateBanks()",
+				Message:    "Bank Not Found",
+				Error:      data.ErrNotFound,
+				StatusCode: http.StatusUnprocessableEntity,
+				Type:       "mysql-error",
+			}
+		}
+	}
+
+	err := s.cardProviderBankRepository.ExecQuery(ctx, `DELETE FROM card_provider_banks WHERE card_provider_id = :card_provider_id`, map[string]interface{}{
+		"card_provider_id": cardProviderID,
+	})
+	if err != nil {
+		return &types.Error{
+			Path:       ".CardProviderStorage->UpdateBanks()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(bankIDs) == 0 {
+		return nil
+	}
+
+	links := []models.CardProviderBank{}
+	for _, bankID := range bankIDs {
+		links = append(links, models.CardProviderBank{
+			ID:             uuid.New().String(),
+			CardProviderID: cardProviderID,
+			BankID:         bankID,
+		})
+	}
+
+	err = s.cardProviderBankRepository.InsertMany(ctx, links)
+	if err != nil {
+		return &types.Error{
+			Path:       ".CardProviderStorage->UpdateBanks()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
+
+// uniqueIDs returns the ids without the repeated ones, in the order they are first given
+func uniqueIDs(ids []string) []string {
+	seen := map[string]bool{}
+	res := []string{}
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		res = append(res, id)
+	}
+	return res
+}
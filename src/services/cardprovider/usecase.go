@@ -0,0 +1,20 @@
+package cardprovider
+
+import (
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Usecase is the contract between Repository and usecase
+type Usecase interface {
+	FindAll(*gin.Context, models.FindAllCardProviderParams) ([]*models.CardProvider, *types.Error)
+	Find(*gin.Context, string) (*models.CardProvider, *types.Error)
+	Count(*gin.Context, models.FindAllCardProviderParams) (int, *types.Error)
+	Create(*gin.Context, models.CardProvider) (*models.CardProvider, *types.Error)
+	Update(*gin.Context, string, models.CardProvider) (*models.CardProvider, *types.Error)
+
+	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	UpdateStatus(*gin.Context, string, string) (*models.CardProvider, *types.Error)
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"net/http"
+	"time"
+
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/library/validator"
+	"luxe-beb-go/src/services/cardprovider"
+
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type CardProviderUsecase struct {
+	cardProviderRepo cardprovider.Repository
+	contextTimeout   time.Duration
+	db               *sqlx.DB
+}
+
+func NewCardProviderUsecase(db *sqlx.DB, cardProviderRepo cardprovider.Repository) cardprovider.Usecase {
+	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+
+	return &CardProviderUsecase{
+		cardProviderRepo: cardProviderRepo,
+		contextTimeout:   timeoutContext,
+		db:               db,
+	}
+}
+
+func (u *CardProviderUsecase) FindAll(ctx *gin.Context, filterFindAllParams models.FindAllCardProviderParams) ([]*models.CardProvider, *types.Error) {
+	result, err := u.cardProviderRepo.FindAll(ctx, filterFindAllParams)
+	if err != nil {
+		err.Path = ".CardProviderUsecase->FindAll()" + err.Path
+		return nil, err
+	}
+
+	err = u.attachBanks(ctx, result...)
+	if err != nil {
+		err.Path = ".CardProviderUsecase->FindAll()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *CardProviderUsecase) Find(ctx *gin.Context, id string) (*models.CardProvider, *types.Error) {
+	result, err := u.cardProviderRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".CardProviderUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	err = u.attachBanks(ctx, result)
+	if err != nil {
+		err.Path = ".CardProviderUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *CardProviderUsecase) Count(ctx *gin.Context, filterFindAllParams models.FindAllCardProviderParams) (int, *types.Error) {
+	result, err := u.cardProviderRepo.FindAll(ctx, filterFindAllParams)
+	if err != nil {
+		err.Path = ".CardProviderUsecase->Count()" + err.Path
+		return 0, err
+	}
+
+	return len(result), nil
+}
+
+func (u *CardProviderUsecase) Create(ctx *gin.Context, obj models.CardProvider) (*models.CardProvider, *types.Error) {
+	errValidation := validator.Shared().StructCtx(ctx, obj)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".CardProviderUsecase->Create()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	data := models.CardProvider{
+		ID:       uuid.New().String(),
+		Name:     obj.Name,
+		StatusID: models.DEFAULT_STATUS_ID,
+	}
+
+	result, err := u.cardProviderRepo.Create(ctx, &data)
+	if err != nil {
+		err.Path = ".CardProviderUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	err = u.cardProviderRepo.UpdateBanks(ctx, result.ID, bankIDs(obj.Banks))
+	if err != nil {
+		err.Path = ".CardProviderUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	err = u.attachBanks(ctx, result)
+	if err != nil {
+		err.Path = ".CardProviderUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *CardProviderUsecase) Update(ctx *gin.Context, id string, obj models.CardProvider) (*models.CardProvider, *types.Error) {
+	errValidation := validator.Shared().StructCtx(ctx, obj)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".CardProviderUsecase->Update()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	data, err := u.cardProviderRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".CardProviderUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	data.Name = obj.Name
+
+	result, err := u.cardProviderRepo.Update(ctx, data)
+	if err != nil {
+		err.Path = ".CardProviderUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	err = u.cardProviderRepo.UpdateBanks(ctx, result.ID, bankIDs(obj.Banks))
+	if err != nil {
+		err.Path = ".CardProviderUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	err = u.attachBanks(ctx, result)
+	if err != nil {
+		err.Path = ".CardProviderUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	return result, err
+}
+
+func (u *CardProviderUsecase) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	result, err := u.cardProviderRepo.FindStatus(ctx)
+	if err != nil {
+		err.Path = ".CardProviderUsecase->FindStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *CardProviderUsecase) UpdateStatus(ctx *gin.Context, id string, newStatusID string) (*models.CardProvider, *types.Error) {
+	result, err := u.cardProviderRepo.UpdateStatus(ctx, id, newStatusID)
+	if err != nil {
+		err.Path = ".CardProviderUsecase->UpdateStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, err
+}
+
+// attachBanks fills the banks linked to each card provider
+func (u *CardProviderUsecase) attachBanks(ctx *gin.Context, cardProviders ...*models.CardProvider) *types.Error {
+	ids := []string{}
+	for _, v := range cardProviders {
+		ids = append(ids, v.ID)
+	}
+
+	banks, err := u.cardProviderRepo.FindBanks(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range cardProviders {
+		v.Banks = banks[v.ID]
+		if v.Banks == nil {
+			v.Banks = []models.IDNameTemplate{}
+		}
+	}
+
+	return nil
+}
+
+func bankIDs(banks []models.IDNameTemplate) []string {
+	ids := []string{}
+	for _, v := range banks {
+		ids = append(ids, v.ID)
+	}
+	return ids
+}
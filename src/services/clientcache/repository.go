@@ -0,0 +1,10 @@
+package clientcache
+
+import (
+	"luxe-beb-go/library/client"
+)
+
+// Repository is the contract between Repository and usecase
+type Repository interface {
+	client.ClientCacheStorage
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"luxe-beb-go/library"
+	"luxe-beb-go/library/client"
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClientCacheRepository initialize object from model ClientCache, to be used in database operation
+type ClientCacheRepository struct {
+	repository data.GenericStorage
+}
+
+// NewClientCacheRepository initialize service that provide connection to Database
+func NewClientCacheRepository(repository data.GenericStorage) ClientCacheRepository {
+	return ClientCacheRepository{repository: repository}
+}
+
+// FindAll is a function to get all Data, the response is left out
+func (s ClientCacheRepository) FindAll(ctx *gin.Context, params *client.FindAllClientCachesParams) ([]*client.ClientCache, *types.Error) {
+	result := []*client.ClientCache{}
+
+	where, args := clientCacheFilter(params)
+
+	where = fmt.Sprintf("%s ORDER BY lastAccessed DESC, id DESC", where)
+
+	if params.Page > 0 && params.Limit > 0 {
+		where = fmt.Sprintf(`%s LIMIT :limit OFFSET :offset`, where)
+	}
+	args["limit"] = params.Limit
+	args["offset"] = ((params.Page - 1) * params.Limit)
+
+	query := fmt.Sprintf(`
+  SELECT id, url, method, clientId, clientName, '{}' AS response, lastAccessed
+  FROM client_caches
+  WHERE %s
+  `, where)
+
+	err := s.repository.SelectWithQuery(ctx, &result, query, args)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ClientCacheStorage->FindAll()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return result, nil
+}
+
+// FindByID is a function to get by ID
+func (s ClientCacheRepository) FindByID(ctx *gin.Context, clientCacheID int) (*client.ClientCache, *types.Error) {
+	result := client.ClientCache{}
+
+	err := s.repository.FindByID(ctx, &result, clientCacheID)
+	if err != nil {
+		return nil, notFoundOr(".ClientCacheStorage->FindByID()", err)
+	}
+
+	return &result, nil
+}
+
+// FindByURL is a function to get the latest cache of the url, a cache older than bufferedTime seconds is not found.
+// A bufferedTime of zero keeps the cache until it is purged
+func (s ClientCacheRepository) FindByURL(ctx *gin.Context, url string, method string, bufferedTime *int) (*client.ClientCache, *types.Error) {
+	result := client.ClientCache{}
+
+	where := `url = :url AND method = :method`
+	if bufferedTime != nil && *bufferedTime > 0 {
+		where = fmt.Sprintf("%s AND lastAccessed >= :expiredAt", where)
+	}
+
+	err := s.repository.SelectFirstWithQuery(ctx, &result, fmt.Sprintf(`
+  SELECT id, url, method, clientId, clientName, response, lastAccessed
+  FROM client_caches
+  WHERE %s
+  ORDER BY lastAccessed DESC
+  LIMIT 1`, where), map[string]interface{}{
+		"url":       url,
+		"method":    method,
+		"expiredAt": library.UTCPlus7().Add(-time.Duration(intValue(bufferedTime)) * time.Second).Format("2006-01-02 15:04:05"),
+	})
+	if err != nil {
+		return nil, notFoundOr(".ClientCacheStorage->FindByURL()", err)
+	}
+
+	return &result, nil
+}
+
+// Insert is a function to insert a cache, the id is given by the auto increment
+func (s ClientCacheRepository) Insert(ctx *gin.Context, clientCache *client.ClientCache) (*client.ClientCache, *types.Error) {
+	result, err := s.repository.InsertNoTrail(ctx, clientCache)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ClientCacheStorage->Insert()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	id, err := (*result).LastInsertId()
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ClientCacheStorage->Insert()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	clientCache.ID = int(id)
+
+	return clientCache, nil
+}
+
+// Update is a function to update a cache
+func (s ClientCacheRepository) Update(ctx *gin.Context, clientCache *client.ClientCache) (*client.ClientCache, *types.Error) {
+	err := s.repository.UpdateNoTrail(ctx, clientCache)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ClientCacheStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return clientCache, nil
+}
+
+// Delete is a function to delete a cache
+func (s ClientCacheRepository) Delete(ctx *gin.Context, clientCache *client.ClientCache) *types.Error {
+	err := s.repository.HardDelete(ctx, clientCache.ID)
+	if err != nil {
+		return &types.Error{
+			Path:       ".ClientCacheStorage->Delete()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
+
+// Purge is a function to delete every cache matching the params, it returns the number of caches deleted
+func (s ClientCacheRepository) Purge(ctx *gin.Context, params *client.FindAllClientCachesParams) (int, *types.Error) {
+	count := 0
+	where, args := clientCacheFilter(params)
+
+	err := s.repository.SelectFirstWithQuery(ctx, &count, fmt.Sprintf(`SELECT COUNT(*) FROM client_caches WHERE %s`, where), args)
+	if err == nil {
+		err = s.repository.ExecQuery(ctx, fmt.Sprintf(`DELETE FROM client_caches WHERE %s`, where), args)
+	}
+	if err != nil {
+		return 0, &types.Error{
+			Path:       ".ClientCacheStorage->Purge()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return count, nil
+}
+
+func clientCacheFilter(params *client.FindAllClientCachesParams) (string, map[string]interface{}) {
+	where := `true`
+
+	if params.Search != "" {
+		where = fmt.Sprintf("%s AND (url LIKE :search OR clientName LIKE :search)", where)
+	}
+
+	if params.Method != "" {
+		where = fmt.Sprintf("%s AND method = :method", where)
+	}
+
+	if params.ClientName != "" {
+		where = fmt.Sprintf("%s AND clientName = :clientName", where)
+	}
+
+	if params.URL != "" {
+		where = fmt.Sprintf("%s AND LEFT(url, CHAR_LENGTH(:url)) = :url", where)
+	}
+
+	return where, map[string]interface{}{
+		"search":     "%" + params.Search + "%",
+		"method":     params.Method,
+		"clientName": params.ClientName,
+		"url":        params.URL,
+	}
+}
+
+func intValue(v *int) int {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+// notFoundOr keeps the message of data.ErrNotFound, the client cache service tells a missing cache by it
+func notFoundOr(path string, err error) *types.Error {
+	if err == data.ErrNotFound {
+		return &types.Error{
+			Path:       path,
+			Message:    data.ErrNotFound.Error(),
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &types.Error{
+		Path:       path,
+		Message:    err.Error(),
+		Error:      err,
+		StatusCode: http.StatusInternalServerError,
+		Type:       "mysql-error",
+	}
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+
+	"luxe-beb-go/library/client"
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClientRequestLogRepository initialize object from model ClientRequestLog, to be used in database operation
+type ClientRequestLogRepository struct {
+	repository data.GenericStorage
+}
+
+// NewClientRequestLogRepository initialize service that provide connection to Database
+func NewClientRequestLogRepository(repository data.GenericStorage) ClientRequestLogRepository {
+	return ClientRequestLogRepository{repository: repository}
+}
+
+// FindAll is a function to get all Data, the newest first
+func (s ClientRequestLogRepository) FindAll(ctx *gin.Context, params *client.FindAllClientRequestLogs) ([]*client.ClientRequestLog, *types.Error) {
+	result := []*client.ClientRequestLog{}
+
+	where := `true`
+
+	if params.Search != "" {
+		where = fmt.Sprintf("%s AND (url LIKE :search OR request LIKE :search OR response LIKE :search)", where)
+	}
+
+	if params.ClientName != "" {
+		where = fmt.Sprintf("%s AND clientName = :clientName", where)
+	}
+
+	if params.Status != "" {
+		where = fmt.Sprintf("%s AND status = :status", where)
+	}
+
+	if params.CorrelationID != "" {
+		where = fmt.Sprintf("%s AND correlationId = :correlationId", where)
+	}
+
+	if params.IsUnacknowledged {
+		where = fmt.Sprintf("%s AND isAcknowledgeNeeded = 1 AND isAcknowledged = 0", where)
+	}
+
+	where = fmt.Sprintf("%s ORDER BY created_at DESC, id DESC", where)
+
+	if params.Page > 0 && params.Limit > 0 {
+		where = fmt.Sprintf(`%s LIMIT :limit OFFSET :offset`, where)
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    id, businessId, clientId, clientName, clientType, transactionId, correlationId, method, url,
+    COALESCE(header, '') AS header,
+    COALESCE(request, '') AS request,
+    COALESCE(response, '') AS response,
+    status, httpStatusCode, latencyMs,
+    COALESCE(error, '') AS error,
+    referenceId, isAcknowledgeNeeded, isAcknowledged, idempotencyKey, attempts,
+    nextAttemptAt, created_at
+  FROM client_request_logs
+  WHERE %s
+  `, where)
+
+	err := s.repository.SelectWithQuery(ctx, &result, query, map[string]interface{}{
+		"limit":         params.Limit,
+		"offset":        ((params.Page - 1) * params.Limit),
+		"search":        "%" + params.Search + "%",
+		"clientName":    params.ClientName,
+		"status":        params.Status,
+		"correlationId": params.CorrelationID,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ClientRequestLogStorage->FindAll()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return result, nil
+}
+
+// FindByID is a function to get by ID
+func (s ClientRequestLogRepository) FindByID(ctx *gin.Context, clientRequestLogID int) (*client.ClientRequestLog, *types.Error) {
+	result := client.ClientRequestLog{}
+
+	err := s.repository.SelectFirstWithQuery(ctx, &result, `
+  SELECT
+    id, businessId, clientId, clientName, clientType, transactionId, correlationId, method, url,
+    COALESCE(header, '') AS header,
+    COALESCE(request, '') AS request,
+    COALESCE(response, '') AS response,
+    status, httpStatusCode, latencyMs,
+    COALESCE(error, '') AS error,
+    referenceId, isAcknowledgeNeeded, isAcknowledged, idempotencyKey, attempts,
+    nextAttemptAt, created_at
+  FROM client_request_logs
+  WHERE id = :id`, map[string]interface{}{
+		"id": clientRequestLogID,
+	})
+	if err != nil {
+		if err == data.ErrNotFound {
+			return nil, &types.Error{
+				Path:       ".ClientRequestLogStorage->FindByID()",
+				Message:    "Data Not Found",
+				Error:      data.ErrNotFound,
+				StatusCode: http.StatusNotFound,
+				Type:       "mysql-error",
+			}
+		}
+
+		return nil, &types.Error{
+			Path:       ".ClientRequestLogStorage->FindByID()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &result, nil
+}
+
+// Insert is a function to insert a log, the id is given by the auto increment
+func (s ClientRequestLogRepository) Insert(ctx *gin.Context, clientRequestLog *client.ClientRequestLog) (*client.ClientRequestLog, *types.Error) {
+	result, err := s.repository.InsertNoTrail(ctx, clientRequestLog)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ClientRequestLogStorage->Insert()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	id, err := (*result).LastInsertId()
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ClientRequestLogStorage->Insert()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	clientRequestLog.ID = int(id)
+
+	return clientRequestLog, nil
+}
+
+// Update is a function to update a log
+func (s ClientRequestLogRepository) Update(ctx *gin.Context, clientRequestLog *client.ClientRequestLog) (*client.ClientRequestLog, *types.Error) {
+	err := s.repository.UpdateNoTrail(ctx, clientRequestLog)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ClientRequestLogStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return clientRequestLog, nil
+}
+
+// Delete is a function to delete a log
+func (s ClientRequestLogRepository) Delete(ctx *gin.Context, clientRequestLogID int) *types.Error {
+	err := s.repository.HardDelete(ctx, clientRequestLogID)
+	if err != nil {
+		return &types.Error{
+			Path:       ".ClientRequestLogStorage->Delete()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
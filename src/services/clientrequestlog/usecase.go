@@ -0,0 +1,15 @@
+package clientrequestlog
+
+import (
+	"luxe-beb-go/library/client"
+	"luxe-beb-go/library/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Usecase is the contract between Repository and usecase
+type Usecase interface {
+	FindAll(*gin.Context, client.FindAllClientRequestLogs) ([]*client.ClientRequestLog, *types.Error)
+	Find(*gin.Context, int) (*client.ClientRequestLog, *types.Error)
+	Count(*gin.Context, client.FindAllClientRequestLogs) (int, *types.Error)
+}
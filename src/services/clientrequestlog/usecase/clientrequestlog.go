@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"time"
+
+	"luxe-beb-go/library/client"
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/src/services/clientrequestlog"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type ClientRequestLogUsecase struct {
+	clientRequestLogRepo clientrequestlog.Repository
+	contextTimeout       time.Duration
+	db                   *sqlx.DB
+}
+
+func NewClientRequestLogUsecase(db *sqlx.DB, clientRequestLogRepo clientrequestlog.Repository) clientrequestlog.Usecase {
+	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+
+	return &ClientRequestLogUsecase{
+		clientRequestLogRepo: clientRequestLogRepo,
+		contextTimeout:       timeoutContext,
+		db:                   db,
+	}
+}
+
+func (u *ClientRequestLogUsecase) FindAll(ctx *gin.Context, params client.FindAllClientRequestLogs) ([]*client.ClientRequestLog, *types.Error) {
+	result, err := u.clientRequestLogRepo.FindAll(ctx, &params)
+	if err != nil {
+		err.Path = ".ClientRequestLogUsecase->FindAll()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *ClientRequestLogUsecase) Find(ctx *gin.Context, id int) (*client.ClientRequestLog, *types.Error) {
+	result, err := u.clientRequestLogRepo.FindByID(ctx, id)
+	if err != nil {
+		err.Path = ".ClientRequestLogUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *ClientRequestLogUsecase) Count(ctx *gin.Context, params client.FindAllClientRequestLogs) (int, *types.Error) {
+	params.Page = 0
+	params.Limit = 0
+	result, err := u.clientRequestLogRepo.FindAll(ctx, &params)
+	if err != nil {
+		err.Path = ".ClientRequestLogUsecase->Count()" + err.Path
+		return 0, err
+	}
+
+	return len(result), nil
+}
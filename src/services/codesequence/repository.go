@@ -0,0 +1,17 @@
+package codesequence
+
+import (
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Repository is the contract between Repository and usecase
+type Repository interface {
+	FindAll(*gin.Context, models.FindAllCodeSequenceParams) ([]*models.CodeSequence, *types.Error)
+	Find(*gin.Context, string) (*models.CodeSequence, *types.Error)
+	FindForUpdate(*gin.Context, string) (*models.CodeSequence, *types.Error)
+	Create(*gin.Context, *models.CodeSequence) (*models.CodeSequence, *types.Error)
+	Update(*gin.Context, *models.CodeSequence) (*models.CodeSequence, *types.Error)
+}
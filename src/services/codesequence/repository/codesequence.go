@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CodeSequenceRepository initialize object from model CodeSequence, to be used in database operation
+type CodeSequenceRepository struct {
+	repository data.GenericStorage
+}
+
+// NewCodeSequenceRepository initialize service that provide connection to Database
+func NewCodeSequenceRepository(repository data.GenericStorage) CodeSequenceRepository {
+	return CodeSequenceRepository{repository: repository}
+}
+
+// FindAll is a function to get all Data
+func (s CodeSequenceRepository) FindAll(ctx *gin.Context, params models.FindAllCodeSequenceParams) ([]*models.CodeSequence, *types.Error) {
+	data := []*models.CodeSequence{}
+
+	where := `true`
+
+	if params.FindAllParams.DataFinder != "" {
+		where = fmt.Sprintf("%s AND %s", where, params.FindAllParams.DataFinder)
+	}
+
+	if params.Prefix != "" {
+		where = fmt.Sprintf("%s AND code_sequences.prefix = :prefix", where)
+	}
+
+	if params.Year != "" {
+		where = fmt.Sprintf("%s AND code_sequences.year = :year", where)
+	}
+
+	if params.FindAllParams.SortBy != "" {
+		where = fmt.Sprintf("%s ORDER BY %s", where, params.FindAllParams.SortBy)
+	}
+
+	if params.FindAllParams.Page > 0 && params.FindAllParams.Size > 0 {
+		where = fmt.Sprintf(`%s LIMIT :limit OFFSET :offset`, where)
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    code_sequences.id, code_sequences.business_id, code_sequences.prefix, code_sequences.name,
+    code_sequences.year, code_sequences.sequence,
+    code_sequences.format, code_sequences.reset_yearly
+  FROM code_sequences
+  WHERE %s
+  `, where)
+
+	err := s.repository.SelectWithQuery(ctx, &data, query, map[string]interface{}{
+		"limit":  params.FindAllParams.Size,
+		"offset": ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+		"prefix": params.Prefix,
+		"year":   params.Year,
+	})
+
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".CodeSequenceStorage->FindAll()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return data, nil
+}
+
+// Find is a function to get by ID
+func (s CodeSequenceRepository) Find(ctx *gin.Context, id string) (*models.CodeSequence, *types.Error) {
+	result := models.CodeSequence{}
+
+	err := s.repository.FindByID(ctx, &result, id)
+	if err != nil {
+		if err == data.ErrNotFound {
+			return nil, &types.Error{
+				Path:       ".CodeSequenceStorage->Find()",
+				Message:    "Data Not Found",
+				Error:      data.ErrNotFound,
+				StatusCode: http.StatusNotFound,
+				Type:       "mysql-error",
+			}
+		}
+
+		return nil, &types.Error{
+			Path:       ".CodeSequenceStorage->Find()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &result, nil
+}
+
+// FindForUpdate is a function to get by ID with the row locked until the transaction of the context ends,
+// as SequenceService.Next locks it, so the sequence does not move meanwhile
+func (s CodeSequenceRepository) FindForUpdate(ctx *gin.Context, id string) (*models.CodeSequence, *types.Error) {
+	result := models.CodeSequence{}
+
+	if _, ok := data.TxFromContext(ctx); !ok {
+		return nil, &types.Error{
+			Path:       ".CodeSequenceStorage->FindForUpdate()",
+			Message:    data.ErrNoTransaction.Error(),
+			Error:      data.ErrNoTransaction,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "golang-error",
+		}
+	}
+
+	err := s.repository.SelectFirstWithQuery(ctx, &result, `
+  SELECT
+    code_sequences.id, code_sequences.business_id, code_sequences.prefix, code_sequences.name,
+    code_sequences.year, code_sequences.sequence,
+    code_sequences.format, code_sequences.reset_yearly
+  FROM code_sequences
+  WHERE code_sequences.id = :id
+  FOR UPDATE`, map[string]interface{}{
+		"id": id,
+	})
+	if err != nil {
+		if err == data.ErrNotFound {
+			return nil, &types.Error{
+				Path:       ".CodeSequenceStorage->FindForUpdate()",
+				Message:    "Data Not Found",
+				Error:      data.ErrNotFound,
+				StatusCode: http.StatusNotFound,
+				Type:       "mysql-error",
+			}
+		}
+
+		return nil, &types.Error{
+			Path:       ".CodeSequenceStorage->FindForUpdate()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &result, nil
+}
+
+// Create is a function to get by ID
+func (s CodeSequenceRepository) Create(ctx *gin.Context, obj *models.CodeSequence) (*models.CodeSequence, *types.Error) {
+	data := models.CodeSequence{}
+	_, err := s.repository.Insert(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".CodeSequenceStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".CodeSequenceStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+// Update is a function to get by ID
+func (s CodeSequenceRepository) Update(ctx *gin.Context, obj *models.CodeSequence) (*models.CodeSequence, *types.Error) {
+	data := models.CodeSequence{}
+	err := s.repository.Update(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".CodeSequenceStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".CodeSequenceStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
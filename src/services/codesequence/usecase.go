@@ -0,0 +1,18 @@
+package codesequence
+
+import (
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Usecase is the contract between Repository and usecase
+type Usecase interface {
+	FindAll(*gin.Context, models.FindAllCodeSequenceParams) ([]*models.CodeSequence, *types.Error)
+	Find(*gin.Context, string) (*models.CodeSequence, *types.Error)
+	Count(*gin.Context, models.FindAllCodeSequenceParams) (int, *types.Error)
+	Create(*gin.Context, models.CodeSequence) (*models.CodeSequence, *types.Error)
+	Update(*gin.Context, string, models.CodeSequenceUpdateRequest) (*models.CodeSequence, *types.Error)
+	Next(*gin.Context, string) (*models.CodeSequenceCode, *types.Error)
+}
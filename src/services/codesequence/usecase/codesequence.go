@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"luxe-beb-go/library"
+	"luxe-beb-go/library/appcontext"
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/library/validator"
+	"luxe-beb-go/src/services/codesequence"
+
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type CodeSequenceUsecase struct {
+	codeSequenceRepo codesequence.Repository
+	sequenceService  *data.SequenceService
+	contextTimeout   time.Duration
+	db               *sqlx.DB
+}
+
+func NewCodeSequenceUsecase(db *sqlx.DB, codeSequenceRepo codesequence.Repository) codesequence.Usecase {
+	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+
+	return &CodeSequenceUsecase{
+		codeSequenceRepo: codeSequenceRepo,
+		sequenceService:  data.NewSequenceService(db),
+		contextTimeout:   timeoutContext,
+		db:               db,
+	}
+}
+
+func (u *CodeSequenceUsecase) FindAll(ctx *gin.Context, params models.FindAllCodeSequenceParams) ([]*models.CodeSequence, *types.Error) {
+	result, err := u.codeSequenceRepo.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".CodeSequenceUsecase->FindAll()" + err.Path
+		return nil, err
+	}
+
+	for _, v := range result {
+		fillNextCode(v)
+	}
+
+	return result, nil
+}
+
+func (u *CodeSequenceUsecase) Find(ctx *gin.Context, id string) (*models.CodeSequence, *types.Error) {
+	result, err := u.codeSequenceRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".CodeSequenceUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	fillNextCode(result)
+
+	return result, nil
+}
+
+func (u *CodeSequenceUsecase) Count(ctx *gin.Context, params models.FindAllCodeSequenceParams) (int, *types.Error) {
+	result, err := u.codeSequenceRepo.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".CodeSequenceUsecase->Count()" + err.Path
+		return 0, err
+	}
+
+	return len(result), nil
+}
+
+func (u *CodeSequenceUsecase) Create(ctx *gin.Context, obj models.CodeSequence) (*models.CodeSequence, *types.Error) {
+	format := obj.Format
+	if format == "" {
+		format = data.DefaultSequenceFormat
+	}
+
+	// the sequence belongs to the current business, a shared sequence has no business
+	businessID := appcontext.BusinessID(ctx)
+	if obj.IsShared {
+		if !appcontext.IsSuperAdmin(ctx) {
+			return nil, &types.Error{
+				Path:       ".CodeSequenceUsecase->Create()",
+				Message:    "Code Sequence bersama hanya dapat dibuat oleh super admin",
+				Error:      data.ErrOutOfScope,
+				StatusCode: http.StatusForbidden,
+				Type:       "validation-error",
+			}
+		}
+		businessID = ""
+	} else if businessID == "" {
+		return nil, &types.Error{
+			Path:       ".CodeSequenceUsecase->Create()",
+			Message:    "Business harus dipilih untuk membuat Code Sequence",
+			Error:      fmt.Errorf("no business to create the code sequence for"),
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	data := models.CodeSequence{
+		ID:          uuid.New().String(),
+		BusinessID:  businessID,
+		Prefix:      strings.ToUpper(obj.Prefix),
+		Name:        obj.Name,
+		Year:        library.UTCPlus7().Year(),
+		Sequence:    0,
+		Format:      format,
+		ResetYearly: obj.ResetYearly,
+	}
+
+	errValidation := validator.Shared().StructCtx(ctx, data)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".CodeSequenceUsecase->Create()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	existing, err := u.codeSequenceRepo.FindAll(ctx, models.FindAllCodeSequenceParams{Prefix: data.Prefix})
+	if err != nil {
+		err.Path = ".CodeSequenceUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	// a business may have its own sequence of a shared prefix, it is used instead of the shared one
+	for _, v := range existing {
+		if v.BusinessID != data.BusinessID {
+			continue
+		}
+
+		return nil, &types.Error{
+			Path:       ".CodeSequenceUsecase->Create()",
+			Message:    fmt.Sprintf("Prefix %s sudah digunakan", data.Prefix),
+			Error:      fmt.Errorf("prefix %s already exists", data.Prefix),
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.codeSequenceRepo.Create(ctx, &data)
+	if err != nil {
+		err.Path = ".CodeSequenceUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	fillNextCode(result)
+
+	return result, nil
+}
+
+// Update adjusts the name, format, reset period and last number of the sequence. It runs inside RunInTransaction
+// with the row locked like a reservation does, so no code is given meanwhile. The last number only moves forward,
+// lowering it would give again the codes already given.
+func (u *CodeSequenceUsecase) Update(ctx *gin.Context, id string, obj models.CodeSequenceUpdateRequest) (*models.CodeSequence, *types.Error) {
+	data, err := u.codeSequenceRepo.FindForUpdate(ctx, id)
+	if err != nil {
+		err.Path = ".CodeSequenceUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	if data.BusinessID == "" && !appcontext.IsSuperAdmin(ctx) {
+		return nil, &types.Error{
+			Path:       ".CodeSequenceUsecase->Update()",
+			Message:    "Code Sequence bersama hanya dapat diubah oleh super admin",
+			Error:      fmt.Errorf("shared code sequence %s can only be changed by the super admin", id),
+			StatusCode: http.StatusForbidden,
+			Type:       "validation-error",
+		}
+	}
+
+	data.Name = obj.Name
+	if obj.Format != "" {
+		data.Format = obj.Format
+	}
+	if obj.Sequence != nil {
+		if *obj.Sequence < data.Sequence {
+			return nil, &types.Error{
+				Path:       ".CodeSequenceUsecase->Update()",
+				Message:    fmt.Sprintf("Sequence tidak boleh lebih kecil dari nomor terakhir %d", data.Sequence),
+				Error:      fmt.Errorf("sequence %d is lower than the last number %d", *obj.Sequence, data.Sequence),
+				StatusCode: http.StatusUnprocessableEntity,
+				Type:       "validation-error",
+			}
+		}
+		data.Sequence = *obj.Sequence
+	}
+	if obj.ResetYearly != nil {
+		data.ResetYearly = *obj.ResetYearly
+	}
+
+	errValidation := validator.Shared().StructCtx(ctx, data)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".CodeSequenceUsecase->Update()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.codeSequenceRepo.Update(ctx, data)
+	if err != nil {
+		err.Path = ".CodeSequenceUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	fillNextCode(result)
+
+	return result, err
+}
+
+// Next reserves the next code of the prefix, it has to run inside RunInTransaction
+func (u *CodeSequenceUsecase) Next(ctx *gin.Context, prefix string) (*models.CodeSequenceCode, *types.Error) {
+	prefix = strings.ToUpper(prefix)
+
+	code, errNext := u.sequenceService.Next(ctx, prefix)
+	if errNext != nil {
+		if errNext == data.ErrSequenceNotFound {
+			return nil, &types.Error{
+				Path:       ".CodeSequenceUsecase->Next()",
+				Message:    fmt.Sprintf("Code Sequence %s tidak ditemukan", prefix),
+				Error:      errNext,
+				StatusCode: http.StatusNotFound,
+				Type:       "validation-error",
+			}
+		}
+
+		return nil, &types.Error{
+			Path:       ".CodeSequenceUsecase->Next()",
+			Message:    errNext.Error(),
+			Error:      errNext,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &models.CodeSequenceCode{Prefix: prefix, Code: code}, nil
+}
+
+// fillNextCode previews the code given by the next reservation without reserving it
+func fillNextCode(obj *models.CodeSequence) {
+	now := library.UTCPlus7()
+
+	sequence := obj.Sequence + 1
+	if obj.ResetYearly && obj.Year != now.Year() {
+		sequence = 1
+	}
+
+	obj.IsShared = obj.BusinessID == ""
+	obj.NextCode = data.FormatSequence(obj.Format, obj.Prefix, now, sequence)
+}
@@ -0,0 +1,15 @@
+package emailoutbox
+
+import (
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Repository is the contract between Repository and usecase
+type Repository interface {
+	FindAll(*gin.Context, models.FindAllEmailOutboxParams) ([]*models.EmailOutboxBulk, *types.Error)
+	Find(*gin.Context, string) (*models.EmailOutbox, *types.Error)
+	Resend(*gin.Context, string) *types.Error
+}
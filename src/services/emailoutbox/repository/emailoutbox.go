@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/mail"
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmailOutboxRepository initialize object from model EmailOutbox, to be used in database operation
+type EmailOutboxRepository struct {
+	repository data.GenericStorage
+	outbox     *mail.Outbox
+}
+
+// NewEmailOutboxRepository initialize service that provide connection to Database
+func NewEmailOutboxRepository(repository data.GenericStorage, outbox *mail.Outbox) EmailOutboxRepository {
+	return EmailOutboxRepository{repository: repository, outbox: outbox}
+}
+
+// FindAll is a function to get all Data
+func (s EmailOutboxRepository) FindAll(ctx *gin.Context, params models.FindAllEmailOutboxParams) ([]*models.EmailOutboxBulk, *types.Error) {
+	data := []*models.EmailOutboxBulk{}
+
+	where := `true`
+
+	if params.FindAllParams.DataFinder != "" {
+		where = fmt.Sprintf("%s AND %s", where, params.FindAllParams.DataFinder)
+	}
+
+	if params.Status != "" {
+		where = fmt.Sprintf("%s AND email_outbox.status = :status", where)
+	}
+
+	if params.Email != "" {
+		where = fmt.Sprintf(`%s AND email_outbox.id IN (
+      SELECT outbox_id FROM email_outbox_recipients WHERE email = :email
+    )`, where)
+	}
+
+	if params.FindAllParams.SortBy != "" {
+		where = fmt.Sprintf("%s ORDER BY %s", where, params.FindAllParams.SortBy)
+	}
+
+	if params.FindAllParams.Page > 0 && params.FindAllParams.Size > 0 {
+		where = fmt.Sprintf(`%s LIMIT :limit OFFSET :offset`, where)
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    email_outbox.id, email_outbox.from_email, email_outbox.subject,
+    COALESCE((
+      SELECT GROUP_CONCAT(email_outbox_recipients.email SEPARATOR ', ')
+      FROM email_outbox_recipients
+      WHERE email_outbox_recipients.outbox_id = email_outbox.id
+    ), '') AS recipients,
+    email_outbox.status, email_outbox.attempts, email_outbox.max_attempts,
+    email_outbox.next_attempt_at, email_outbox.provider,
+    COALESCE(email_outbox.last_error, '') AS last_error,
+    COALESCE(email_outbox.sent_at, '') AS sent_at,
+    COALESCE(email_outbox.created_at, '') AS created_at
+  FROM email_outbox
+  WHERE %s
+  `, where)
+
+	err := s.repository.SelectWithQuery(ctx, &data, query, map[string]interface{}{
+		"limit":  params.FindAllParams.Size,
+		"offset": ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+		"status": params.Status,
+		"email":  params.Email,
+	})
+
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".EmailOutboxStorage->FindAll()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return data, nil
+}
+
+// Find is a function to get by ID with the recipients, attachments & attempts
+func (s EmailOutboxRepository) Find(ctx *gin.Context, id string) (*models.EmailOutbox, *types.Error) {
+	result := models.EmailOutbox{}
+
+	err := s.repository.SelectFirstWithQuery(ctx, &result, `
+  SELECT
+    id, from_email, from_name, reply_to, subject, status, attempts, max_attempts,
+    next_attempt_at, provider, provider_message_id,
+    COALESCE(last_error, '') AS last_error,
+    COALESCE(sent_at, '') AS sent_at,
+    COALESCE(created_at, '') AS created_at
+  FROM email_outbox
+  WHERE id = :id`, map[string]interface{}{
+		"id": id,
+	})
+	if err != nil {
+		if err == data.ErrNotFound {
+			return nil, &types.Error{
+				Path:       ".EmailOutboxStorage->Find()",
+				Message:    "Data Not Found",
+				Error:      data.ErrNotFound,
+				StatusCode: http.StatusNotFound,
+				Type:       "mysql-error",
+			}
+		}
+
+		return nil, &types.Error{
+			Path:       ".EmailOutboxStorage->Find()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	result.Recipients = []*models.EmailOutboxRecipient{}
+	err = s.repository.SelectWithQuery(ctx, &result.Recipients, `
+  SELECT
+    id, kind, email, name, status, provider_message_id,
+    COALESCE(last_error, '') AS last_error,
+    COALESCE(sent_at, '') AS sent_at
+  FROM email_outbox_recipients
+  WHERE outbox_id = :id
+  ORDER BY FIELD(kind, 'to', 'cc', 'bcc'), email`, map[string]interface{}{
+		"id": id,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".EmailOutboxStorage->Find()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	result.Attachments = []*models.EmailOutboxAttachment{}
+	err = s.repository.SelectWithQuery(ctx, &result.Attachments, `
+  SELECT id, filename, content_type, content_id, LENGTH(content) AS size
+  FROM email_outbox_attachments
+  WHERE outbox_id = :id
+  ORDER BY filename`, map[string]interface{}{
+		"id": id,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".EmailOutboxStorage->Find()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	result.AttemptLog = []*models.EmailOutboxAttempt{}
+	err = s.repository.SelectWithQuery(ctx, &result.AttemptLog, `
+  SELECT
+    id, attempt, status, provider,
+    COALESCE(response, '') AS response,
+    COALESCE(error, '') AS error,
+    started_at, finished_at
+  FROM email_outbox_attempts
+  WHERE outbox_id = :id
+  ORDER BY started_at, attempt`, map[string]interface{}{
+		"id": id,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".EmailOutboxStorage->Find()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &result, nil
+}
+
+// Resend is a function to queue a sent or failed email again
+func (s EmailOutboxRepository) Resend(ctx *gin.Context, id string) *types.Error {
+	err := s.outbox.Resend(ctx, id)
+	if err != nil {
+		if err == mail.ErrNotResendable {
+			return &types.Error{
+				Path:       ".EmailOutboxStorage->Resend()",
+				Message:    "Email masih dalam proses pengiriman",
+				Error:      err,
+				StatusCode: http.StatusUnprocessableEntity,
+				Type:       "validation-error",
+			}
+		}
+
+		return &types.Error{
+			Path:       ".EmailOutboxStorage->Resend()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
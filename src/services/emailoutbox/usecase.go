@@ -0,0 +1,16 @@
+package emailoutbox
+
+import (
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Usecase is the contract between Repository and usecase
+type Usecase interface {
+	FindAll(*gin.Context, models.FindAllEmailOutboxParams) ([]*models.EmailOutboxBulk, *types.Error)
+	Find(*gin.Context, string) (*models.EmailOutbox, *types.Error)
+	Count(*gin.Context, models.FindAllEmailOutboxParams) (int, *types.Error)
+	Resend(*gin.Context, string) (*models.EmailOutbox, *types.Error)
+}
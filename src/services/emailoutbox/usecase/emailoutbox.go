@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"time"
+
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/src/services/emailoutbox"
+
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type EmailOutboxUsecase struct {
+	emailOutboxRepo emailoutbox.Repository
+	contextTimeout  time.Duration
+	db              *sqlx.DB
+}
+
+func NewEmailOutboxUsecase(db *sqlx.DB, emailOutboxRepo emailoutbox.Repository) emailoutbox.Usecase {
+	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+
+	return &EmailOutboxUsecase{
+		emailOutboxRepo: emailOutboxRepo,
+		contextTimeout:  timeoutContext,
+		db:              db,
+	}
+}
+
+func (u *EmailOutboxUsecase) FindAll(ctx *gin.Context, params models.FindAllEmailOutboxParams) ([]*models.EmailOutboxBulk, *types.Error) {
+	result, err := u.emailOutboxRepo.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".EmailOutboxUsecase->FindAll()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *EmailOutboxUsecase) Find(ctx *gin.Context, id string) (*models.EmailOutbox, *types.Error) {
+	result, err := u.emailOutboxRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".EmailOutboxUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *EmailOutboxUsecase) Count(ctx *gin.Context, params models.FindAllEmailOutboxParams) (int, *types.Error) {
+	result, err := u.emailOutboxRepo.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".EmailOutboxUsecase->Count()" + err.Path
+		return 0, err
+	}
+
+	return len(result), nil
+}
+
+func (u *EmailOutboxUsecase) Resend(ctx *gin.Context, id string) (*models.EmailOutbox, *types.Error) {
+	_, err := u.emailOutboxRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".EmailOutboxUsecase->Resend()" + err.Path
+		return nil, err
+	}
+
+	err = u.emailOutboxRepo.Resend(ctx, id)
+	if err != nil {
+		err.Path = ".EmailOutboxUsecase->Resend()" + err.Path
+		return nil, err
+	}
+
+	result, err := u.emailOutboxRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".EmailOutboxUsecase->Resend()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
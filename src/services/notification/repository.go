@@ -0,0 +1,18 @@
+package notification
+
+import (
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Repository is the contract between Repository and usecase
+type Repository interface {
+	FindAll(*gin.Context, models.FindAllNotificationParams) ([]*models.Notification, *types.Error)
+	Find(*gin.Context, string, string) (*models.Notification, *types.Error)
+	MarkRead(*gin.Context, string, string) *types.Error
+
+	FindPreferences(*gin.Context, string) ([]*models.NotificationPreference, *types.Error)
+	ReplacePreferences(*gin.Context, string, []*models.NotificationPreference) *types.Error
+}
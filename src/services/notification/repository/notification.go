@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+
+	"luxe-beb-go/library"
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/notif"
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationRepository initialize object from model Notification, to be used in database operation
+type NotificationRepository struct {
+	repository           data.GenericStorage
+	preferenceRepository data.GenericStorage
+}
+
+// NewNotificationRepository initialize service that provide connection to Database
+func NewNotificationRepository(repository data.GenericStorage, preferenceRepository data.GenericStorage) NotificationRepository {
+	return NotificationRepository{repository: repository, preferenceRepository: preferenceRepository}
+}
+
+const notificationSelect = `
+  SELECT
+    notifications.id, notifications.event, notifications.channel, notifications.recipient,
+    notifications.subject, COALESCE(notifications.body, '') AS body, notifications.repeat_count,
+    notifications.status,
+    COALESCE(notifications.sent_at, '') AS sent_at,
+    COALESCE(notifications.read_at, '') AS read_at,
+    COALESCE(notifications.created_at, '') AS created_at
+  FROM notifications`
+
+// FindAll is a function to get the in-app notifications of a user
+func (s NotificationRepository) FindAll(ctx *gin.Context, params models.FindAllNotificationParams) ([]*models.Notification, *types.Error) {
+	data := []*models.Notification{}
+
+	where := `notifications.channel = :channel AND notifications.user_id = :user_id`
+
+	if params.FindAllParams.DataFinder != "" {
+		where = fmt.Sprintf("%s AND %s", where, params.FindAllParams.DataFinder)
+	}
+
+	if params.Unread {
+		where = fmt.Sprintf("%s AND notifications.read_at IS NULL", where)
+	}
+
+	if params.FindAllParams.SortBy != "" {
+		where = fmt.Sprintf("%s ORDER BY %s", where, params.FindAllParams.SortBy)
+	}
+
+	if params.FindAllParams.Page > 0 && params.FindAllParams.Size > 0 {
+		where = fmt.Sprintf(`%s LIMIT :limit OFFSET :offset`, where)
+	}
+
+	query := fmt.Sprintf(`%s
+  WHERE %s
+  `, notificationSelect, where)
+
+	err := s.repository.SelectWithQuery(ctx, &data, query, map[string]interface{}{
+		"limit":   params.FindAllParams.Size,
+		"offset":  ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+		"channel": notif.ChannelInApp,
+		"user_id": params.UserID,
+	})
+
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".NotificationStorage->FindAll()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return data, nil
+}
+
+// Find is a function to get an in-app notification of a user by ID
+func (s NotificationRepository) Find(ctx *gin.Context, userID string, id string) (*models.Notification, *types.Error) {
+	result := models.Notification{}
+
+	query := fmt.Sprintf(`%s
+  WHERE notifications.id = :id AND notifications.user_id = :user_id AND notifications.channel = :channel`, notificationSelect)
+
+	err := s.repository.SelectFirstWithQuery(ctx, &result, query, map[string]interface{}{
+		"id":      id,
+		"user_id": userID,
+		"channel": notif.ChannelInApp,
+	})
+	if err != nil {
+		if err == data.ErrNotFound {
+			return nil, &types.Error{
+				Path:       ".NotificationStorage->Find()",
+				Message:    "Data Not Found",
+				Error:      data.ErrNotFound,
+				StatusCode: http.StatusNotFound,
+				Type:       "mysql-error",
+			}
+		}
+
+		return nil, &types.Error{
+			Path:       ".NotificationStorage->Find()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &result, nil
+}
+
+// MarkRead is a function to mark the in-app notifications of a user as read, every unread one when id is empty
+func (s NotificationRepository) MarkRead(ctx *gin.Context, userID string, id string) *types.Error {
+	where := `user_id = :user_id AND channel = :channel AND read_at IS NULL`
+	if id != "" {
+		where = fmt.Sprintf("%s AND id = :id", where)
+	}
+
+	err := s.repository.ExecQuery(ctx, fmt.Sprintf(`UPDATE notifications SET read_at = :now WHERE %s`, where), map[string]interface{}{
+		"id":      id,
+		"user_id": userID,
+		"channel": notif.ChannelInApp,
+		"now":     library.UTCPlus7(),
+	})
+	if err != nil {
+		return &types.Error{
+			Path:       ".NotificationStorage->MarkRead()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
+
+// FindPreferences is a function to get the notification preferences of a user
+func (s NotificationRepository) FindPreferences(ctx *gin.Context, userID string) ([]*models.NotificationPreference, *types.Error) {
+	data := []*models.NotificationPreference{}
+
+	err := s.preferenceRepository.SelectWithQuery(ctx, &data, `
+  SELECT
+    id, user_id, event, channel, address, enabled,
+    COALESCE(TIME_FORMAT(quiet_start, '%H:%i'), '') AS quiet_start,
+    COALESCE(TIME_FORMAT(quiet_end, '%H:%i'), '') AS quiet_end
+  FROM notification_preferences
+  WHERE user_id = :user_id
+  ORDER BY event, channel`, map[string]interface{}{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".NotificationStorage->FindPreferences()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return data, nil
+}
+
+// ReplacePreferences is a function to replace every notification preference of a user
+func (s NotificationRepository) ReplacePreferences(ctx *gin.Context, userID string, preferences []*models.NotificationPreference) *types.Error {
+	err := s.preferenceRepository.ExecQuery(ctx, `DELETE FROM notification_preferences WHERE user_id = :user_id`, map[string]interface{}{
+		"user_id": userID,
+	})
+	if err != nil {
+		return &types.Error{
+			Path:       ".NotificationStorage->ReplacePreferences()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	now := library.UTCPlus7()
+	for _, v := range preferences {
+		var quietStart, quietEnd interface{}
+		if v.QuietStart != "" {
+			quietStart, quietEnd = v.QuietStart, v.QuietEnd
+		}
+
+		err := s.preferenceRepository.ExecQuery(ctx, `
+  INSERT INTO notification_preferences (id, user_id, event, channel, address, enabled, quiet_start, quiet_end, created_at, updated_at)
+  VALUES (:id, :user_id, :event, :channel, :address, :enabled, :quiet_start, :quiet_end, :now, :now)`, map[string]interface{}{
+			"id":          v.ID,
+			"user_id":     userID,
+			"event":       v.Event,
+			"channel":     v.Channel,
+			"address":     v.Address,
+			"enabled":     v.Enabled,
+			"quiet_start": quietStart,
+			"quiet_end":   quietEnd,
+			"now":         now,
+		})
+		if err != nil {
+			return &types.Error{
+				Path:       ".NotificationStorage->ReplacePreferences()",
+				Message:    err.Error(),
+				Error:      err,
+				StatusCode: http.StatusInternalServerError,
+				Type:       "mysql-error",
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,19 @@
+package notification
+
+import (
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Usecase is the contract between Repository and usecase
+type Usecase interface {
+	FindAll(*gin.Context, models.FindAllNotificationParams) ([]*models.Notification, *types.Error)
+	Count(*gin.Context, models.FindAllNotificationParams) (int, *types.Error)
+	MarkRead(*gin.Context, string) (*models.Notification, *types.Error)
+	MarkAllRead(*gin.Context) *types.Error
+
+	FindPreferences(*gin.Context) ([]*models.NotificationPreference, *types.Error)
+	UpdatePreferences(*gin.Context, models.NotificationPreferencesRequest) ([]*models.NotificationPreference, *types.Error)
+}
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"luxe-beb-go/library"
+	"luxe-beb-go/library/appcontext"
+	"luxe-beb-go/library/notif"
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/library/validator"
+	"luxe-beb-go/library/whatsapp"
+	"luxe-beb-go/src/services/notification"
+
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type NotificationUsecase struct {
+	notificationRepo notification.Repository
+	contextTimeout   time.Duration
+	db               *sqlx.DB
+}
+
+func NewNotificationUsecase(db *sqlx.DB, notificationRepo notification.Repository) notification.Usecase {
+	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+
+	return &NotificationUsecase{
+		notificationRepo: notificationRepo,
+		contextTimeout:   timeoutContext,
+		db:               db,
+	}
+}
+
+func (u *NotificationUsecase) FindAll(ctx *gin.Context, params models.FindAllNotificationParams) ([]*models.Notification, *types.Error) {
+	params.UserID = currentUserID(ctx)
+
+	result, err := u.notificationRepo.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".NotificationUsecase->FindAll()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *NotificationUsecase) Count(ctx *gin.Context, params models.FindAllNotificationParams) (int, *types.Error) {
+	params.UserID = currentUserID(ctx)
+
+	result, err := u.notificationRepo.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".NotificationUsecase->Count()" + err.Path
+		return 0, err
+	}
+
+	return len(result), nil
+}
+
+func (u *NotificationUsecase) MarkRead(ctx *gin.Context, id string) (*models.Notification, *types.Error) {
+	userID := currentUserID(ctx)
+
+	_, err := u.notificationRepo.Find(ctx, userID, id)
+	if err != nil {
+		err.Path = ".NotificationUsecase->MarkRead()" + err.Path
+		return nil, err
+	}
+
+	err = u.notificationRepo.MarkRead(ctx, userID, id)
+	if err != nil {
+		err.Path = ".NotificationUsecase->MarkRead()" + err.Path
+		return nil, err
+	}
+
+	result, err := u.notificationRepo.Find(ctx, userID, id)
+	if err != nil {
+		err.Path = ".NotificationUsecase->MarkRead()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *NotificationUsecase) MarkAllRead(ctx *gin.Context) *types.Error {
+	err := u.notificationRepo.MarkRead(ctx, currentUserID(ctx), "")
+	if err != nil {
+		err.Path = ".NotificationUsecase->MarkAllRead()" + err.Path
+		return err
+	}
+
+	return nil
+}
+
+func (u *NotificationUsecase) FindPreferences(ctx *gin.Context) ([]*models.NotificationPreference, *types.Error) {
+	result, err := u.notificationRepo.FindPreferences(ctx, currentUserID(ctx))
+	if err != nil {
+		err.Path = ".NotificationUsecase->FindPreferences()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdatePreferences replaces the preferences of the current user. An empty event or channel applies to all of them,
+// the address is the email or whatsapp number used instead of the one of the account.
+func (u *NotificationUsecase) UpdatePreferences(ctx *gin.Context, obj models.NotificationPreferencesRequest) ([]*models.NotificationPreference, *types.Error) {
+	errValidation := validator.Shared().StructCtx(ctx, obj)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".NotificationUsecase->UpdatePreferences()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	preferences := []*models.NotificationPreference{}
+	seen := map[string]bool{}
+	for _, v := range obj.Preferences {
+		data := &models.NotificationPreference{
+			ID:         uuid.New().String(),
+			Event:      v.Event,
+			Channel:    v.Channel,
+			Address:    v.Address,
+			Enabled:    v.Enabled == nil || *v.Enabled,
+			QuietStart: v.QuietStart,
+			QuietEnd:   v.QuietEnd,
+		}
+		if data.Event == "" {
+			data.Event = "*"
+		}
+		if data.Channel == "" {
+			data.Channel = "*"
+		}
+
+		key := data.Event + "|" + data.Channel
+		if seen[key] {
+			return nil, &types.Error{
+				Path:       ".NotificationUsecase->UpdatePreferences()",
+				Message:    fmt.Sprintf("Preferensi %s untuk channel %s lebih dari satu", data.Event, data.Channel),
+				Error:      fmt.Errorf("duplicate preference %s", key),
+				StatusCode: http.StatusUnprocessableEntity,
+				Type:       "validation-error",
+			}
+		}
+		seen[key] = true
+
+		address, err := preferenceAddress(data.Channel, data.Address)
+		if err != nil {
+			return nil, &types.Error{
+				Path:       ".NotificationUsecase->UpdatePreferences()",
+				Message:    fmt.Sprintf("Alamat %s tidak valid untuk channel %s", data.Address, data.Channel),
+				Error:      err,
+				StatusCode: http.StatusUnprocessableEntity,
+				Type:       "validation-error",
+			}
+		}
+		data.Address = address
+
+		preferences = append(preferences, data)
+	}
+
+	err := u.notificationRepo.ReplacePreferences(ctx, currentUserID(ctx), preferences)
+	if err != nil {
+		err.Path = ".NotificationUsecase->UpdatePreferences()" + err.Path
+		return nil, err
+	}
+
+	result, err := u.notificationRepo.FindPreferences(ctx, currentUserID(ctx))
+	if err != nil {
+		err.Path = ".NotificationUsecase->UpdatePreferences()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// preferenceAddress checks the address fits the channel, whatsapp numbers are stored in E.164
+func preferenceAddress(channel string, address string) (string, error) {
+	if address == "" {
+		return "", nil
+	}
+
+	switch channel {
+	case notif.ChannelEmail:
+		if !library.IsEmailValid(address) {
+			return "", fmt.Errorf("invalid email address")
+		}
+	case notif.ChannelWhatsapp:
+		return whatsapp.NormalizePhone(address)
+	case notif.ChannelSlack:
+	default:
+		return "", fmt.Errorf("channel %s has no address", channel)
+	}
+
+	return address, nil
+}
+
+func currentUserID(ctx *gin.Context) string {
+	if userID := appcontext.UserID(ctx); userID != nil {
+		return *userID
+	}
+	return ""
+}
@@ -0,0 +1,19 @@
+package notificationrule
+
+import (
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Repository is the contract between Repository and usecase
+type Repository interface {
+	FindAll(*gin.Context, models.FindAllNotificationRuleParams) ([]*models.NotificationRule, *types.Error)
+	Find(*gin.Context, string) (*models.NotificationRule, *types.Error)
+	Create(*gin.Context, *models.NotificationRule) (*models.NotificationRule, *types.Error)
+	Update(*gin.Context, *models.NotificationRule) (*models.NotificationRule, *types.Error)
+
+	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	UpdateStatus(*gin.Context, string, string) (*models.NotificationRule, *types.Error)
+}
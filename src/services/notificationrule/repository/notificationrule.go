@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationRuleRepository initialize object from model NotificationRule, to be used in database operation
+type NotificationRuleRepository struct {
+	repository       data.GenericStorage
+	statusRepository data.GenericStorage
+}
+
+// NewNotificationRuleRepository initialize service that provide connection to Database
+func NewNotificationRuleRepository(repository data.GenericStorage, statusRepository data.GenericStorage) NotificationRuleRepository {
+	return NotificationRuleRepository{repository: repository, statusRepository: statusRepository}
+}
+
+const notificationRuleSelect = `
+  SELECT
+    notification_rules.id, notification_rules.business_id, notification_rules.name, notification_rules.event,
+    notification_rules.channel, notification_rules.target,
+    COALESCE(notification_rules.subject_template, '') AS subject_template,
+    COALESCE(notification_rules.body_template, '') AS body_template,
+    notification_rules.dedup_seconds, notification_rules.ignore_quiet_hours,
+    notification_rules.status_id, status.name status_name
+  FROM notification_rules
+  JOIN status ON notification_rules.status_id = status.id`
+
+// FindAll is a function to get all Data
+func (s NotificationRuleRepository) FindAll(ctx *gin.Context, params models.FindAllNotificationRuleParams) ([]*models.NotificationRule, *types.Error) {
+	data := []*models.NotificationRule{}
+	bulks := []*models.NotificationRuleBulk{}
+
+	where := `true`
+
+	if params.FindAllParams.DataFinder != "" {
+		where = fmt.Sprintf("%s AND %s", where, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where = fmt.Sprintf("%s AND notification_rules.%s", where, params.FindAllParams.StatusID)
+	}
+
+	if params.Event != "" {
+		where = fmt.Sprintf("%s AND notification_rules.event = :event", where)
+	}
+
+	if params.Channel != "" {
+		where = fmt.Sprintf("%s AND notification_rules.channel = :channel", where)
+	}
+
+	if params.FindAllParams.SortBy != "" {
+		where = fmt.Sprintf("%s ORDER BY %s", where, params.FindAllParams.SortBy)
+	}
+
+	if params.FindAllParams.Page > 0 && params.FindAllParams.Size > 0 {
+		where = fmt.Sprintf(`%s LIMIT :limit OFFSET :offset`, where)
+	}
+
+	query := fmt.Sprintf(`%s
+  WHERE %s
+  `, notificationRuleSelect, where)
+
+	err := s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"limit":   params.FindAllParams.Size,
+		"offset":  ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+		"event":   params.Event,
+		"channel": params.Channel,
+	})
+
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".NotificationRuleStorage->FindAll()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		data = append(data, notificationRule(v))
+	}
+
+	return data, nil
+}
+
+// Find is a function to get by ID
+func (s NotificationRuleRepository) Find(ctx *gin.Context, id string) (*models.NotificationRule, *types.Error) {
+	bulks := []*models.NotificationRuleBulk{}
+
+	query := fmt.Sprintf(`%s
+  WHERE notification_rules.id = :id`, notificationRuleSelect)
+
+	err := s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"id": id,
+	})
+
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".NotificationRuleStorage->Find()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(bulks) == 0 {
+		return nil, &types.Error{
+			Path:       ".NotificationRuleStorage->Find()",
+			Message:    "Data Not Found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return notificationRule(bulks[0]), nil
+}
+
+// Create is a function to get by ID
+func (s NotificationRuleRepository) Create(ctx *gin.Context, obj *models.NotificationRule) (*models.NotificationRule, *types.Error) {
+	_, err := s.repository.Insert(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".NotificationRuleStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	result, errFind := s.Find(ctx, obj.ID)
+	if errFind != nil {
+		errFind.Path = ".NotificationRuleStorage->Create()" + errFind.Path
+		return nil, errFind
+	}
+	return result, nil
+}
+
+// Update is a function to get by ID
+func (s NotificationRuleRepository) Update(ctx *gin.Context, obj *models.NotificationRule) (*models.NotificationRule, *types.Error) {
+	err := s.repository.Update(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".NotificationRuleStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	result, errFind := s.Find(ctx, obj.ID)
+	if errFind != nil {
+		errFind.Path = ".NotificationRuleStorage->Update()" + errFind.Path
+		return nil, errFind
+	}
+	return result, nil
+}
+
+// FindStatus is a function to get the statuses of a rule
+func (s NotificationRuleRepository) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	statuses := []*models.Status{}
+
+	err := s.statusRepository.Where(ctx, &statuses, "1=1", map[string]interface{}{})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".NotificationRuleStorage->FindStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return statuses, nil
+}
+
+// UpdateStatus is a function to activate or deactivate a rule
+func (s NotificationRuleRepository) UpdateStatus(ctx *gin.Context, id string, statusID string) (*models.NotificationRule, *types.Error) {
+	err := s.repository.UpdateStatus(ctx, id, statusID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".NotificationRuleStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	result, errFind := s.Find(ctx, id)
+	if errFind != nil {
+		errFind.Path = ".NotificationRuleStorage->UpdateStatus()" + errFind.Path
+		return nil, errFind
+	}
+	return result, nil
+}
+
+func notificationRule(v *models.NotificationRuleBulk) *models.NotificationRule {
+	return &models.NotificationRule{
+		ID:               v.ID,
+		BusinessID:       v.BusinessID,
+		Name:             v.Name,
+		Event:            v.Event,
+		Channel:          v.Channel,
+		Target:           v.Target,
+		SubjectTemplate:  v.SubjectTemplate,
+		BodyTemplate:     v.BodyTemplate,
+		DedupSeconds:     v.DedupSeconds,
+		IgnoreQuietHours: v.IgnoreQuietHours,
+		StatusID:         v.StatusID,
+		Status: models.Status{
+			ID:   v.StatusID,
+			Name: v.StatusName,
+		},
+	}
+}
@@ -0,0 +1,20 @@
+package notificationrule
+
+import (
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Usecase is the contract between Repository and usecase
+type Usecase interface {
+	FindAll(*gin.Context, models.FindAllNotificationRuleParams) ([]*models.NotificationRule, *types.Error)
+	Find(*gin.Context, string) (*models.NotificationRule, *types.Error)
+	Count(*gin.Context, models.FindAllNotificationRuleParams) (int, *types.Error)
+	Create(*gin.Context, models.NotificationRuleRequest) (*models.NotificationRule, *types.Error)
+	Update(*gin.Context, string, models.NotificationRuleRequest) (*models.NotificationRule, *types.Error)
+
+	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	UpdateStatus(*gin.Context, string, string) (*models.NotificationRule, *types.Error)
+}
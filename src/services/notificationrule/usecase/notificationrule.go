@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"net/http"
+	"time"
+
+	"luxe-beb-go/library/notif"
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/library/validator"
+	"luxe-beb-go/src/services/notificationrule"
+
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type NotificationRuleUsecase struct {
+	notificationRuleRepo notificationrule.Repository
+	contextTimeout       time.Duration
+	db                   *sqlx.DB
+}
+
+func NewNotificationRuleUsecase(db *sqlx.DB, notificationRuleRepo notificationrule.Repository) notificationrule.Usecase {
+	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+
+	return &NotificationRuleUsecase{
+		notificationRuleRepo: notificationRuleRepo,
+		contextTimeout:       timeoutContext,
+		db:                   db,
+	}
+}
+
+func (u *NotificationRuleUsecase) FindAll(ctx *gin.Context, params models.FindAllNotificationRuleParams) ([]*models.NotificationRule, *types.Error) {
+	result, err := u.notificationRuleRepo.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".NotificationRuleUsecase->FindAll()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *NotificationRuleUsecase) Find(ctx *gin.Context, id string) (*models.NotificationRule, *types.Error) {
+	result, err := u.notificationRuleRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".NotificationRuleUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *NotificationRuleUsecase) Count(ctx *gin.Context, params models.FindAllNotificationRuleParams) (int, *types.Error) {
+	result, err := u.notificationRuleRepo.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".NotificationRuleUsecase->Count()" + err.Path
+		return 0, err
+	}
+
+	return len(result), nil
+}
+
+func (u *NotificationRuleUsecase) Create(ctx *gin.Context, obj models.NotificationRuleRequest) (*models.NotificationRule, *types.Error) {
+	data := models.NotificationRule{
+		ID:       uuid.New().String(),
+		StatusID: models.DEFAULT_STATUS_ID,
+	}
+	fillNotificationRule(&data, obj)
+
+	errValidation := validator.Shared().StructCtx(ctx, data)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".NotificationRuleUsecase->Create()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.notificationRuleRepo.Create(ctx, &data)
+	if err != nil {
+		err.Path = ".NotificationRuleUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *NotificationRuleUsecase) Update(ctx *gin.Context, id string, obj models.NotificationRuleRequest) (*models.NotificationRule, *types.Error) {
+	data, err := u.notificationRuleRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".NotificationRuleUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	fillNotificationRule(data, obj)
+
+	errValidation := validator.Shared().StructCtx(ctx, data)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".NotificationRuleUsecase->Update()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.notificationRuleRepo.Update(ctx, data)
+	if err != nil {
+		err.Path = ".NotificationRuleUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *NotificationRuleUsecase) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	result, err := u.notificationRuleRepo.FindStatus(ctx)
+	if err != nil {
+		err.Path = ".NotificationRuleUsecase->FindStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *NotificationRuleUsecase) UpdateStatus(ctx *gin.Context, id string, newStatusID string) (*models.NotificationRule, *types.Error) {
+	result, err := u.notificationRuleRepo.UpdateStatus(ctx, id, newStatusID)
+	if err != nil {
+		err.Path = ".NotificationRuleUsecase->UpdateStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, err
+}
+
+// fillNotificationRule copies the request into the rule, a rule without target goes to the user of the event
+func fillNotificationRule(data *models.NotificationRule, obj models.NotificationRuleRequest) {
+	data.Name = obj.Name
+	data.Event = obj.Event
+	data.Channel = obj.Channel
+	data.Target = obj.Target
+	if data.Target == "" && obj.Channel != notif.ChannelSlack {
+		data.Target = notif.TargetUser
+	}
+	data.SubjectTemplate = obj.SubjectTemplate
+	data.BodyTemplate = obj.BodyTemplate
+	data.DedupSeconds = obj.DedupSeconds
+	data.IgnoreQuietHours = obj.IgnoreQuietHours
+}
@@ -0,0 +1,19 @@
+package outlet
+
+import (
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Repository is the contract between Repository and usecase
+type Repository interface {
+	FindAll(*gin.Context, models.FindAllOutletParams) ([]*models.Outlet, *types.Error)
+	Find(*gin.Context, string) (*models.Outlet, *types.Error)
+	Create(*gin.Context, *models.Outlet) (*models.Outlet, *types.Error)
+	Update(*gin.Context, *models.Outlet) (*models.Outlet, *types.Error)
+
+	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	UpdateStatus(*gin.Context, string, string) (*models.Outlet, *types.Error)
+}
@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OutletRepository initialize object from model Outlet, to be used in database operation
+type OutletRepository struct {
+	repository       data.GenericStorage
+	statusRepository data.GenericStorage
+}
+
+// NewOutletRepository initialize service that provide connection to Database
+func NewOutletRepository(repository data.GenericStorage, statusRepository data.GenericStorage) OutletRepository {
+	//db := &models.DB{DB: configs.ActiveDB}
+	return OutletRepository{repository: repository, statusRepository: statusRepository}
+}
+
+// FindAll is a function to get all Data
+func (s OutletRepository) FindAll(ctx *gin.Context, params models.FindAllOutletParams) ([]*models.Outlet, *types.Error) {
+	data := []*models.Outlet{}
+	bulks := []*models.OutletBulk{}
+
+	var err error
+
+	where := `true`
+
+	if params.FindAllParams.DataFinder != "" {
+		where = fmt.Sprintf("%s AND %s", where, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where = fmt.Sprintf("%s AND outlets.%s", where, params.FindAllParams.StatusID)
+	}
+
+	if params.FindAllParams.BusinessID != "" {
+		where = fmt.Sprintf("%s AND outlets.%s", where, params.FindAllParams.BusinessID)
+	}
+
+	if params.FindAllParams.SortBy != "" {
+		where = fmt.Sprintf("%s ORDER BY %s", where, params.FindAllParams.SortBy)
+	}
+
+	if params.FindAllParams.Page > 0 && params.FindAllParams.Size > 0 {
+		where = fmt.Sprintf(`%s LIMIT :limit OFFSET :offset`, where)
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    outlets.id, outlets.business_id, businesses.name business_name,
+    outlets.name, outlets.address,
+    outlets.status_id, status.name status_name
+  FROM outlets
+  JOIN businesses ON outlets.business_id = businesses.id
+  JOIN status ON outlets.status_id = status.id
+  WHERE %s
+  `, where)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"limit":        params.FindAllParams.Size,
+		"offset":       ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+		"status_id":    params.FindAllParams.StatusID,
+		"business_ids": params.FindAllParams.Businesses,
+	})
+
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".OutletStorage->FindAll()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		obj := &models.Outlet{
+			ID:         v.ID,
+			BusinessID: v.BusinessID,
+			Business: models.IDNameTemplate{
+				ID:   v.BusinessID,
+				Name: v.BusinessName,
+			},
+			Name:     v.Name,
+			Address:  v.Address,
+			StatusID: v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+		}
+
+		data = append(data, obj)
+	}
+
+	return data, nil
+}
+
+// Find is a function to get by ID
+func (s OutletRepository) Find(ctx *gin.Context, id string) (*models.Outlet, *types.Error) {
+	result := models.Outlet{}
+	bulks := []*models.OutletBulk{}
+	var err error
+
+	query := fmt.Sprintf(`
+  SELECT outlets.id, outlets.business_id, businesses.name business_name,
+  outlets.name, outlets.address,
+  outlets.status_id, status.name status_name
+  FROM outlets
+  JOIN businesses ON outlets.business_id = businesses.id
+  JOIN status ON outlets.status_id = status.id
+  WHERE outlets.id = :id`)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"id": id,
+	})
+
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".OutletStorage->Find()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(bulks) > 0 {
+		v := bulks[0]
+		result = models.Outlet{
+			ID:         v.ID,
+			BusinessID: v.BusinessID,
+			Business: models.IDNameTemplate{
+				ID:   v.BusinessID,
+				Name: v.BusinessName,
+			},
+			Name:     v.Name,
+			Address:  v.Address,
+			StatusID: v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+		}
+	} else {
+		return nil, &types.Error{
+			Path:       ".OutletStorage->Find()",
+			Message:    "Data Not Found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &result, nil
+}
+
+// Create is a function to get by ID
+func (s OutletRepository) Create(ctx *gin.Context, obj *models.Outlet) (*models.Outlet, *types.Error) {
+	data := models.Outlet{}
+	_, err := s.repository.Insert(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".OutletStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".OutletStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+// Update is a function to get by ID
+func (s OutletRepository) Update(ctx *gin.Context, obj *models.Outlet) (*models.Outlet, *types.Error) {
+	data := models.Outlet{}
+	err := s.repository.Update(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".OutletStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".OutletStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+// FindStatus is a function to get by ID
+func (s OutletRepository) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	businessStatus := []*models.Status{}
+
+	err := s.statusRepository.Where(ctx, &businessStatus, "1=1", map[string]interface{}{})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".OutletStorage->FindStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return businessStatus, nil
+}
+
+// UpdateStatus is a function to get by ID
+func (s OutletRepository) UpdateStatus(ctx *gin.Context, id string, statusID string) (*models.Outlet, *types.Error) {
+	data := models.Outlet{}
+	err := s.repository.UpdateStatus(ctx, id, statusID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".OutletStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, id)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".OutletStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &data, nil
+}
@@ -0,0 +1,20 @@
+package outlet
+
+import (
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Usecase is the contract between Repository and usecase
+type Usecase interface {
+	FindAll(*gin.Context, models.FindAllOutletParams) ([]*models.Outlet, *types.Error)
+	Find(*gin.Context, string) (*models.Outlet, *types.Error)
+	Count(*gin.Context, models.FindAllOutletParams) (int, *types.Error)
+	Create(*gin.Context, models.Outlet) (*models.Outlet, *types.Error)
+	Update(*gin.Context, string, models.Outlet) (*models.Outlet, *types.Error)
+
+	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	UpdateStatus(*gin.Context, string, string) (*models.Outlet, *types.Error)
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"net/http"
+	"time"
+
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/library/validator"
+	"luxe-beb-go/src/services/outlet"
+
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type OutletUsecase struct {
+	outletRepo     outlet.Repository
+	contextTimeout time.Duration
+	db             *sqlx.DB
+}
+
+func NewOutletUsecase(db *sqlx.DB, outletRepo outlet.Repository) outlet.Usecase {
+	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+
+	return &OutletUsecase{
+		outletRepo:     outletRepo,
+		contextTimeout: timeoutContext,
+		db:             db,
+	}
+}
+
+func (u *OutletUsecase) FindAll(ctx *gin.Context, filterFindAllParams models.FindAllOutletParams) ([]*models.Outlet, *types.Error) {
+	result, err := u.outletRepo.FindAll(ctx, filterFindAllParams)
+	if err != nil {
+		err.Path = ".OutletUsecase->FindAll()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *OutletUsecase) Find(ctx *gin.Context, id string) (*models.Outlet, *types.Error) {
+	result, err := u.outletRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".OutletUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *OutletUsecase) Count(ctx *gin.Context, filterFindAllParams models.FindAllOutletParams) (int, *types.Error) {
+	result, err := u.outletRepo.FindAll(ctx, filterFindAllParams)
+	if err != nil {
+		err.Path = ".OutletUsecase->Count()" + err.Path
+		return 0, err
+	}
+
+	return len(result), nil
+}
+
+func (u *OutletUsecase) Create(ctx *gin.Context, obj models.Outlet) (*models.Outlet, *types.Error) {
+	errValidation := validator.Shared().StructCtx(ctx, obj)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".OutletUsecase->Create()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	data := models.Outlet{
+		ID:         uuid.New().String(),
+		BusinessID: obj.BusinessID,
+		Name:       obj.Name,
+		Address:    obj.Address,
+		StatusID:   models.DEFAULT_STATUS_ID,
+	}
+
+	result, err := u.outletRepo.Create(ctx, &data)
+	if err != nil {
+		err.Path = ".OutletUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *OutletUsecase) Update(ctx *gin.Context, id string, obj models.Outlet) (*models.Outlet, *types.Error) {
+	errValidation := validator.Shared().StructCtx(ctx, obj)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".OutletUsecase->Update()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	data, err := u.outletRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".OutletUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	data.BusinessID = obj.BusinessID
+	data.Name = obj.Name
+	data.Address = obj.Address
+
+	result, err := u.outletRepo.Update(ctx, data)
+	if err != nil {
+		err.Path = ".OutletUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	return result, err
+}
+
+func (u *OutletUsecase) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	result, err := u.outletRepo.FindStatus(ctx)
+	if err != nil {
+		err.Path = ".OutletUsecase->FindStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *OutletUsecase) UpdateStatus(ctx *gin.Context, id string, newStatusID string) (*models.Outlet, *types.Error) {
+	result, err := u.outletRepo.UpdateStatus(ctx, id, newStatusID)
+	if err != nil {
+		err.Path = ".OutletUsecase->UpdateStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, err
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReferenceRepository initialize object from model Reference, to be used in database operation
+// on any simple id/name/status table
+type ReferenceRepository struct {
+	tableName        string
+	repository       data.GenericStorage
+	statusRepository data.GenericStorage
+}
+
+// NewReferenceRepository initialize service that provide connection to the reference table
+func NewReferenceRepository(tableName string, repository data.GenericStorage, statusRepository data.GenericStorage) ReferenceRepository {
+	return ReferenceRepository{tableName: tableName, repository: repository, statusRepository: statusRepository}
+}
+
+// FindAll is a function to get all Data
+func (s ReferenceRepository) FindAll(ctx *gin.Context, params models.FindAllReferenceParams) ([]*models.Reference, *types.Error) {
+	data := []*models.Reference{}
+	bulks := []*models.ReferenceBulk{}
+
+	var err error
+
+	where := `true`
+
+	if params.FindAllParams.DataFinder != "" {
+		where = fmt.Sprintf("%s AND %s", where, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where = fmt.Sprintf("%s AND %s.%s", where, s.tableName, params.FindAllParams.StatusID)
+	}
+
+	if params.FindAllParams.SortBy != "" {
+		where = fmt.Sprintf("%s ORDER BY %s", where, params.FindAllParams.SortBy)
+	}
+
+	if params.FindAllParams.Page > 0 && params.FindAllParams.Size > 0 {
+		where = fmt.Sprintf(`%s LIMIT :limit OFFSET :offset`, where)
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    %[1]s.id, %[1]s.name,
+    %[1]s.status_id, status.name status_name
+  FROM %[1]s
+  JOIN status ON %[1]s.status_id = status.id
+  WHERE %[2]s
+  `, s.tableName, where)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"limit":     params.FindAllParams.Size,
+		"offset":    ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+		"status_id": params.FindAllParams.StatusID,
+	})
+
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ReferenceStorage->FindAll()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		obj := &models.Reference{
+			ID:       v.ID,
+			Name:     v.Name,
+			StatusID: v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+		}
+
+		data = append(data, obj)
+	}
+
+	return data, nil
+}
+
+// Find is a function to get by ID
+func (s ReferenceRepository) Find(ctx *gin.Context, id string) (*models.Reference, *types.Error) {
+	result := models.Reference{}
+	bulks := []*models.ReferenceBulk{}
+	var err error
+
+	query := fmt.Sprintf(`
+  SELECT %[1]s.id, %[1]s.name,
+  %[1]s.status_id, status.name status_name
+  FROM %[1]s
+  JOIN status ON %[1]s.status_id = status.id
+  WHERE %[1]s.id = :id`, s.tableName)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"id": id,
+	})
+
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ReferenceStorage->Find()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(bulks) > 0 {
+		v := bulks[0]
+		result = models.Reference{
+			ID:       v.ID,
+			Name:     v.Name,
+			StatusID: v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+		}
+	} else {
+		return nil, &types.Error{
+			Path:       ".ReferenceStorage->Find()",
+			Message:    "Data Not Found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &result, nil
+}
+
+// Create is a function to get by ID
+func (s ReferenceRepository) Create(ctx *gin.Context, obj *models.Reference) (*models.Reference, *types.Error) {
+	data := models.Reference{}
+	_, err := s.repository.Insert(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ReferenceStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ReferenceStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+// Update is a function to get by ID
+func (s ReferenceRepository) Update(ctx *gin.Context, obj *models.Reference) (*models.Reference, *types.Error) {
+	data := models.Reference{}
+	err := s.repository.Update(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ReferenceStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ReferenceStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+// FindStatus is a function to get by ID
+func (s ReferenceRepository) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	businessStatus := []*models.Status{}
+
+	err := s.statusRepository.Where(ctx, &businessStatus, "1=1", map[string]interface{}{})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ReferenceStorage->FindStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return businessStatus, nil
+}
+
+// UpdateStatus is a function to get by ID
+func (s ReferenceRepository) UpdateStatus(ctx *gin.Context, id string, statusID string) (*models.Reference, *types.Error) {
+	data := models.Reference{}
+	err := s.repository.UpdateStatus(ctx, id, statusID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ReferenceStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, id)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ReferenceStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &data, nil
+}
@@ -0,0 +1,20 @@
+package reference
+
+import (
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Usecase is the contract between Repository and usecase
+type Usecase interface {
+	FindAll(*gin.Context, models.FindAllReferenceParams) ([]*models.Reference, *types.Error)
+	Find(*gin.Context, string) (*models.Reference, *types.Error)
+	Count(*gin.Context, models.FindAllReferenceParams) (int, *types.Error)
+	Create(*gin.Context, models.Reference) (*models.Reference, *types.Error)
+	Update(*gin.Context, string, models.Reference) (*models.Reference, *types.Error)
+
+	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	UpdateStatus(*gin.Context, string, string) (*models.Reference, *types.Error)
+}
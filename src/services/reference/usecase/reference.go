@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"net/http"
+	"time"
+
+	"luxe-beb-go/library/types"
+	"luxe-beb-go/library/validator"
+	"luxe-beb-go/src/services/reference"
+
+	"luxe-beb-go/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type ReferenceUsecase struct {
+	referenceRepo  reference.Repository
+	contextTimeout time.Duration
+	db             *sqlx.DB
+}
+
+func NewReferenceUsecase(db *sqlx.DB, referenceRepo reference.Repository) reference.Usecase {
+	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+
+	return &ReferenceUsecase{
+		referenceRepo:  referenceRepo,
+		contextTimeout: timeoutContext,
+		db:             db,
+	}
+}
+
+func (u *ReferenceUsecase) FindAll(ctx *gin.Context, filterFindAllParams models.FindAllReferenceParams) ([]*models.Reference, *types.Error) {
+	result, err := u.referenceRepo.FindAll(ctx, filterFindAllParams)
+	if err != nil {
+		err.Path = ".ReferenceUsecase->FindAll()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *ReferenceUsecase) Find(ctx *gin.Context, id string) (*models.Reference, *types.Error) {
+	result, err := u.referenceRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".ReferenceUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *ReferenceUsecase) Count(ctx *gin.Context, filterFindAllParams models.FindAllReferenceParams) (int, *types.Error) {
+	result, err := u.referenceRepo.FindAll(ctx, filterFindAllParams)
+	if err != nil {
+		err.Path = ".ReferenceUsecase->Count()" + err.Path
+		return 0, err
+	}
+
+	return len(result), nil
+}
+
+func (u *ReferenceUsecase) Create(ctx *gin.Context, obj models.Reference) (*models.Reference, *types.Error) {
+	errValidation := validator.Shared().StructCtx(ctx, obj)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".ReferenceUsecase->Create()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	data := models.Reference{
+		ID:       uuid.New().String(),
+		Name:     obj.Name,
+		StatusID: models.DEFAULT_STATUS_ID,
+	}
+
+	result, err := u.referenceRepo.Create(ctx, &data)
+	if err != nil {
+		err.Path = ".ReferenceUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *ReferenceUsecase) Update(ctx *gin.Context, id string, obj models.Reference) (*models.Reference, *types.Error) {
+	errValidation := validator.Shared().StructCtx(ctx, obj)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".ReferenceUsecase->Update()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	data, err := u.referenceRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".ReferenceUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	data.Name = obj.Name
+
+	result, err := u.referenceRepo.Update(ctx, data)
+	if err != nil {
+		err.Path = ".ReferenceUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	return result, err
+}
+
+func (u *ReferenceUsecase) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	result, err := u.referenceRepo.FindStatus(ctx)
+	if err != nil {
+		err.Path = ".ReferenceUsecase->FindStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *ReferenceUsecase) UpdateStatus(ctx *gin.Context, id string, newStatusID string) (*models.Reference, *types.Error) {
+	result, err := u.referenceRepo.UpdateStatus(ctx, id, newStatusID)
+	if err != nil {
+		err.Path = ".ReferenceUsecase->UpdateStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, err
+}
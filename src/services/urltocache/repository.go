@@ -0,0 +1,10 @@
+package urltocache
+
+import (
+	"luxe-beb-go/library/client"
+)
+
+// Repository is the contract between Repository and usecase
+type Repository interface {
+	client.URLToCacheStorage
+}
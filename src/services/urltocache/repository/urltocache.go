@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+
+	"luxe-beb-go/library/client"
+	"luxe-beb-go/library/data"
+	"luxe-beb-go/library/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// URLToCacheRepository initialize object from model URLToCache, to be used in database operation
+type URLToCacheRepository struct {
+	repository data.GenericStorage
+}
+
+// NewURLToCacheRepository initialize service that provide connection to Database
+func NewURLToCacheRepository(repository data.GenericStorage) URLToCacheRepository {
+	return URLToCacheRepository{repository: repository}
+}
+
+// FindAll is a function to get all Data
+func (s URLToCacheRepository) FindAll(ctx *gin.Context, params *client.FindAllURLToCachesParams) ([]*client.URLToCache, *types.Error) {
+	result := []*client.URLToCache{}
+
+	where := `true`
+
+	if params.Search != "" {
+		where = fmt.Sprintf("%s AND (baseUrl LIKE :search OR clientName LIKE :search)", where)
+	}
+
+	if params.Method != "" {
+		where = fmt.Sprintf("%s AND method = :method", where)
+	}
+
+	if params.ClientName != "" {
+		where = fmt.Sprintf("%s AND clientName = :clientName", where)
+	}
+
+	if params.IsBlocked {
+		where = fmt.Sprintf("%s AND isBlocked = 1", where)
+	}
+
+	where = fmt.Sprintf("%s ORDER BY baseUrl, method", where)
+
+	if params.Page > 0 && params.Limit > 0 {
+		where = fmt.Sprintf(`%s LIMIT :limit OFFSET :offset`, where)
+	}
+
+	query := fmt.Sprintf(`
+  SELECT id, baseUrl, method, clientId, clientName, bufferedTime, isBlocked
+  FROM urls_to_cache
+  WHERE %s
+  `, where)
+
+	err := s.repository.SelectWithQuery(ctx, &result, query, map[string]interface{}{
+		"limit":      params.Limit,
+		"offset":     ((params.Page - 1) * params.Limit),
+		"search":     "%" + params.Search + "%",
+		"method":     params.Method,
+		"clientName": params.ClientName,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".URLToCacheStorage->FindAll()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return result, nil
+}
+
+// FindByID is a function to get by ID
+func (s URLToCacheRepository) FindByID(ctx *gin.Context, urlToCacheID int) (*client.URLToCache, *types.Error) {
+	result := client.URLToCache{}
+
+	err := s.repository.FindByID(ctx, &result, urlToCacheID)
+	if err != nil {
+		return nil, notFoundOr(".URLToCacheStorage->FindByID()", err)
+	}
+
+	return &result, nil
+}
+
+// FindByURL is a function to get the rule of the url, the longest base url the url starts with wins
+func (s URLToCacheRepository) FindByURL(ctx *gin.Context, url string, method string) (*client.URLToCache, *types.Error) {
+	result := client.URLToCache{}
+
+	err := s.repository.SelectFirstWithQuery(ctx, &result, `
+  SELECT id, baseUrl, method, clientId, clientName, bufferedTime, isBlocked
+  FROM urls_to_cache
+  WHERE method = :method AND LEFT(:url, CHAR_LENGTH(baseUrl)) = baseUrl
+  ORDER BY CHAR_LENGTH(baseUrl) DESC
+  LIMIT 1`, map[string]interface{}{
+		"url":    url,
+		"method": method,
+	})
+	if err != nil {
+		return nil, notFoundOr(".URLToCacheStorage->FindByURL()", err)
+	}
+
+	return &result, nil
+}
+
+// Insert is a function to insert a rule
+func (s URLToCacheRepository) Insert(ctx *gin.Context, urlToCache *client.URLToCache) (*client.URLToCache, *types.Error) {
+	_, err := s.repository.Insert(ctx, urlToCache)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".URLToCacheStorage->Insert()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return urlToCache, nil
+}
+
+// Update is a function to update a rule
+func (s URLToCacheRepository) Update(ctx *gin.Context, urlToCache *client.URLToCache) (*client.URLToCache, *types.Error) {
+	err := s.repository.Update(ctx, urlToCache)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".URLToCacheStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return urlToCache, nil
+}
+
+// Delete is a function to delete a rule
+func (s URLToCacheRepository) Delete(ctx *gin.Context, urlToCache *client.URLToCache) *types.Error {
+	err := s.repository.HardDelete(ctx, urlToCache.ID)
+	if err != nil {
+		return &types.Error{
+			Path:       ".URLToCacheStorage->Delete()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
+
+// notFoundOr keeps the message of data.ErrNotFound, the client cache service tells a missing rule by it
+func notFoundOr(path string, err error) *types.Error {
+	if err == data.ErrNotFound {
+		return &types.Error{
+			Path:       path,
+			Message:    data.ErrNotFound.Error(),
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &types.Error{
+		Path:       path,
+		Message:    err.Error(),
+		Error:      err,
+		StatusCode: http.StatusInternalServerError,
+		Type:       "mysql-error",
+	}
+}
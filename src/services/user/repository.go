@@ -16,4 +16,6 @@ type Repository interface {
 
 	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
 	UpdateStatus(*gin.Context, string, string) (*models.User, *types.Error)
+
+	FindBusinesses(*gin.Context, string) ([]string, *types.Error)
 }
@@ -63,7 +63,7 @@ func (s UserRepository) FindAll(ctx *gin.Context, params models.FindAllUserParam
 	query := fmt.Sprintf(`
   SELECT
     users.id, users.name, users.email, users.username, users.password,
-    users.status_id, status.name status_name
+    users.status_id, status.name status_name, users.version
   FROM users
   JOIN status ON users.status_id = status.id
   WHERE %s
@@ -99,6 +99,7 @@ func (s UserRepository) FindAll(ctx *gin.Context, params models.FindAllUserParam
 				ID:   v.StatusID,
 				Name: v.StatusName,
 			},
+			Version: v.Version,
 		}
 		data = append(data, obj)
 	}
@@ -114,7 +115,7 @@ func (s UserRepository) Find(ctx *gin.Context, id string) (*models.User, *types.
 	query := fmt.Sprintf(`
   SELECT
     users.id, users.name, users.email, users.username, users.password,
-    users.status_id, status.name status_name
+    users.status_id, status.name status_name, users.version
   FROM users
   JOIN status ON users.status_id = status.id
   WHERE users.id = :id`)
@@ -143,6 +144,7 @@ func (s UserRepository) Find(ctx *gin.Context, id string) (*models.User, *types.
 				ID:   v.StatusID,
 				Name: v.StatusName,
 			},
+			Version: v.Version,
 		}
 	} else {
 		return nil, &types.Error{
@@ -159,7 +161,7 @@ func (s UserRepository) Find(ctx *gin.Context, id string) (*models.User, *types.
 
 func (s UserRepository) Create(ctx *gin.Context, obj *models.User) (*models.User, *types.Error) {
 	data := models.User{}
-	result, err := s.repository.Insert(ctx, obj)
+	_, err := s.repository.Insert(ctx, obj)
 	if err != nil {
 		return nil, &types.Error{
 			Path:       ".UserStorage->Create()",
@@ -170,8 +172,7 @@ func (s UserRepository) Create(ctx *gin.Context, obj *models.User) (*models.User
 		}
 	}
 
-	lastID, _ := (*result).LastInsertId()
-	err = s.repository.FindByID(ctx, &data, lastID)
+	err = s.repository.FindByID(ctx, &data, obj.ID)
 	if err != nil {
 		return nil, &types.Error{
 			Path:       ".UserStorage->Create()",
@@ -185,18 +186,22 @@ func (s UserRepository) Create(ctx *gin.Context, obj *models.User) (*models.User
 }
 
 func (s UserRepository) Update(ctx *gin.Context, obj *models.User) (*models.User, *types.Error) {
-	data := models.User{}
 	err := s.repository.Update(ctx, obj)
 	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err == data.ErrVersionConflict {
+			statusCode = http.StatusPreconditionFailed
+		}
 		return nil, &types.Error{
 			Path:       ".UserStorage->Update()",
 			Message:    err.Error(),
 			Error:      err,
-			StatusCode: http.StatusInternalServerError,
+			StatusCode: statusCode,
 			Type:       "mysql-error",
 		}
 	}
 
+	data := models.User{}
 	err = s.repository.FindByID(ctx, &data, obj.ID)
 	if err != nil {
 		return nil, &types.Error{
@@ -227,19 +232,50 @@ func (s UserRepository) FindStatus(ctx *gin.Context) ([]*models.Status, *types.E
 	return status, nil
 }
 
+// FindBusinesses returns the id of the businesses the user is linked to
+func (s UserRepository) FindBusinesses(ctx *gin.Context, userID string) ([]string, *types.Error) {
+	businessIDs := []string{}
+
+	err := s.repository.SelectWithQuery(ctx, &businessIDs, `
+  SELECT business_id
+  FROM user_businesses
+  WHERE user_id = :user_id
+  ORDER BY business_id`, map[string]interface{}{"user_id": userID})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".UserStorage->FindBusinesses()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return businessIDs, nil
+}
+
 func (s UserRepository) UpdateStatus(ctx *gin.Context, id string, statusID string) (*models.User, *types.Error) {
-	data := models.User{}
 	err := s.repository.UpdateStatus(ctx, id, statusID)
 	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err {
+		case data.ErrNotFound:
+			statusCode = http.StatusNotFound
+		case data.ErrInvalidStatus:
+			statusCode = http.StatusUnprocessableEntity
+		case data.ErrOutOfScope:
+			statusCode = http.StatusForbidden
+		}
 		return nil, &types.Error{
 			Path:       ".UserStorage->UpdateStatus()",
 			Message:    err.Error(),
 			Error:      err,
-			StatusCode: http.StatusInternalServerError,
+			StatusCode: statusCode,
 			Type:       "mysql-error",
 		}
 	}
 
+	data := models.User{}
 	err = s.repository.FindByID(ctx, &data, id)
 	if err != nil {
 		return nil, &types.Error{
@@ -19,5 +19,5 @@ type Usecase interface {
 	UpdateStatus(*gin.Context, string, string) (*models.User, *types.Error)
 
 	// LOGIN
-	Login(*gin.Context, models.FindAllUserParams) (*models.UserLogin, *types.Error)
+	Login(*gin.Context, models.FindAllUserParams, string) (*models.UserLogin, *types.Error)
 }
@@ -1,14 +1,14 @@
 package usecase
 
 import (
+	"crypto/md5"
 	"fmt"
 	"net/http"
-	"reflect"
-	"strings"
 	"time"
 
 	"luxe-beb-go/library"
 	"luxe-beb-go/library/types"
+	"luxe-beb-go/library/validator"
 	"luxe-beb-go/src/services/user"
 
 	"luxe-beb-go/models"
@@ -18,20 +18,23 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/jmoiron/sqlx"
-	validator "gopkg.in/go-playground/validator.v9"
 )
 
 type UserUsecase struct {
 	userRepo       user.Repository
+	loginRepo      user.Repository
 	contextTimeout time.Duration
 	db             *sqlx.DB
 }
 
-func NewUserUsecase(db *sqlx.DB, userRepo user.Repository) user.Usecase {
+// NewUserUsecase takes the users scoped to the businesses of the context in userRepo,
+// loginRepo reads every user since nobody is logged in yet on login
+func NewUserUsecase(db *sqlx.DB, userRepo user.Repository, loginRepo user.Repository) user.Usecase {
 	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
 
 	return &UserUsecase{
 		userRepo:       userRepo,
+		loginRepo:      loginRepo,
 		contextTimeout: timeoutContext,
 		db:             db,
 	}
@@ -68,16 +71,7 @@ func (u *UserUsecase) Count(ctx *gin.Context, params models.FindAllUserParams) (
 }
 
 func (u *UserUsecase) Create(ctx *gin.Context, obj models.User) (*models.User, *types.Error) {
-	validate := validator.New()
-	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
-		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
-		if name == "-" {
-			return ""
-		}
-		return name
-	})
-
-	errValidation := validate.Struct(obj)
+	errValidation := validator.Shared().StructCtx(ctx, obj)
 	if errValidation != nil {
 		return nil, &types.Error{
 			Path:       ".UserUsecase->Create()",
@@ -93,7 +87,7 @@ func (u *UserUsecase) Create(ctx *gin.Context, obj models.User) (*models.User, *
 		Name:     obj.Name,
 		Email:    obj.Email,
 		Username: obj.Username,
-		Password: obj.Password,
+		Password: fmt.Sprintf("%x", md5.Sum([]byte(obj.Password))),
 		StatusID: models.DEFAULT_STATUS_ID,
 	}
 
@@ -107,16 +101,10 @@ func (u *UserUsecase) Create(ctx *gin.Context, obj models.User) (*models.User, *
 }
 
 func (u *UserUsecase) Update(ctx *gin.Context, id string, obj models.User) (*models.User, *types.Error) {
-	validate := validator.New()
-	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
-		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
-		if name == "-" {
-			return ""
-		}
-		return name
-	})
+	obj.ID = id
 
-	errValidation := validate.Struct(obj)
+	// password is changed through its own flow, so it is not part of the update
+	errValidation := validator.Shared().StructExceptCtx(ctx, obj, "Password")
 	if errValidation != nil {
 		return nil, &types.Error{
 			Path:       ".UserUsecase->Update()",
@@ -137,6 +125,9 @@ func (u *UserUsecase) Update(ctx *gin.Context, id string, obj models.User) (*mod
 	data.Email = obj.Email
 	data.Username = obj.Username
 
+	// the row is only updated when it is still at the version the caller read
+	data.Version = obj.Version
+
 	result, err := u.userRepo.Update(ctx, data)
 	if err != nil {
 		err.Path = ".UserUsecase->Update()" + err.Path
@@ -168,17 +159,8 @@ func (u *UserUsecase) UpdateStatus(ctx *gin.Context, id string, newStatusID stri
 
 // LOGIN
 
-func (u *UserUsecase) Login(ctx *gin.Context, params models.FindAllUserParams) (*models.UserLogin, *types.Error) {
-	validate := validator.New()
-	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
-		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
-		if name == "-" {
-			return ""
-		}
-		return name
-	})
-
-	errValidation := validate.Struct(params)
+func (u *UserUsecase) Login(ctx *gin.Context, params models.FindAllUserParams, businessID string) (*models.UserLogin, *types.Error) {
+	errValidation := validator.Shared().StructCtx(ctx, params)
 	if errValidation != nil {
 		return nil, &types.Error{
 			Path:       ".UserService->Login()",
@@ -189,7 +171,7 @@ func (u *UserUsecase) Login(ctx *gin.Context, params models.FindAllUserParams) (
 		}
 	}
 
-	result, err := u.userRepo.FindAll(ctx, params)
+	result, err := u.loginRepo.FindAll(ctx, params)
 	if err != nil {
 		err.Path = ".UserService->Login()" + err.Path
 		return nil, err
@@ -204,7 +186,27 @@ func (u *UserUsecase) Login(ctx *gin.Context, params models.FindAllUserParams) (
 		return nil, &err
 	}
 
-	credentials := library.Credential{ID: result[0].ID, Username: result[0].Username, Type: "Web"}
+	businessIDs, err := u.loginRepo.FindBusinesses(ctx, result[0].ID)
+	if err != nil {
+		err.Path = ".UserService->Login()" + err.Path
+		return nil, err
+	}
+
+	// the business picked on login is the one the data written without business belongs to
+	if businessID == "" && len(businessIDs) > 0 {
+		businessID = businessIDs[0]
+	}
+	if businessID != "" && !containsBusiness(businessIDs, businessID) {
+		return nil, &types.Error{
+			Path:       ".UserService->Login()",
+			Message:    "business tidak dapat diakses oleh user ini",
+			Error:      fmt.Errorf("Login Failed"),
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "authentication",
+		}
+	}
+
+	credentials := library.Credential{ID: result[0].ID, Username: result[0].Username, Type: "Web", BusinessID: businessID}
 
 	token, errorJwtSign := library.JwtSignString(credentials)
 	if errorJwtSign != nil {
@@ -221,9 +223,19 @@ func (u *UserUsecase) Login(ctx *gin.Context, params models.FindAllUserParams) (
 	userLogin.Name = result[0].Name
 	userLogin.Token = token
 	userLogin.Email = result[0].Email
+	userLogin.BusinessID = businessID
 	userLogin.StatusID = result[0].StatusID
 
 	return &userLogin, nil
 }
 
+func containsBusiness(businessIDs []string, businessID string) bool {
+	for _, id := range businessIDs {
+		if id == businessID {
+			return true
+		}
+	}
+	return false
+}
+
 // //